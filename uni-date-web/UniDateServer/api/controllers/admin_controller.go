@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminController 管理后台控制器接口
+type AdminController interface {
+	SearchUsers(c *gin.Context)
+	GetUser(c *gin.Context)
+	SetVerified(c *gin.Context)
+	SetVIP(c *gin.Context)
+	Suspend(c *gin.Context)
+	Ban(c *gin.Context)
+	Reinstate(c *gin.Context)
+	DeleteUser(c *gin.Context)
+	RestoreUser(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	GetRisk(c *gin.Context)
+}
+
+// adminController 管理后台控制器实现
+type adminController struct {
+	adminService services.AdminService
+	riskService  services.RiskService
+}
+
+// NewAdminController 创建管理后台控制器实例
+func NewAdminController(adminService services.AdminService, riskService services.RiskService) AdminController {
+	return &adminController{
+		adminService: adminService,
+		riskService:  riskService,
+	}
+}
+
+// 开关类请求结构
+type toggleRequest struct {
+	Value *bool `json:"value" binding:"required"`
+}
+
+// 暂停账号请求结构
+type suspendRequest struct {
+	Reason string    `json:"reason" binding:"required"`
+	Until  time.Time `json:"until" binding:"required"`
+}
+
+// 带原因的管理操作请求结构
+type reasonRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// SearchUsers 按账号、姓名、学校查询用户
+func (c *adminController) SearchUsers(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+	includeDeleted, _ := strconv.ParseBool(ctx.Query("includeDeleted"))
+
+	filter := repositories.UserSearchFilter{
+		Query:          ctx.Query("q"),
+		Account:        ctx.Query("account"),
+		Name:           ctx.Query("name"),
+		University:     ctx.Query("university"),
+		Status:         ctx.Query("status"),
+		IncludeDeleted: includeDeleted,
+	}
+
+	result, err := c.adminService.SearchUsers(ctx.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "查询用户失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetUser 查看完整用户记录
+func (c *adminController) GetUser(ctx *gin.Context) {
+	detail, err := c.adminService.GetUser(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, detail)
+}
+
+// GetRisk 查看用户当前的风险评估明细
+func (c *adminController) GetRisk(ctx *gin.Context) {
+	assessment, err := c.riskService.Assess(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assessment)
+}
+
+// SetVerified 设置用户认证状态
+func (c *adminController) SetVerified(ctx *gin.Context) {
+	var req toggleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := c.adminService.SetVerified(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"), *req.Value)
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, detail)
+}
+
+// SetVIP 开通或撤销赠送的 VIP
+func (c *adminController) SetVIP(ctx *gin.Context) {
+	var req toggleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := c.adminService.SetVIP(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"), *req.Value)
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, detail)
+}
+
+// Suspend 暂停用户账号
+func (c *adminController) Suspend(ctx *gin.Context) {
+	var req suspendRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := c.adminService.Suspend(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"), req.Reason, req.Until)
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, detail)
+}
+
+// Ban 封禁用户账号
+func (c *adminController) Ban(ctx *gin.Context) {
+	var req reasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := c.adminService.Ban(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"), req.Reason)
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, detail)
+}
+
+// Reinstate 解除暂停或封禁
+func (c *adminController) Reinstate(ctx *gin.Context) {
+	var req reasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := c.adminService.Reinstate(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"), req.Reason)
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, detail)
+}
+
+// DeleteUser 软删除用户
+func (c *adminController) DeleteUser(ctx *gin.Context) {
+	var req reasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.adminService.DeleteUser(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"), req.Reason); err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "用户已删除"})
+}
+
+// RestoreUser 恢复被删除的用户
+func (c *adminController) RestoreUser(ctx *gin.Context) {
+	detail, err := c.adminService.RestoreUser(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"))
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, detail)
+}
+
+// ResetPassword 重置用户密码，临时密码只在本次响应中返回
+func (c *adminController) ResetPassword(ctx *gin.Context) {
+	password, err := c.adminService.ResetPassword(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"))
+	if err != nil {
+		respondAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"password": password})
+}
+
+// respondAdminError 将管理操作的错误转换为响应
+func respondAdminError(ctx *gin.Context, err error) {
+	switch err {
+	case services.ErrUserNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+	case services.ErrCannotModerateSelf, services.ErrInvalidSuspension:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case services.ErrUserAlreadyDeleted, services.ErrUserNotDeleted, services.ErrPaidSubscriptionExist:
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "操作失败"})
+	}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditController 审计日志控制器接口
+type AuditController interface {
+	ListEvents(c *gin.Context)
+	VerifyChain(c *gin.Context)
+}
+
+// auditController 审计日志控制器实现
+type auditController struct {
+	auditService services.AuditService
+}
+
+// NewAuditController 创建审计日志控制器实例
+func NewAuditController(auditService services.AuditService) AuditController {
+	return &auditController{
+		auditService: auditService,
+	}
+}
+
+// ListEvents 按操作人、目标、类型和时间范围查询审计事件
+// from/to 为 RFC3339 格式
+func (c *auditController) ListEvents(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "50"))
+
+	filter := repositories.AuditFilter{
+		ActorID:  ctx.Query("actorId"),
+		TargetID: ctx.Query("targetId"),
+		Action:   ctx.Query("action"),
+	}
+	for param, dest := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := ctx.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的时间格式: " + param})
+			return
+		}
+		*dest = &t
+	}
+
+	result, err := c.auditService.List(ctx.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "查询审计日志失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// VerifyChain 校验审计日志哈希链是否完整
+func (c *auditController) VerifyChain(ctx *gin.Context) {
+	report, err := c.auditService.VerifyChain(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "校验审计日志失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// requestMeta 提取请求的客户端信息，用于审计记录
+func requestMeta(ctx *gin.Context) services.RequestMeta {
+	return services.RequestMeta{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+		RequestID: ctx.GetString("request_id"),
+	}
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// BlockController 拉黑控制器接口
+type BlockController interface {
+	Block(c *gin.Context)
+	Unblock(c *gin.Context)
+}
+
+// blockController 拉黑控制器实现
+type blockController struct {
+	blockService services.BlockService
+}
+
+// NewBlockController 创建拉黑控制器实例
+func NewBlockController(blockService services.BlockService) BlockController {
+	return &blockController{
+		blockService: blockService,
+	}
+}
+
+// Block 拉黑用户
+func (c *blockController) Block(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	if err := c.blockService.Block(ctx.Request.Context(), requestMeta(ctx), userID.(string), ctx.Param("id")); err != nil {
+		switch err {
+		case services.ErrCannotBlockSelf:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "不能拉黑自己"})
+		case services.ErrUserNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "拉黑失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "已拉黑"})
+}
+
+// Unblock 取消拉黑
+func (c *blockController) Unblock(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	if err := c.blockService.Unblock(ctx.Request.Context(), requestMeta(ctx), userID.(string), ctx.Param("id")); err != nil {
+		if err == services.ErrBlockNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "未拉黑该用户"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "取消拉黑失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "已取消拉黑"})
+}
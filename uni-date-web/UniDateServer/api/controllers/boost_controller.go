@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShijieLu222/uni-date-server/internal/payment"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// BoostController 资料加速控制器接口
+type BoostController interface {
+	Activate(c *gin.Context)
+	GetStatus(c *gin.Context)
+	ListHistory(c *gin.Context)
+	Checkout(c *gin.Context)
+}
+
+// boostController 资料加速控制器实现
+type boostController struct {
+	boostService services.BoostService
+}
+
+// NewBoostController 创建资料加速控制器实例
+func NewBoostController(boostService services.BoostService) BoostController {
+	return &boostController{
+		boostService: boostService,
+	}
+}
+
+// Activate 激活一次资料加速
+func (c *boostController) Activate(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	boost, err := c.boostService.Activate(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if respondEntitlementError(ctx, err) {
+			return
+		}
+		switch err {
+		case services.ErrBoostActive:
+			ctx.JSON(http.StatusConflict, gin.H{"error": "已有生效中的加速"})
+		case services.ErrUserNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "激活加速失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, boost)
+}
+
+// GetStatus 获取当前加速状态和可用次数
+func (c *boostController) GetStatus(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	status, err := c.boostService.GetStatus(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取加速状态失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+// ListHistory 获取加速记录及每次加速获得的曝光和喜欢
+func (c *boostController) ListHistory(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+
+	result, err := c.boostService.ListHistory(ctx.Request.Context(), userID.(string), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取加速记录失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Checkout 创建购买单次加速的支付会话
+func (c *boostController) Checkout(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	session, err := c.boostService.Checkout(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if err == payment.ErrUnknownPlan {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "暂不支持购买加速"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "创建支付失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, session)
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DataExportController 个人数据导出控制器接口
+type DataExportController interface {
+	Request(c *gin.Context)
+	Download(c *gin.Context)
+}
+
+// dataExportController 个人数据导出控制器实现
+type dataExportController struct {
+	dataExportService services.DataExportService
+}
+
+// NewDataExportController 创建个人数据导出控制器实例
+func NewDataExportController(dataExportService services.DataExportService) DataExportController {
+	return &dataExportController{
+		dataExportService: dataExportService,
+	}
+}
+
+// Request 申请导出个人数据，处理中返回 202，就绪时返回 200 和下载链接
+// 客户端可以重复调用以查询进度
+func (c *dataExportController) Request(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	status, err := c.dataExportService.Request(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "申请数据导出失败"})
+		return
+	}
+
+	if status.Status == models.DataExportStatusReady {
+		ctx.JSON(http.StatusOK, status)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, status)
+}
+
+// Download 通过签名链接下载导出文件，链接本身即凭证，不需要登录
+func (c *dataExportController) Download(ctx *gin.Context) {
+	expires, err := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": services.ErrExportLinkInvalid.Error()})
+		return
+	}
+
+	filePath, err := c.dataExportService.OpenDownload(ctx.Request.Context(), ctx.Param("id"), expires, ctx.Query("sig"))
+	if err != nil {
+		switch err {
+		case services.ErrExportLinkInvalid:
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case services.ErrExportNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "下载失败"})
+		}
+		return
+	}
+
+	ctx.FileAttachment(filePath, "unidate-data-export.zip")
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DiscoveryController 推荐控制器接口
+type DiscoveryController interface {
+	Feed(c *gin.Context)
+}
+
+// discoveryController 推荐控制器实现
+type discoveryController struct {
+	discoveryService services.DiscoveryService
+}
+
+// NewDiscoveryController 创建推荐控制器实例
+func NewDiscoveryController(discoveryService services.DiscoveryService) DiscoveryController {
+	return &discoveryController{
+		discoveryService: discoveryService,
+	}
+}
+
+// Feed 获取推荐的用户列表
+func (c *discoveryController) Feed(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	items, err := c.discoveryService.Feed(ctx.Request.Context(), userID.(string), limit)
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取推荐失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"items": items})
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// EntitlementController 会员权益控制器接口
+type EntitlementController interface {
+	GetEntitlements(c *gin.Context)
+}
+
+// entitlementController 会员权益控制器实现
+type entitlementController struct {
+	entitlementService services.EntitlementService
+}
+
+// NewEntitlementController 创建会员权益控制器实例
+func NewEntitlementController(entitlementService services.EntitlementService) EntitlementController {
+	return &entitlementController{
+		entitlementService: entitlementService,
+	}
+}
+
+// GetEntitlements 获取当前用户的等级、每日剩余次数和能力
+func (c *entitlementController) GetEntitlements(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	entitlements, err := c.entitlementService.GetEntitlements(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取会员权益失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entitlements)
+}
+
+// respondEntitlementError 将权益相关错误转换为结构化响应，前端据 code 展示升级引导
+// 不是权益错误时返回 false，由调用方继续处理
+func respondEntitlementError(ctx *gin.Context, err error) bool {
+	var quotaErr *services.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		ctx.JSON(http.StatusTooManyRequests, gin.H{
+			"error":   quotaErr.Error(),
+			"code":    "QUOTA_EXCEEDED",
+			"feature": quotaErr.Feature,
+			"tier":    quotaErr.Tier,
+			"limit":   quotaErr.Limit,
+			"resetAt": quotaErr.ResetAt,
+			"upgrade": quotaErr.Tier != services.TierVIP,
+		})
+		return true
+	}
+
+	var capabilityErr *services.CapabilityRequiredError
+	if errors.As(err, &capabilityErr) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error":      capabilityErr.Error(),
+			"code":       "VIP_REQUIRED",
+			"capability": capabilityErr.Capability,
+			"tier":       capabilityErr.Tier,
+			"upgrade":    true,
+		})
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// InteractionController 用户交互控制器接口
+type InteractionController interface {
+	Swipe(c *gin.Context)
+	Undo(c *gin.Context)
+	ListReceivedLikes(c *gin.Context)
+}
+
+// interactionController 用户交互控制器实现
+type interactionController struct {
+	interactionService services.InteractionService
+}
+
+// NewInteractionController 创建用户交互控制器实例
+func NewInteractionController(interactionService services.InteractionService) InteractionController {
+	return &interactionController{
+		interactionService: interactionService,
+	}
+}
+
+// 滑动请求结构
+type swipeRequest struct {
+	ToUserID string `json:"toUserId" binding:"required,uuid"`
+	Type     string `json:"type" binding:"required,oneof=like dislike"`
+}
+
+// Swipe 喜欢或跳过一个用户
+func (c *interactionController) Swipe(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req swipeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.interactionService.Swipe(ctx.Request.Context(), userID.(string), req.ToUserID, req.Type)
+	if err != nil {
+		if respondEntitlementError(ctx, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInteractionType, services.ErrCannotSwipeSelf:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case services.ErrUserNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		case services.ErrAlreadySwiped:
+			ctx.JSON(http.StatusConflict, gin.H{"error": "已经操作过该用户"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "操作失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// Undo 撤回最近一次滑动
+func (c *interactionController) Undo(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	result, err := c.interactionService.Undo(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if respondEntitlementError(ctx, err) {
+			return
+		}
+		switch err {
+		case services.ErrNothingToUndo:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "没有可撤回的操作"})
+		case services.ErrUndoWindowExpired, services.ErrMatchHasMessages, services.ErrMatchedByOther:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "撤回失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ListReceivedLikes 查看谁喜欢了我
+// VIP 返回完整资料，免费用户只返回总数和脱敏预览
+func (c *interactionController) ListReceivedLikes(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+
+	result, err := c.interactionService.ListReceivedLikes(ctx.Request.Context(), userID.(string), page, pageSize)
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取喜欢列表失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// MessageController 聊天消息控制器接口
+type MessageController interface {
+	Send(c *gin.Context)
+	List(c *gin.Context)
+}
+
+// messageController 聊天消息控制器实现
+type messageController struct {
+	messageService services.MessageService
+}
+
+// NewMessageController 创建聊天消息控制器实例
+func NewMessageController(messageService services.MessageService) MessageController {
+	return &messageController{
+		messageService: messageService,
+	}
+}
+
+// 发送消息请求结构
+type sendMessageRequest struct {
+	Content     string `json:"content" binding:"required,max=2000"`
+	ContentType string `json:"contentType" binding:"required,oneof=text image emoji"`
+}
+
+// Send 在匹配中发送消息
+func (c *messageController) Send(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req sendMessageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	message, err := c.messageService.Send(ctx.Request.Context(), userID.(string), ctx.Param("id"), req.Content, req.ContentType)
+	if err != nil {
+		if respondContentRejected(ctx, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidContentType:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case services.ErrMatchNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "匹配不存在"})
+		case services.ErrMatchInactive:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "发送消息失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, message)
+}
+
+// List 获取匹配中的消息，before 为 RFC3339 时间，用于向前翻页
+func (c *messageController) List(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "30"))
+	var before *time.Time
+	if value := ctx.Query("before"); value != "" {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的时间格式"})
+			return
+		}
+		before = &t
+	}
+
+	messages, err := c.messageService.List(ctx.Request.Context(), userID.(string), ctx.Param("id"), before, limit)
+	if err != nil {
+		if err == services.ErrMatchNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "匹配不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取消息失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"items": messages})
+}
+
+// respondContentRejected 内容命中拒绝规则时返回 422 和命中的规则
+// 不是内容过滤错误时返回 false，由调用方继续处理
+func respondContentRejected(ctx *gin.Context, err error) bool {
+	var rejected *services.ContentRejectedError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+	ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error": rejected.Error(),
+		"code":  "CONTENT_REJECTED",
+		"field": rejected.Field,
+		"rules": rejected.Rules,
+	})
+	return true
+}
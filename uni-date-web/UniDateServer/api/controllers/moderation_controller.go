@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationController 账号处置控制器接口
+type ModerationController interface {
+	SubmitAppeal(c *gin.Context)
+	ListAppeals(c *gin.Context)
+	ResolveAppeal(c *gin.Context)
+	ListQueue(c *gin.Context)
+	ResolveItem(c *gin.Context)
+}
+
+// moderationController 账号处置控制器实现
+type moderationController struct {
+	moderationService services.ModerationService
+}
+
+// NewModerationController 创建账号处置控制器实例
+func NewModerationController(moderationService services.ModerationService) ModerationController {
+	return &moderationController{
+		moderationService: moderationService,
+	}
+}
+
+// 提交申诉请求结构
+type appealRequest struct {
+	Message string `json:"message" binding:"required,max=2000"`
+}
+
+// 处理申诉请求结构
+type resolveAppealRequest struct {
+	Approve *bool  `json:"approve" binding:"required"`
+	Note    string `json:"note" binding:"max=2000"`
+}
+
+// 处理审核队列条目请求结构
+type resolveModerationRequest struct {
+	Status string `json:"status" binding:"required,oneof=actioned dismissed"`
+	Note   string `json:"note" binding:"max=2000"`
+}
+
+// SubmitAppeal 被暂停或封禁的用户提交申诉
+func (c *moderationController) SubmitAppeal(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req appealRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	appeal, err := c.moderationService.SubmitAppeal(ctx.Request.Context(), requestMeta(ctx), userID.(string), req.Message)
+	if err != nil {
+		switch err {
+		case services.ErrUserNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		case services.ErrAccountActive:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case services.ErrAppealPending:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "提交申诉失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, appeal)
+}
+
+// ListAppeals 管理员按状态查询申诉
+func (c *moderationController) ListAppeals(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+
+	result, err := c.moderationService.ListAppeals(ctx.Request.Context(), ctx.Query("status"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "查询申诉失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ResolveAppeal 管理员处理申诉，通过时恢复账号
+func (c *moderationController) ResolveAppeal(ctx *gin.Context) {
+	var req resolveAppealRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	appeal, err := c.moderationService.ResolveAppeal(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"), *req.Approve, req.Note)
+	if err != nil {
+		switch err {
+		case services.ErrAppealNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "申诉不存在"})
+		case services.ErrAppealResolved:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "处理申诉失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, appeal)
+}
+
+// ListQueue 管理员按状态和来源查询审核队列
+func (c *moderationController) ListQueue(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+
+	result, err := c.moderationService.ListQueue(ctx.Request.Context(), ctx.Query("status"), ctx.Query("source"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "查询审核队列失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ResolveItem 管理员处理审核队列条目
+func (c *moderationController) ResolveItem(ctx *gin.Context) {
+	var req resolveModerationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := c.moderationService.ResolveItem(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id"), req.Status, req.Note)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidModerationStatus:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case services.ErrModerationItemNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "审核条目不存在"})
+		case services.ErrModerationItemResolved:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "处理审核条目失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
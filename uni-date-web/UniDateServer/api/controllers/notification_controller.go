@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// NotificationController 通知控制器接口
+type NotificationController interface {
+	List(c *gin.Context)
+	Stream(c *gin.Context)
+	MarkRead(c *gin.Context)
+	Delete(c *gin.Context)
+	Shutdown()
+}
+
+// notificationController 通知控制器实现
+type notificationController struct {
+	notificationService services.NotificationService
+	config              *config.Config
+
+	// done 在服务器关闭时关闭，通知所有推送流退出
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// NewNotificationController 创建通知控制器实例
+func NewNotificationController(notificationService services.NotificationService, config *config.Config) NotificationController {
+	return &notificationController{
+		notificationService: notificationService,
+		config:              config,
+		done:                make(chan struct{}),
+	}
+}
+
+// 标记已读请求结构，ids 为空时标记全部
+type markReadRequest struct {
+	IDs []string `json:"ids" binding:"dive,uuid"`
+}
+
+// List 分页获取通知列表
+// 查询参数：page、pageSize、unread=true 仅返回未读
+func (c *notificationController) List(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+	unreadOnly, _ := strconv.ParseBool(ctx.DefaultQuery("unread", "false"))
+
+	result, err := c.notificationService.List(ctx.Request.Context(), userID.(string), unreadOnly, page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取通知失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Stream 通过 Server-Sent Events 实时推送新通知
+// 客户端重连时携带 Last-Event-ID，服务端会先补发断线期间的通知
+// 断线期间的通知超过补发上限时改为发送 resync 事件，客户端收到后应重新请求通知列表
+func (c *notificationController) Stream(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	// 先订阅再补发，避免两者之间产生的通知丢失
+	notifications, cancel, err := c.notificationService.Subscribe(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "订阅通知失败"})
+		return
+	}
+	defer cancel()
+
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("lastEventId")
+	}
+	// 通知 ID 都是 UUID，格式不对的事件 ID 不可能对应任何通知，按首次连接处理
+	if !isUUID(lastEventID) {
+		lastEventID = ""
+	}
+	missed, resync, err := c.notificationService.ListSince(ctx.Request.Context(), userID.(string), lastEventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取通知失败"})
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	// 记录已补发的通知，避免订阅通道中重复推送
+	replayed := make(map[string]struct{}, len(missed))
+	for i := range missed {
+		writeNotificationEvent(ctx, &missed[i])
+		replayed[missed[i].ID] = struct{}{}
+	}
+	// 错过的通知太多，不逐条补发，通知客户端重新拉取通知列表
+	if resync {
+		ctx.Render(-1, sse.Event{Event: "resync", Data: ""})
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(c.config.Notification.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-c.done:
+			return false
+		case notification, ok := <-notifications:
+			if !ok {
+				return false
+			}
+			if _, dup := replayed[notification.ID]; dup {
+				return true
+			}
+			writeNotificationEvent(ctx, notification)
+			return true
+		case now := <-heartbeat.C:
+			ctx.Render(-1, sse.Event{Event: "ping", Data: now.Unix()})
+			return true
+		}
+	})
+}
+
+// Shutdown 结束所有推送流，服务器关闭时调用，否则长连接会一直占用到关闭超时
+func (c *notificationController) Shutdown() {
+	c.doneOnce.Do(func() { close(c.done) })
+}
+
+// MarkRead 批量标记通知为已读
+func (c *notificationController) MarkRead(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req markReadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := c.notificationService.MarkRead(ctx.Request.Context(), userID.(string), req.IDs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "标记已读失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// Delete 删除一条通知
+func (c *notificationController) Delete(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	// 不是 UUID 的 ID 不可能对应任何通知
+	id := ctx.Param("id")
+	if !isUUID(id) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "通知不存在"})
+		return
+	}
+
+	if err := c.notificationService.Delete(ctx.Request.Context(), userID.(string), id); err != nil {
+		if err == services.ErrNotificationNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "通知不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "删除通知失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "通知已删除"})
+}
+
+// writeNotificationEvent 以通知 ID 作为事件 ID 写出 SSE 事件
+func writeNotificationEvent(ctx *gin.Context, notification *models.Notification) {
+	ctx.Render(-1, sse.Event{
+		Id:    notification.ID,
+		Event: "notification",
+		Data:  notification,
+	})
+}
+
+// isUUID 判断字符串是否为 UUID，非法的 ID 传给数据库的 uuid 列会导致查询报错
+func isUUID(s string) bool {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	return ok && validate.Var(s, "required,uuid") == nil
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/presence"
+	"github.com/ShijieLu222/uni-date-server/internal/pubsub"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNotificationIDValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repos := memory.NewSet(memory.NewStore())
+	tracker := presence.NewMemoryTracker()
+	// 用户在线时不会走移动端推送
+	defer tracker.Connect("owner")()
+	notificationService := services.NewNotificationService(repos.Notifications, nil, pubsub.NewMemoryPubSub(), tracker, &config.Config{})
+	notification, err := notificationService.Notify(context.Background(), "owner", models.NotificationTypeSystem, "hello", "")
+	if err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+
+	controller := NewNotificationController(notificationService, &config.Config{})
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) { ctx.Set("user_id", "owner") })
+	router.POST("/notifications/read", controller.MarkRead)
+	router.DELETE("/notifications/:id", controller.Delete)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"标记已读包含非法 ID", http.MethodPost, "/notifications/read", `{"ids":["` + notification.ID + `","not-a-uuid"]}`, http.StatusBadRequest},
+		{"标记已读", http.MethodPost, "/notifications/read", `{"ids":["` + notification.ID + `"]}`, http.StatusOK},
+		{"标记全部已读", http.MethodPost, "/notifications/read", `{}`, http.StatusOK},
+		{"删除非法 ID", http.MethodDelete, "/notifications/not-a-uuid", "", http.StatusNotFound},
+		{"删除不存在的通知", http.MethodDelete, "/notifications/00000000-0000-4000-8000-000000000000", "", http.StatusNotFound},
+		{"删除通知", http.MethodDelete, "/notifications/" + notification.ID, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("期望状态码 %d，实际 %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestStreamEndsOnServerShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repos := memory.NewSet(memory.NewStore())
+	cfg := &config.Config{Notification: config.NotificationConfig{HeartbeatInterval: time.Minute}}
+	notificationService := services.NewNotificationService(repos.Notifications, nil, pubsub.NewMemoryPubSub(), presence.NewMemoryTracker(), cfg)
+	controller := NewNotificationController(notificationService, cfg)
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) { ctx.Set("user_id", "owner") })
+	router.GET("/notifications/stream", controller.Stream)
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.RegisterOnShutdown(controller.Shutdown)
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/notifications/stream")
+	if err != nil {
+		t.Fatalf("连接推送流失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("期望状态码 200，实际 %d", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := server.Config.Shutdown(ctx); err != nil {
+		t.Fatalf("关闭服务器时推送流没有退出: %v", err)
+	}
+	// 推送流结束后响应体读到结尾
+	reader := bufio.NewReader(resp.Body)
+	for {
+		if _, err := reader.ReadString('\n'); err != nil {
+			break
+		}
+	}
+}
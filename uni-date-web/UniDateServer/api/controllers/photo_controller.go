@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// 表单中除文件外其他字段预留的大小
+const multipartOverhead = 1 << 20
+
+// PhotoController 照片控制器接口
+type PhotoController interface {
+	Upload(c *gin.Context)
+	ListBlocklist(c *gin.Context)
+	AddToBlocklist(c *gin.Context)
+	RemoveFromBlocklist(c *gin.Context)
+}
+
+// photoController 照片控制器实现
+type photoController struct {
+	photoService services.PhotoService
+	config       *config.Config
+}
+
+// NewPhotoController 创建照片控制器实例
+func NewPhotoController(photoService services.PhotoService, config *config.Config) PhotoController {
+	return &photoController{
+		photoService: photoService,
+		config:       config,
+	}
+}
+
+// Upload 上传照片，表单字段为 photo，返回的 url 用于更新资料中的照片列表
+func (c *photoController) Upload(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	c.limitBody(ctx)
+	data, err := readFormFile(ctx, "photo")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "请选择要上传的照片"})
+		return
+	}
+
+	photo, err := c.photoService.Upload(ctx.Request.Context(), userID.(string), data)
+	if err != nil {
+		respondPhotoError(ctx, err, "上传照片失败")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, photo)
+}
+
+// ListBlocklist 管理员查询违规图片
+func (c *photoController) ListBlocklist(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+
+	result, err := c.photoService.ListBlocklist(ctx.Request.Context(), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "查询违规图片失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// AddToBlocklist 管理员添加违规图片
+// 表单字段：label 必填；photo 为图片文件，或 photoId 指定已上传的照片
+func (c *photoController) AddToBlocklist(ctx *gin.Context) {
+	c.limitBody(ctx)
+	label := ctx.PostForm("label")
+	photoID := ctx.PostForm("photoId")
+	if label == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "请填写违规类型"})
+		return
+	}
+
+	var data []byte
+	if photoID == "" {
+		var err error
+		data, err = readFormFile(ctx, "photo")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "请上传图片或指定照片"})
+			return
+		}
+	}
+
+	entry, err := c.photoService.AddToBlocklist(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), label, data, photoID)
+	if err != nil {
+		respondPhotoError(ctx, err, "添加违规图片失败")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entry)
+}
+
+// RemoveFromBlocklist 管理员删除违规图片
+func (c *photoController) RemoveFromBlocklist(ctx *gin.Context) {
+	if err := c.photoService.RemoveFromBlocklist(ctx.Request.Context(), requestMeta(ctx), ctx.GetString("user_id"), ctx.Param("id")); err != nil {
+		respondPhotoError(ctx, err, "删除违规图片失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "已删除"})
+}
+
+// limitBody 限制请求体大小，避免超大文件占用内存
+func (c *photoController) limitBody(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.config.Photo.MaxSize+multipartOverhead)
+}
+
+// readFormFile 读取表单中的文件内容
+func readFormFile(ctx *gin.Context, field string) ([]byte, error) {
+	header, err := ctx.FormFile(field)
+	if err != nil {
+		return nil, err
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// respondPhotoError 将照片服务错误映射为 HTTP 响应
+func respondPhotoError(ctx *gin.Context, err error, fallback string) {
+	switch err {
+	case services.ErrPhotoTooLarge, services.ErrPhotoDimensionsTooLarge:
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case services.ErrUnsupportedImage:
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case services.ErrPhotoNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case services.ErrBlocklistEntryNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
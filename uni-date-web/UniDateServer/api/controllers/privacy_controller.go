@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PrivacyController 隐私设置控制器接口
+type PrivacyController interface {
+	GetSettings(c *gin.Context)
+	UpdateSettings(c *gin.Context)
+}
+
+// privacyController 隐私设置控制器实现
+type privacyController struct {
+	privacyService services.PrivacyService
+}
+
+// NewPrivacyController 创建隐私设置控制器实例
+func NewPrivacyController(privacyService services.PrivacyService) PrivacyController {
+	return &privacyController{
+		privacyService: privacyService,
+	}
+}
+
+// GetSettings 获取隐私设置
+func (c *privacyController) GetSettings(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	settings, err := c.privacyService.GetSettings(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取隐私设置失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings 修改隐私设置，免费用户开启隐身模式返回 403 VIP_REQUIRED
+func (c *privacyController) UpdateSettings(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req services.UpdatePrivacyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := c.privacyService.UpdateSettings(ctx.Request.Context(), userID.(string), req)
+	if err != nil {
+		if respondEntitlementError(ctx, err) {
+			return
+		}
+		if errors.Is(err, services.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新隐私设置失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileController 公开资料控制器接口
+type ProfileController interface {
+	GetPublicProfile(c *gin.Context)
+}
+
+// profileController 公开资料控制器实现
+type profileController struct {
+	profileService services.ProfileService
+}
+
+// NewProfileController 创建公开资料控制器实例
+func NewProfileController(profileService services.ProfileService) ProfileController {
+	return &profileController{
+		profileService: profileService,
+	}
+}
+
+// GetPublicProfile 查看其他用户的公开资料
+func (c *profileController) GetPublicProfile(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	profile, err := c.profileService.GetPublicProfile(ctx.Request.Context(), userID.(string), ctx.Param("id"))
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取用户资料失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PushController 推送设备控制器接口
+type PushController interface {
+	RegisterDevice(c *gin.Context)
+	UnregisterDevice(c *gin.Context)
+	GetPreference(c *gin.Context)
+	UpdatePreference(c *gin.Context)
+}
+
+// pushController 推送设备控制器实现
+type pushController struct {
+	pushService services.PushService
+}
+
+// NewPushController 创建推送设备控制器实例
+func NewPushController(pushService services.PushService) PushController {
+	return &pushController{
+		pushService: pushService,
+	}
+}
+
+// 登记设备请求结构
+type registerDeviceRequest struct {
+	Token    string `json:"token" binding:"required,max=255"`
+	Platform string `json:"platform" binding:"required,oneof=ios android"`
+}
+
+// 推送偏好请求结构
+type pushPreferenceRequest struct {
+	MuteMatch   bool `json:"muteMatch"`
+	MuteMessage bool `json:"muteMessage"`
+	MuteLike    bool `json:"muteLike"`
+	MuteSystem  bool `json:"muteSystem"`
+}
+
+// RegisterDevice 登记推送设备
+func (c *pushController) RegisterDevice(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req registerDeviceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	device, err := c.pushService.RegisterDevice(ctx.Request.Context(), userID.(string), req.Token, req.Platform)
+	if err != nil {
+		if err == services.ErrInvalidPlatform {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "不支持的设备平台"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "登记设备失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, device)
+}
+
+// UnregisterDevice 注销推送设备
+func (c *pushController) UnregisterDevice(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	if err := c.pushService.UnregisterDevice(ctx.Request.Context(), userID.(string), ctx.Param("token")); err != nil {
+		if err == services.ErrDeviceNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "设备不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "注销设备失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "设备已注销"})
+}
+
+// GetPreference 获取推送偏好
+func (c *pushController) GetPreference(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	preference, err := c.pushService.GetPreference(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取推送偏好失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preference)
+}
+
+// UpdatePreference 更新推送偏好
+func (c *pushController) UpdatePreference(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req pushPreferenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preference := &models.PushPreference{
+		UserID:      userID.(string),
+		MuteMatch:   req.MuteMatch,
+		MuteMessage: req.MuteMessage,
+		MuteLike:    req.MuteLike,
+		MuteSystem:  req.MuteSystem,
+	}
+	if err := c.pushService.UpdatePreference(ctx.Request.Context(), preference); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新推送偏好失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preference)
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportController 举报控制器接口
+type ReportController interface {
+	Report(c *gin.Context)
+}
+
+// reportController 举报控制器实现
+type reportController struct {
+	reportService services.ReportService
+}
+
+// NewReportController 创建举报控制器实例
+func NewReportController(reportService services.ReportService) ReportController {
+	return &reportController{
+		reportService: reportService,
+	}
+}
+
+// 举报请求结构
+type reportRequest struct {
+	Reason string `json:"reason" binding:"required,oneof=fake harassment spam inappropriate underage other"`
+	Detail string `json:"detail" binding:"max=1000"`
+}
+
+// Report 举报用户
+func (c *reportController) Report(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req reportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := c.reportService.Report(ctx.Request.Context(), requestMeta(ctx), userID.(string), ctx.Param("id"), req.Reason, req.Detail)
+	if err != nil {
+		switch err {
+		case services.ErrCannotReportSelf:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "不能举报自己"})
+		case services.ErrUserNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "举报失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, report)
+}
@@ -0,0 +1,23 @@
+package controllers
+
+// Set 路由使用的全部控制器
+type Set struct {
+	User         UserController
+	Notification NotificationController
+	Push         PushController
+	Subscription SubscriptionController
+	Entitlement  EntitlementController
+	Interaction  InteractionController
+	Block        BlockController
+	Boost        BoostController
+	Discovery    DiscoveryController
+	Admin        AdminController
+	Audit        AuditController
+	Report       ReportController
+	Moderation   ModerationController
+	Message      MessageController
+	Photo        PhotoController
+	DataExport   DataExportController
+	Privacy      PrivacyController
+	Profile      ProfileController
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/ShijieLu222/uni-date-server/internal/payment"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// 回调请求体大小上限
+const maxWebhookBodySize = 1 << 20
+
+// SubscriptionController VIP 订阅控制器接口
+type SubscriptionController interface {
+	Checkout(c *gin.Context)
+	GetCurrent(c *gin.Context)
+	Cancel(c *gin.Context)
+	Webhook(c *gin.Context)
+}
+
+// subscriptionController VIP 订阅控制器实现
+type subscriptionController struct {
+	subscriptionService services.SubscriptionService
+}
+
+// NewSubscriptionController 创建订阅控制器实例
+func NewSubscriptionController(subscriptionService services.SubscriptionService) SubscriptionController {
+	return &subscriptionController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// 创建支付会话请求结构
+type checkoutRequest struct {
+	Plan string `json:"plan" binding:"required,oneof=monthly yearly lifetime"`
+}
+
+// Checkout 创建支付会话，返回支付跳转地址
+func (c *subscriptionController) Checkout(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req checkoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := c.subscriptionService.Checkout(ctx.Request.Context(), userID.(string), req.Plan)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidPlan, payment.ErrUnknownPlan:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的订阅套餐"})
+		case services.ErrAlreadySubscribed:
+			ctx.JSON(http.StatusConflict, gin.H{"error": "已有有效的订阅"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "创建支付失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, session)
+}
+
+// GetCurrent 获取当前有效的订阅
+func (c *subscriptionController) GetCurrent(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	subscription, err := c.subscriptionService.GetCurrent(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if err == services.ErrSubscriptionNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "没有有效的订阅"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取订阅失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscription)
+}
+
+// Cancel 取消自动续费
+func (c *subscriptionController) Cancel(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	subscription, err := c.subscriptionService.Cancel(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if err == services.ErrSubscriptionNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "没有可取消的订阅"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "取消订阅失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscription)
+}
+
+// Webhook 接收支付平台回调，签名校验基于原始请求体
+func (c *subscriptionController) Webhook(ctx *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "读取请求失败"})
+		return
+	}
+
+	if err := c.subscriptionService.HandleWebhook(ctx.Request.Context(), payload, ctx.GetHeader("Stripe-Signature")); err != nil {
+		switch err {
+		case payment.ErrInvalidSignature:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "签名校验失败"})
+			return
+		case payment.ErrWebhookNotConfigured:
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "支付回调未配置"})
+			return
+		case services.ErrInvalidPlan:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "回调缺少用户或套餐信息"})
+			return
+		}
+		// 返回 500 让支付平台稍后重试
+		log.Printf("处理支付回调失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "处理回调失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"received": true})
+}
@@ -1,8 +1,13 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/ShijieLu222/uni-date-server/internal/dto"
 	"github.com/ShijieLu222/uni-date-server/internal/models"
 	"github.com/ShijieLu222/uni-date-server/internal/services"
 	"github.com/gin-gonic/gin"
@@ -14,6 +19,8 @@ type UserController interface {
 	Login(c *gin.Context)
 	GetProfile(c *gin.Context)
 	UpdateProfile(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	DeleteAccount(c *gin.Context)
 	Logout(c *gin.Context)
 }
 
@@ -43,6 +50,17 @@ type loginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// 注销账号请求结构
+type deleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// 修改密码请求结构
+type changePasswordRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=6"`
+}
+
 // Register 处理用户注册请求
 func (c *userController) Register(ctx *gin.Context) {
 	var req registerRequest
@@ -62,8 +80,11 @@ func (c *userController) Register(ctx *gin.Context) {
 	}
 
 	// 调用服务注册
-	token, err := c.userService.Register(user)
+	token, err := c.userService.Register(ctx.Request.Context(), requestMeta(ctx), user)
 	if err != nil {
+		if respondContentRejected(ctx, err) {
+			return
+		}
 		if err == services.ErrAccountExists {
 			ctx.JSON(http.StatusConflict, gin.H{"error": "账号已存在"})
 			return
@@ -72,13 +93,10 @@ func (c *userController) Register(ctx *gin.Context) {
 		return
 	}
 
-	// 清除敏感信息
-	user.Password = ""
-
 	// 返回结果
 	ctx.JSON(http.StatusCreated, gin.H{
 		"token": token,
-		"user":  user,
+		"user":  dto.NewProfile(user),
 	})
 }
 
@@ -91,8 +109,18 @@ func (c *userController) Login(ctx *gin.Context) {
 	}
 
 	// 调用服务登录
-	token, user, err := c.userService.Login(req.Account, req.Password)
+	token, user, err := c.userService.Login(ctx.Request.Context(), requestMeta(ctx), req.Account, req.Password)
 	if err != nil {
+		var restricted *services.AccountRestrictedError
+		if errors.As(err, &restricted) {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"error":       restricted.Error(),
+				"code":        "ACCOUNT_RESTRICTED",
+				"account":     restricted,
+				"appealToken": token,
+			})
+			return
+		}
 		if err == services.ErrInvalidCredentials {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "账号或密码错误"})
 			return
@@ -104,11 +132,11 @@ func (c *userController) Login(ctx *gin.Context) {
 	// 返回结果
 	ctx.JSON(http.StatusOK, gin.H{
 		"token": token,
-		"user":  user,
+		"user":  dto.NewProfile(user),
 	})
 }
 
-// GetProfile 获取用户资料
+// GetProfile 获取本人的完整资料
 func (c *userController) GetProfile(ctx *gin.Context) {
 	// 从上下文中获取用户ID
 	userID, exists := ctx.Get("user_id")
@@ -118,7 +146,7 @@ func (c *userController) GetProfile(ctx *gin.Context) {
 	}
 
 	// 调用服务获取用户信息
-	user, err := c.userService.GetUserByID(userID.(string))
+	user, err := c.userService.GetUserByID(ctx.Request.Context(), userID.(string))
 	if err != nil {
 		if err == services.ErrUserNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
@@ -128,11 +156,13 @@ func (c *userController) GetProfile(ctx *gin.Context) {
 		return
 	}
 
-	// 返回结果
-	ctx.JSON(http.StatusOK, user)
+	// 返回结果，ETag 用于修改时的 If-Match 校验
+	ctx.Header("ETag", profileETag(user.Version))
+	ctx.JSON(http.StatusOK, dto.NewProfile(user))
 }
 
-// UpdateProfile 更新用户资料
+// UpdateProfile 修改本人资料，只更新传入的字段，包含不可修改或未知的字段时返回 400
+// 带 If-Match 时只有资料版本一致才会修改，否则返回 412
 func (c *userController) UpdateProfile(ctx *gin.Context) {
 	// 从上下文中获取用户ID
 	userID, exists := ctx.Get("user_id")
@@ -141,18 +171,40 @@ func (c *userController) UpdateProfile(ctx *gin.Context) {
 		return
 	}
 
-	// 解析请求体
-	var user models.User
-	if err := ctx.ShouldBindJSON(&user); err != nil {
+	// 解析请求体，拒绝未知字段，避免客户端误以为修改了认证、VIP 等字段
+	var req services.UpdateProfileRequest
+	decoder := json.NewDecoder(ctx.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 设置用户ID
-	user.ID = userID.(string)
+	version, ok := parseIfMatch(ctx.GetHeader("If-Match"))
+	if !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": services.ErrProfileModified.Error(), "code": "PROFILE_MODIFIED"})
+		return
+	}
 
 	// 调用服务更新用户信息
-	if err := c.userService.UpdateUserProfile(&user); err != nil {
+	user, err := c.userService.UpdateProfile(ctx.Request.Context(), requestMeta(ctx), userID.(string), version, req)
+	if err != nil {
+		if err == services.ErrProfileModified {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error(), "code": "PROFILE_MODIFIED"})
+			return
+		}
+		if respondContentRejected(ctx, err) {
+			return
+		}
+		var fieldErr *services.ProfileFieldError
+		if errors.As(err, &fieldErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": fieldErr.Error(),
+				"code":  "INVALID_FIELD",
+				"field": fieldErr.Field,
+			})
+			return
+		}
 		if err == services.ErrUserNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
 			return
@@ -162,7 +214,37 @@ func (c *userController) UpdateProfile(ctx *gin.Context) {
 	}
 
 	// 返回结果
-	ctx.JSON(http.StatusOK, gin.H{"message": "用户信息更新成功"})
+	ctx.Header("ETag", profileETag(user.Version))
+	ctx.JSON(http.StatusOK, dto.NewProfile(user))
+}
+
+// ChangePassword 修改密码
+func (c *userController) ChangePassword(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.ChangePassword(ctx.Request.Context(), requestMeta(ctx), userID.(string), req.OldPassword, req.NewPassword); err != nil {
+		switch err {
+		case services.ErrWrongPassword:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "原密码错误"})
+		case services.ErrUserNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "修改密码失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "密码修改成功"})
 }
 
 // Logout 处理用户登出请求
@@ -171,3 +253,60 @@ func (c *userController) Logout(ctx *gin.Context) {
 	// 本方法主要是提供API一致性
 	ctx.JSON(http.StatusOK, gin.H{"message": "登出成功"})
 }
+
+// DeleteAccount 注销账号，宽限期内重新登录可以撤销
+func (c *userController) DeleteAccount(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+
+	var req deleteAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	purgeAfter, err := c.userService.DeleteAccount(ctx.Request.Context(), requestMeta(ctx), userID.(string), req.Password)
+	if err != nil {
+		switch err {
+		case services.ErrWrongPassword:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "密码错误"})
+		case services.ErrUserNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "注销账号失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":    "账号已注销，在此之前重新登录可以撤销",
+		"purgeAfter": purgeAfter,
+	})
+}
+
+// profileETag 由资料版本号生成 ETag
+func profileETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// parseIfMatch 解析 If-Match 请求头中的资料版本号
+// 未传或为 * 时返回 0 表示不校验；无法解析时第二个返回值为 false
+func parseIfMatch(header string) (int, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, false
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
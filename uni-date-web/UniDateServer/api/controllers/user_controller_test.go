@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/contentfilter"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		version int
+		ok      bool
+	}{
+		{"", 0, true},
+		{"*", 0, true},
+		{"  ", 0, true},
+		{`"3"`, 3, true},
+		{` "3" `, 3, true},
+		{`W/"3"`, 3, true},
+		{"3", 0, false},
+		{`"abc"`, 0, false},
+		{`"0"`, 0, false},
+		{`"-1"`, 0, false},
+		{`"3`, 0, false},
+		{`"3", "4"`, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			version, ok := parseIfMatch(tt.header)
+			if version != tt.version || ok != tt.ok {
+				t.Fatalf("期望 %d %v，实际 %d %v", tt.version, tt.ok, version, ok)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repos := memory.NewSet(memory.NewStore())
+	cfg := &config.Config{Profile: config.ProfileConfig{NameMinLength: 1, NameMaxLength: 20}}
+	auditService := services.NewAuditService(repos.Audit)
+	moderationService := services.NewModerationService(repos.Users, repos.Appeals, repos.Moderation, contentfilter.New(), auditService)
+	userService := services.NewUserService(repos.Users, repos.Photos, moderationService, nil, auditService, cfg)
+
+	user := &models.User{Name: "user", Account: "user", Password: "password", University: "North University"}
+	if err := repos.Users.Create(context.Background(), user); err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	controller := NewUserController(userService)
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) { ctx.Set("user_id", user.ID) })
+	router.PATCH("/profile", controller.UpdateProfile)
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		setup   func()
+		status  int
+		etag    string
+	}{
+		{"版本一致", `"1"`, nil, http.StatusOK, `"2"`},
+		{"版本已过期", `"1"`, nil, http.StatusPreconditionFailed, ""},
+		{"弱 ETag", `W/"2"`, nil, http.StatusOK, `"3"`},
+		{"无法解析", "abc", nil, http.StatusPreconditionFailed, ""},
+		{"不校验版本", "", nil, http.StatusOK, `"4"`},
+		{"管理员修改后版本过期", `"4"`, func() {
+			if err := repos.Users.UpdateColumns(context.Background(), user.ID, map[string]interface{}{"is_verified": true}); err != nil {
+				t.Fatalf("修改认证状态失败: %v", err)
+			}
+		}, http.StatusPreconditionFailed, ""},
+		{"使用新的版本", `"5"`, nil, http.StatusOK, `"6"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			req := httptest.NewRequest(http.MethodPatch, "/profile", strings.NewReader(`{"name":"`+tt.name+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("期望状态码 %d，实际 %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if etag := w.Header().Get("ETag"); etag != tt.etag {
+				t.Fatalf("期望 ETag %s，实际 %s", tt.etag, etag)
+			}
+			if tt.status == http.StatusPreconditionFailed {
+				var body struct {
+					Code string `json:"code"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != "PROFILE_MODIFIED" {
+					t.Fatalf("期望错误码 PROFILE_MODIFIED，实际 %s", w.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -1,16 +1,20 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 )
 
 // AuthMiddleware JWT认证中间件
-func AuthMiddleware(config *config.Config) gin.HandlerFunc {
+// 令牌有效时还会检查账号状态，被暂停或封禁的账号即使令牌未过期也会被拒绝
+// moderationService 为 nil 时不检查账号状态，仅用于允许被限制账号访问的接口（如申诉）
+func AuthMiddleware(config *config.Config, moderationService services.ModerationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 从 Authorization 头获取 token
 		authHeader := c.GetHeader("Authorization")
@@ -49,6 +53,21 @@ func AuthMiddleware(config *config.Config) gin.HandlerFunc {
 		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 			// 将用户 ID 设置到上下文中
 			userID, _ := claims["user_id"].(string)
+			scope, _ := claims["scope"].(string)
+
+			if moderationService != nil {
+				// 带范围的令牌只能访问对应的接口
+				if scope != "" {
+					c.JSON(http.StatusForbidden, gin.H{"error": "令牌无权访问该接口"})
+					c.Abort()
+					return
+				}
+				if err := moderationService.CheckAccountStatus(c.Request.Context(), userID); err != nil {
+					respondAccountStatusError(c, err)
+					return
+				}
+			}
+
 			c.Set("user_id", userID)
 			c.Next()
 		} else {
@@ -57,3 +76,54 @@ func AuthMiddleware(config *config.Config) gin.HandlerFunc {
 		}
 	}
 }
+
+// respondAccountStatusError 账号状态检查失败时中止请求
+func respondAccountStatusError(c *gin.Context, err error) {
+	var restricted *services.AccountRestrictedError
+	switch {
+	case errors.As(err, &restricted):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   restricted.Error(),
+			"code":    "ACCOUNT_RESTRICTED",
+			"account": restricted,
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户不存在"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "账号状态校验失败"})
+	}
+	c.Abort()
+}
+
+// QueryTokenMiddleware 允许通过 token 查询参数传递令牌
+// 浏览器的 EventSource 无法设置请求头，仅用于 SSE 等流式接口，需放在 AuthMiddleware 之前
+func QueryTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
+
+// AdminMiddleware 要求当前用户为管理员，需放在 AuthMiddleware 之后
+// 每次请求都查询角色，撤销管理员权限后立即生效
+func AdminMiddleware(adminService services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		isAdmin, err := adminService.IsAdmin(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "权限校验失败"})
+			c.Abort()
+			return
+		}
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "需要管理员权限"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
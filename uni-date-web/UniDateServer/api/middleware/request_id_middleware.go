@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader 请求 ID 请求头/响应头
+const RequestIDHeader = "X-Request-ID"
+
+// 客户端传入的请求 ID 长度上限，超出时重新生成
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware 为每个请求分配请求 ID，客户端已携带时沿用
+// 请求 ID 写入上下文的 request_id 并在响应头中返回，便于串联日志和审计记录
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			buf := make([]byte, 16)
+			rand.Read(buf)
+			requestID = hex.EncodeToString(buf)
+		}
+		c.Set("request_id", requestID)
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
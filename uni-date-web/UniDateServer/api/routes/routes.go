@@ -4,13 +4,17 @@ import (
 	"github.com/ShijieLu222/uni-date-server/api/controllers"
 	"github.com/ShijieLu222/uni-date-server/api/middleware"
 	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 // SetupRoutes 设置API路由
-func SetupRoutes(r *gin.Engine, userController controllers.UserController, config *config.Config) {
+func SetupRoutes(r *gin.Engine, c controllers.Set, adminService services.AdminService, moderationService services.ModerationService, config *config.Config) {
 	// 添加CORS中间件
-	r.Use(middleware.CorsMiddleware())
+	r.Use(middleware.CorsMiddleware(), middleware.RequestIDMiddleware())
+
+	// 上传的照片
+	r.Static(config.Photo.BaseURL, config.Photo.StorageDir)
 
 	// API 路由组
 	api := r.Group("/api")
@@ -18,19 +22,137 @@ func SetupRoutes(r *gin.Engine, userController controllers.UserController, confi
 	// 认证路由
 	auth := api.Group("/auth")
 	{
-		auth.POST("/register", userController.Register)
-		auth.POST("/login", userController.Login)
-		auth.POST("/logout", userController.Logout)
+		auth.POST("/register", c.User.Register)
+		auth.POST("/login", c.User.Login)
+		auth.POST("/logout", c.User.Logout)
 	}
 
 	// 用户路由（需要认证）
 	user := api.Group("/user")
-	user.Use(middleware.AuthMiddleware(config))
+	user.Use(middleware.AuthMiddleware(config, moderationService))
+	{
+		user.GET("/profile", c.User.GetProfile)
+		user.PATCH("/profile", c.User.UpdateProfile)
+		user.PUT("/profile", c.User.UpdateProfile) // 兼容旧客户端，语义与 PATCH 相同
+		user.PUT("/password", c.User.ChangePassword)
+		user.DELETE("", c.User.DeleteAccount)
+		user.POST("/photos", c.Photo.Upload)
+		user.GET("/export", c.DataExport.Request)
+		user.GET("/privacy", c.Privacy.GetSettings)
+		user.PUT("/privacy", c.Privacy.UpdateSettings)
+		user.POST("/devices", c.Push.RegisterDevice)
+		user.DELETE("/devices/:token", c.Push.UnregisterDevice)
+		user.GET("/push-preferences", c.Push.GetPreference)
+		user.PUT("/push-preferences", c.Push.UpdatePreference)
+		user.GET("/entitlements", c.Entitlement.GetEntitlements)
+	}
+
+	// 申诉路由：被暂停或封禁的账号也可以访问，允许使用登录时获得的申诉令牌
+	api.POST("/user/appeal", middleware.AuthMiddleware(config, nil), c.Moderation.SubmitAppeal)
+
+	// 数据导出下载，签名链接即凭证
+	api.GET("/exports/:id/download", c.DataExport.Download)
+
+	// 通知路由（需要认证）
+	// 只有 SSE 接口允许通过查询参数传递令牌，其余接口的令牌不应出现在 URL 和访问日志中
+	api.GET("/notifications/stream", middleware.QueryTokenMiddleware(), middleware.AuthMiddleware(config, moderationService), c.Notification.Stream)
+
+	notifications := api.Group("/notifications")
+	notifications.Use(middleware.AuthMiddleware(config, moderationService))
+	{
+		notifications.GET("", c.Notification.List)
+		notifications.POST("/read", c.Notification.MarkRead)
+		notifications.DELETE("/:id", c.Notification.Delete)
+	}
+
+	// 交互路由（需要认证）
+	interactions := api.Group("/interactions")
+	interactions.Use(middleware.AuthMiddleware(config, moderationService))
+	{
+		interactions.POST("", c.Interaction.Swipe)
+		interactions.POST("/undo", c.Interaction.Undo)
+	}
+
+	// 推荐路由（需要认证）
+	discover := api.Group("/discover")
+	discover.Use(middleware.AuthMiddleware(config, moderationService))
+	{
+		discover.GET("", c.Discovery.Feed)
+	}
+
+	// 聊天消息路由（需要认证）
+	matches := api.Group("/matches")
+	matches.Use(middleware.AuthMiddleware(config, moderationService))
 	{
-		user.GET("/profile", userController.GetProfile)
-		user.PUT("/profile", userController.UpdateProfile)
+		matches.GET("/:id/messages", c.Message.List)
+		matches.POST("/:id/messages", c.Message.Send)
 	}
 
+	// 喜欢路由（需要认证）
+	likes := api.Group("/likes")
+	likes.Use(middleware.AuthMiddleware(config, moderationService))
+	{
+		likes.GET("/received", c.Interaction.ListReceivedLikes)
+	}
+
+	// 其他用户相关路由（需要认证）
+	users := api.Group("/users")
+	users.Use(middleware.AuthMiddleware(config, moderationService))
+	{
+		users.GET("/:id", c.Profile.GetPublicProfile)
+		users.POST("/:id/block", c.Block.Block)
+		users.DELETE("/:id/block", c.Block.Unblock)
+		users.POST("/:id/report", c.Report.Report)
+	}
+
+	// VIP订阅路由（需要认证）
+	subscriptions := api.Group("/subscriptions")
+	subscriptions.Use(middleware.AuthMiddleware(config, moderationService))
+	{
+		subscriptions.POST("/checkout", c.Subscription.Checkout)
+		subscriptions.GET("/current", c.Subscription.GetCurrent)
+		subscriptions.POST("/cancel", c.Subscription.Cancel)
+	}
+
+	// 资料加速路由（需要认证）
+	boosts := api.Group("/boosts")
+	boosts.Use(middleware.AuthMiddleware(config, moderationService))
+	{
+		boosts.GET("", c.Boost.ListHistory)
+		boosts.GET("/current", c.Boost.GetStatus)
+		boosts.POST("/activate", c.Boost.Activate)
+		boosts.POST("/checkout", c.Boost.Checkout)
+	}
+
+	// 管理后台路由（需要管理员权限）
+	admin := api.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(config, moderationService), middleware.AdminMiddleware(adminService))
+	{
+		admin.GET("/users", c.Admin.SearchUsers)
+		admin.GET("/users/:id", c.Admin.GetUser)
+		admin.GET("/users/:id/risk", c.Admin.GetRisk)
+		admin.PUT("/users/:id/verified", c.Admin.SetVerified)
+		admin.PUT("/users/:id/vip", c.Admin.SetVIP)
+		admin.POST("/users/:id/suspend", c.Admin.Suspend)
+		admin.POST("/users/:id/ban", c.Admin.Ban)
+		admin.POST("/users/:id/reinstate", c.Admin.Reinstate)
+		admin.DELETE("/users/:id", c.Admin.DeleteUser)
+		admin.POST("/users/:id/restore", c.Admin.RestoreUser)
+		admin.POST("/users/:id/reset-password", c.Admin.ResetPassword)
+		admin.GET("/audit-events", c.Audit.ListEvents)
+		admin.GET("/audit-events/verify", c.Audit.VerifyChain)
+		admin.GET("/appeals", c.Moderation.ListAppeals)
+		admin.POST("/appeals/:id/resolve", c.Moderation.ResolveAppeal)
+		admin.GET("/moderation-queue", c.Moderation.ListQueue)
+		admin.POST("/moderation-queue/:id/resolve", c.Moderation.ResolveItem)
+		admin.GET("/photo-blocklist", c.Photo.ListBlocklist)
+		admin.POST("/photo-blocklist", c.Photo.AddToBlocklist)
+		admin.DELETE("/photo-blocklist/:id", c.Photo.RemoveFromBlocklist)
+	}
+
+	// 支付平台回调路由（通过签名校验，不走JWT认证）
+	api.POST("/webhooks/payment", c.Subscription.Webhook)
+
 	// 健康检查路由
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
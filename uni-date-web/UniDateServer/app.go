@@ -0,0 +1,251 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ShijieLu222/uni-date-server/api/controllers"
+	"github.com/ShijieLu222/uni-date-server/api/routes"
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/contentfilter"
+	"github.com/ShijieLu222/uni-date-server/internal/jobs"
+	"github.com/ShijieLu222/uni-date-server/internal/payment"
+	"github.com/ShijieLu222/uni-date-server/internal/presence"
+	"github.com/ShijieLu222/uni-date-server/internal/pubsub"
+	"github.com/ShijieLu222/uni-date-server/internal/push"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/db"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+	"github.com/ShijieLu222/uni-date-server/internal/risk"
+	"github.com/ShijieLu222/uni-date-server/internal/services"
+	"github.com/ShijieLu222/uni-date-server/internal/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// App 应用容器，持有配置、数据库等长生命周期的依赖，负责组装各层并在退出时按相反顺序释放
+// 使用内存存储时 DB 和 Migrator 为空
+type App struct {
+	Config       *config.Config
+	DB           *gorm.DB
+	Migrator     db.Migrator
+	Repositories repositories.Set
+	Router       *gin.Engine
+
+	closers     []func() error
+	shutdowners []func()
+}
+
+// NewApp 创建应用容器，只初始化存储，供 migrate 子命令在不启动服务的情况下使用
+func NewApp(cfg *config.Config) (*App, error) {
+	app := &App{Config: cfg}
+
+	switch cfg.Database.Driver {
+	case "memory":
+		log.Println("使用内存存储，数据在重启后丢失")
+		app.Repositories = memory.NewSet(memory.NewStore())
+		return app, nil
+	case "postgres":
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", cfg.Database.Driver)
+	}
+
+	database, err := db.InitDB(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("数据库连接失败: %w", err)
+	}
+	app.DB = database
+	app.onClose(func() error {
+		sqlDB, err := database.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	})
+
+	migrator, err := db.NewMigrator(database)
+	if err != nil {
+		app.Close()
+		return nil, fmt.Errorf("加载数据库迁移失败: %w", err)
+	}
+	app.Migrator = migrator
+	app.Repositories = repositories.NewSet(database, cfg.Database.TxMaxRetries)
+	return app, nil
+}
+
+// Build 组装存储库、服务、控制器和路由，并启动定时任务
+func (a *App) Build() error {
+	cfg := a.Config
+
+	// 初始化发布订阅和在线状态，多实例部署时使用 Redis 跨实例共享
+	var ps pubsub.PubSub
+	var tracker presence.Tracker
+	switch cfg.PubSub.Driver {
+	case "redis":
+		redisClient, err := db.InitRedis(cfg)
+		if err != nil {
+			return fmt.Errorf("Redis连接失败: %w", err)
+		}
+		a.onClose(redisClient.Close)
+		ps = pubsub.NewRedisPubSub(redisClient)
+		tracker = presence.NewRedisTracker(redisClient)
+	default:
+		ps = pubsub.NewMemoryPubSub()
+		tracker = presence.NewMemoryTracker()
+	}
+	a.onClose(ps.Close)
+	a.onClose(tracker.Close)
+
+	// 初始化移动端推送提供方
+	pushProviders := make(map[string]push.PushProvider)
+	if cfg.Push.APNs.Enabled {
+		provider, err := push.NewAPNsProvider(cfg.Push.APNs)
+		if err != nil {
+			return fmt.Errorf("初始化APNs失败: %w", err)
+		}
+		pushProviders[push.PlatformIOS] = provider
+	}
+	if cfg.Push.FCM.Enabled {
+		provider, err := push.NewFCMProvider(cfg.Push.FCM)
+		if err != nil {
+			return fmt.Errorf("初始化FCM失败: %w", err)
+		}
+		pushProviders[push.PlatformAndroid] = provider
+	}
+
+	// 初始化内容过滤规则
+	contentFilter, err := contentfilter.NewFromConfig(cfg.ContentFilter)
+	if err != nil {
+		return fmt.Errorf("加载内容过滤规则失败: %w", err)
+	}
+
+	// 初始化照片存储
+	photoStorage, err := storage.NewLocalStorage(cfg.Photo.StorageDir, cfg.Photo.BaseURL)
+	if err != nil {
+		return fmt.Errorf("初始化照片存储失败: %w", err)
+	}
+
+	// 初始化支付平台，配置了 API 密钥即视为启用，此时必须同时配置回调签名密钥
+	if cfg.Payment.SecretKey != "" && cfg.Payment.WebhookSecret == "" {
+		return fmt.Errorf("启用支付平台 %s 时必须配置回调签名密钥 payment.webhookSecret", cfg.Payment.Provider)
+	}
+	paymentProvider := payment.NewStripeProvider(cfg.Payment)
+
+	// 初始化存储库
+	repos := a.Repositories
+	userRepo := repos.Users
+	notificationRepo := repos.Notifications
+	pushRepo := repos.Push
+	subscriptionRepo := repos.Subscriptions
+	usageRepo := repos.Usage
+	interactionRepo := repos.Interactions
+	matchRepo := repos.Matches
+	blockRepo := repos.Blocks
+	messageRepo := repos.Messages
+	boostRepo := repos.Boosts
+	discoveryRepo := repos.Discovery
+	auditRepo := repos.Audit
+	reportRepo := repos.Reports
+	appealRepo := repos.Appeals
+	moderationRepo := repos.Moderation
+	riskRepo := repos.Risk
+	photoRepo := repos.Photos
+	dataExportRepo := repos.DataExports
+	accountPurgeRepo := repos.AccountPurge
+	privacyRepo := repos.Privacy
+	txManager := repos.Tx
+
+	// 初始化服务
+	auditService := services.NewAuditService(auditRepo)
+	moderationService := services.NewModerationService(userRepo, appealRepo, moderationRepo, contentFilter, auditService)
+	riskService := services.NewRiskService(riskRepo, userRepo, moderationService, auditService, risk.NewFromConfig(cfg.Risk), cfg)
+	userService := services.NewUserService(userRepo, photoRepo, moderationService, riskService, auditService, cfg)
+	pushService := services.NewPushService(pushRepo, pushProviders)
+	notificationService := services.NewNotificationService(notificationRepo, pushService, ps, tracker, cfg)
+	entitlementService := services.NewEntitlementService(userRepo, usageRepo, cfg)
+	boostService := services.NewBoostService(boostRepo, txManager, entitlementService, notificationService, paymentProvider, cfg)
+	subscriptionService := services.NewSubscriptionService(subscriptionRepo, paymentProvider, notificationService, boostService)
+	privacyService := services.NewPrivacyService(privacyRepo, userRepo, entitlementService, tracker)
+	interactionService := services.NewInteractionService(interactionRepo, txManager, userRepo, blockRepo, entitlementService, notificationService, boostService, riskService, privacyService, cfg)
+	discoveryService := services.NewDiscoveryService(discoveryRepo, userRepo, boostService, privacyService)
+	profileService := services.NewProfileService(userRepo, matchRepo, discoveryRepo, privacyService)
+	adminService := services.NewAdminService(userRepo, subscriptionRepo, matchRepo, auditService)
+	reportService := services.NewReportService(reportRepo, userRepo, moderationService, auditService)
+	photoService := services.NewPhotoService(photoRepo, moderationService, auditService, photoStorage, cfg)
+	dataExportService := services.NewDataExportService(dataExportRepo, userRepo, photoRepo, interactionRepo, matchRepo, messageRepo, notificationRepo, notificationService, photoStorage, cfg)
+	accountPurgeService := services.NewAccountPurgeService(accountPurgeRepo, photoRepo, auditService, photoStorage)
+	messageService := services.NewMessageService(messageRepo, matchRepo, userRepo, moderationService, riskService, notificationService, cfg)
+	blockService := services.NewBlockService(blockRepo, matchRepo, userRepo, auditService)
+
+	// 初始化控制器
+	ctrls := controllers.Set{
+		User:         controllers.NewUserController(userService),
+		Notification: controllers.NewNotificationController(notificationService, cfg),
+		Push:         controllers.NewPushController(pushService),
+		Subscription: controllers.NewSubscriptionController(subscriptionService),
+		Entitlement:  controllers.NewEntitlementController(entitlementService),
+		Interaction:  controllers.NewInteractionController(interactionService),
+		Block:        controllers.NewBlockController(blockService),
+		Boost:        controllers.NewBoostController(boostService),
+		Discovery:    controllers.NewDiscoveryController(discoveryService),
+		Admin:        controllers.NewAdminController(adminService, riskService),
+		Audit:        controllers.NewAuditController(auditService),
+		Report:       controllers.NewReportController(reportService),
+		Moderation:   controllers.NewModerationController(moderationService),
+		Message:      controllers.NewMessageController(messageService),
+		Photo:        controllers.NewPhotoController(photoService, cfg),
+		DataExport:   controllers.NewDataExportController(dataExportService),
+		Privacy:      controllers.NewPrivacyController(privacyService),
+		Profile:      controllers.NewProfileController(profileService),
+	}
+	a.onShutdown(ctrls.Notification.Shutdown)
+
+	// 启动定时任务
+	scheduler := jobs.NewScheduler()
+	a.onClose(func() error {
+		scheduler.Stop()
+		return nil
+	})
+	scheduler.Every("expire-subscriptions", cfg.Payment.ExpiryInterval, subscriptionService.ExpireSubscriptions)
+	scheduler.Every("reinstate-suspensions", cfg.Moderation.ReinstateInterval, moderationService.ReinstateExpired)
+	scheduler.Every("build-data-exports", cfg.Export.Interval, dataExportService.ProcessPending)
+	scheduler.Every("cleanup-data-exports", cfg.Export.Interval, dataExportService.Cleanup)
+	scheduler.Every("purge-deleted-accounts", cfg.Account.PurgeInterval, accountPurgeService.PurgeDue)
+
+	// 设置 Gin 路由
+	a.Router = gin.Default()
+	routes.SetupRoutes(a.Router, ctrls, adminService, moderationService, cfg)
+	return nil
+}
+
+// Shutdown 服务器开始关闭时调用，结束推送流等长连接，使关闭不必等到超时
+func (a *App) Shutdown() {
+	for _, shutdown := range a.shutdowners {
+		shutdown()
+	}
+}
+
+// Close 按注册的相反顺序释放资源：先停止定时任务，再关闭发布订阅、Redis，最后关闭数据库
+// 可以重复调用，返回所有释放失败的错误
+func (a *App) Close() error {
+	var errs []error
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i](); err != nil {
+			log.Printf("释放资源失败: %v", err)
+			errs = append(errs, err)
+		}
+	}
+	a.closers = nil
+	return errors.Join(errs...)
+}
+
+// onClose 注册退出时需要释放的资源
+func (a *App) onClose(closer func() error) {
+	a.closers = append(a.closers, closer)
+}
+
+// onShutdown 注册服务器关闭时需要结束的长连接
+func (a *App) onShutdown(shutdown func()) {
+	a.shutdowners = append(a.shutdowners, shutdown)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -8,10 +9,24 @@ import (
 
 // Config 应用配置结构体
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Redis    RedisConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	JWT           JWTConfig
+	Redis         RedisConfig
+	PubSub        PubSubConfig
+	Notification  NotificationConfig
+	Push          PushConfig
+	Payment       PaymentConfig
+	Entitlement   EntitlementConfig
+	Interaction   InteractionConfig
+	Boost         BoostConfig
+	Moderation    ModerationConfig
+	ContentFilter ContentFilterConfig
+	Risk          RiskConfig
+	Photo         PhotoConfig
+	Export        ExportConfig
+	Account       AccountConfig
+	Profile       ProfileConfig
 }
 
 // ServerConfig 服务器配置
@@ -23,12 +38,15 @@ type ServerConfig struct {
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
+	Driver       string // postgres 或 memory(内存存储，不持久化，用于本地开发和测试)
+	Host         string
+	Port         string
+	User         string
+	Password     string
+	DBName       string
+	SSLMode      string
+	QueryTimeout time.Duration // 单条 SQL 的最长执行时间，请求的上下文更早取消时以上下文为准，0 表示不限制
+	TxMaxRetries int           // 事务遇到序列化失败或死锁时的最大重试次数
 }
 
 // JWTConfig JWT 配置
@@ -45,12 +63,167 @@ type RedisConfig struct {
 	DB       int
 }
 
+// PubSubConfig 发布订阅配置
+type PubSubConfig struct {
+	Driver string // memory(单实例) 或 redis(多实例)
+}
+
+// NotificationConfig 通知推送配置
+type NotificationConfig struct {
+	HeartbeatInterval time.Duration // SSE 心跳间隔
+	ReplayLimit       int           // 断线重连时最多补发的通知数量，超过时通知客户端重新拉取
+}
+
+// PushConfig 移动端推送配置
+type PushConfig struct {
+	APNs APNsConfig
+	FCM  FCMConfig
+}
+
+// APNsConfig 苹果推送配置
+type APNsConfig struct {
+	Enabled    bool
+	KeyID      string
+	TeamID     string
+	KeyFile    string // .p8 私钥文件路径
+	Topic      string // 应用的 Bundle ID
+	Production bool
+}
+
+// FCMConfig Firebase 推送配置
+type FCMConfig struct {
+	Enabled         bool
+	CredentialsFile string // 服务账号 JSON 文件路径
+}
+
+// PaymentConfig 支付配置
+type PaymentConfig struct {
+	Provider         string // 目前支持 stripe
+	SecretKey        string
+	WebhookSecret    string        // 回调签名密钥
+	WebhookTolerance time.Duration // 回调时间戳允许的误差，防止重放
+	SuccessURL       string
+	CancelURL        string
+	MonthlyPriceID   string
+	YearlyPriceID    string
+	LifetimePriceID  string
+	BoostPriceID     string        // 单次资料加速价格ID
+	ExpiryInterval   time.Duration // 过期订阅检查间隔
+}
+
+// EntitlementConfig 会员权益配置
+type EntitlementConfig struct {
+	DefaultTimezone        string        // 用户未设置时区时使用
+	TimezoneChangeInterval time.Duration // 两次修改时区的最短间隔，防止通过切换时区反复重置每日配额
+	Free                   TierLimits
+	VIP                    TierLimits
+}
+
+// TierLimits 各等级的每日配额，-1 表示不限
+type TierLimits struct {
+	DailyLikes    int
+	DailyRewinds  int
+	DailyBoosts   int
+	SeeWhoLikedMe bool
+	Incognito     bool // 隐身浏览：只对自己喜欢过的人可见
+}
+
+// InteractionConfig 滑动交互配置
+type InteractionConfig struct {
+	UndoWindow time.Duration // 可以撤回最近一次滑动的时间窗口
+}
+
+// BoostConfig 资料加速配置
+type BoostConfig struct {
+	Duration time.Duration // 每次加速的持续时间
+}
+
+// ModerationConfig 账号处置配置
+type ModerationConfig struct {
+	ReinstateInterval time.Duration // 检查暂停到期账号的间隔
+}
+
+// ContentFilterConfig 内容过滤配置，处置动作为 flag、mask 或 block
+type ContentFilterConfig struct {
+	WordLists     []WordListConfig
+	URL           ContactRuleConfig
+	Phone         ContactRuleConfig
+	EarlyMessages int // 每个匹配中发送者的前 N 条消息视为会话早期，检测联系方式
+}
+
+// WordListConfig 敏感词表配置，Words 与 File 中的词合并使用
+type WordListConfig struct {
+	Name   string
+	Action string
+	Words  []string
+	File   string // 每行一个词，# 开头为注释
+}
+
+// ContactRuleConfig 联系方式检测规则配置
+type ContactRuleConfig struct {
+	Enabled bool
+	Action  string
+}
+
+// RiskConfig 风险评分配置，各规则得分累加后与阈值比较
+type RiskConfig struct {
+	ReviewScore            int // 达到后进入人工审核队列
+	ShadowLimitScore       int // 达到后限制曝光，对方看不到其资料、喜欢和消息
+	RegistrationsPerIP     RiskRuleConfig
+	DuplicateFirstMessages RiskRuleConfig
+	SwipeVelocity          RiskRuleConfig
+	DuplicatePhotos        RiskRuleConfig
+}
+
+// RiskRuleConfig 单条风险规则配置，Window 内的事件数达到 Threshold 时计 Score 分
+type RiskRuleConfig struct {
+	Window    time.Duration
+	Threshold int
+	Score     int
+}
+
+// PhotoConfig 照片上传配置，相似度按 64 位感知哈希的汉明距离判断
+type PhotoConfig struct {
+	StorageDir        string // 本地存储目录
+	BaseURL           string // 对外访问路径
+	MaxSize           int64  // 单张照片最大字节数
+	MaxDimension      int    // 宽或高的最大像素数
+	MaxPixels         int    // 宽高相乘的最大像素数，解码前检查，防止小文件声明超大尺寸耗尽内存
+	MaxDistance       int    // 与其他用户照片的距离不超过该值视为重复
+	BlocklistDistance int    // 与违规图片的距离不超过该值视为命中
+}
+
+// ExportConfig 个人数据导出配置
+type ExportConfig struct {
+	Dir      string        // 导出文件存放目录
+	LinkTTL  time.Duration // 下载链接有效期，过期后删除文件
+	Interval time.Duration // 处理待导出任务和清理过期文件的间隔
+	Timeout  time.Duration // 处理中的任务超过该时间视为失败
+}
+
+// AccountConfig 账号注销配置
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration // 注销后保留数据的时间，期间重新登录可以撤销
+	PurgeInterval       time.Duration // 清除到期账号数据的间隔
+}
+
+// ProfileConfig 用户资料校验配置
+type ProfileConfig struct {
+	NameMinLength int      // 昵称最少字符数
+	NameMaxLength int      // 昵称最多字符数
+	MinAge        int      // 最小年龄
+	MaxAge        int      // 最大年龄，用于拦截明显错误的生日
+	MaxInterests  int      // 兴趣标签数量上限
+	Majors        []string // 可选的专业，为空时不限制
+}
+
 // LoadConfig 从环境变量或配置文件中加载配置
 func LoadConfig() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("./config")
 	viper.AddConfigPath(".")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	// 设置默认值
@@ -80,12 +253,15 @@ func setDefaults() {
 	viper.SetDefault("server.writeTimeout", time.Second*10)
 
 	// 数据库默认配置
+	viper.SetDefault("database.driver", "postgres")
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", "5432")
 	viper.SetDefault("database.user", "postgres")
 	viper.SetDefault("database.password", "postgres")
 	viper.SetDefault("database.dbname", "unidate")
 	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.queryTimeout", time.Second*5)
+	viper.SetDefault("database.txMaxRetries", 3)
 
 	// JWT 默认配置
 	viper.SetDefault("jwt.secret", "your-secret-key")
@@ -96,4 +272,94 @@ func setDefaults() {
 	viper.SetDefault("redis.port", "6379")
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
+
+	// 发布订阅默认配置
+	viper.SetDefault("pubsub.driver", "memory")
+
+	// 通知默认配置
+	viper.SetDefault("notification.heartbeatInterval", time.Second*25)
+	viper.SetDefault("notification.replayLimit", 100)
+
+	// 推送默认配置
+	viper.SetDefault("push.apns.enabled", false)
+	viper.SetDefault("push.apns.production", false)
+	viper.SetDefault("push.fcm.enabled", false)
+
+	// 支付默认配置
+	viper.SetDefault("payment.provider", "stripe")
+	viper.SetDefault("payment.webhookTolerance", time.Minute*5)
+	viper.SetDefault("payment.successURL", "http://localhost:3000/vip/success")
+	viper.SetDefault("payment.cancelURL", "http://localhost:3000/vip")
+	viper.SetDefault("payment.expiryInterval", time.Minute*5)
+
+	// 会员权益默认配置
+	viper.SetDefault("entitlement.defaultTimezone", "Asia/Shanghai")
+	viper.SetDefault("entitlement.timezoneChangeInterval", "168h")
+	viper.SetDefault("entitlement.free.dailyLikes", 20)
+	viper.SetDefault("entitlement.free.dailyRewinds", 1)
+	viper.SetDefault("entitlement.free.dailyBoosts", 0)
+	viper.SetDefault("entitlement.free.seeWhoLikedMe", false)
+	viper.SetDefault("entitlement.free.incognito", false)
+	viper.SetDefault("entitlement.vip.dailyLikes", -1)
+	viper.SetDefault("entitlement.vip.dailyRewinds", -1)
+	viper.SetDefault("entitlement.vip.dailyBoosts", 1)
+	viper.SetDefault("entitlement.vip.seeWhoLikedMe", true)
+	viper.SetDefault("entitlement.vip.incognito", true)
+
+	// 滑动交互默认配置
+	viper.SetDefault("interaction.undoWindow", time.Minute*5)
+
+	// 资料加速默认配置
+	viper.SetDefault("boost.duration", time.Minute*30)
+
+	// 账号处置默认配置
+	viper.SetDefault("moderation.reinstateInterval", time.Minute)
+
+	// 内容过滤默认配置
+	viper.SetDefault("contentFilter.url.enabled", true)
+	viper.SetDefault("contentFilter.url.action", "block")
+	viper.SetDefault("contentFilter.phone.enabled", true)
+	viper.SetDefault("contentFilter.phone.action", "mask")
+	viper.SetDefault("contentFilter.earlyMessages", 5)
+
+	// 风险评分默认配置
+	viper.SetDefault("risk.reviewScore", 50)
+	viper.SetDefault("risk.shadowLimitScore", 80)
+	viper.SetDefault("risk.registrationsPerIP.window", time.Hour*24)
+	viper.SetDefault("risk.registrationsPerIP.threshold", 3)
+	viper.SetDefault("risk.registrationsPerIP.score", 40)
+	viper.SetDefault("risk.duplicateFirstMessages.window", time.Hour*24)
+	viper.SetDefault("risk.duplicateFirstMessages.threshold", 5)
+	viper.SetDefault("risk.duplicateFirstMessages.score", 50)
+	viper.SetDefault("risk.swipeVelocity.window", time.Minute)
+	viper.SetDefault("risk.swipeVelocity.threshold", 60)
+	viper.SetDefault("risk.swipeVelocity.score", 30)
+	viper.SetDefault("risk.duplicatePhotos.threshold", 1)
+	viper.SetDefault("risk.duplicatePhotos.score", 50)
+
+	// 照片上传默认配置
+	viper.SetDefault("photo.storageDir", "uploads/photos")
+	viper.SetDefault("photo.baseURL", "/uploads/photos")
+	viper.SetDefault("photo.maxSize", 10<<20)
+	viper.SetDefault("photo.maxDimension", 8000)
+	viper.SetDefault("photo.maxPixels", 40000000)
+	viper.SetDefault("photo.maxDistance", 8)
+	viper.SetDefault("photo.blocklistDistance", 10)
+
+	// 数据导出默认配置
+	viper.SetDefault("export.dir", "exports")
+	viper.SetDefault("export.linkTTL", time.Hour*24)
+	viper.SetDefault("export.interval", time.Minute)
+	viper.SetDefault("export.timeout", time.Hour)
+
+	// 账号注销默认配置
+	viper.SetDefault("account.deletionGracePeriod", time.Hour*24*30)
+	viper.SetDefault("account.purgeInterval", time.Hour)
+
+	// 用户资料默认配置
+	viper.SetDefault("profile.nameMinLength", 1)
+	viper.SetDefault("profile.nameMaxLength", 20)
+	viper.SetDefault("profile.minAge", 18)
+	viper.SetDefault("profile.maxAge", 100)
+	viper.SetDefault("profile.maxInterests", 10)
 }
@@ -0,0 +1,123 @@
+package contentfilter
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 命中规则后的处置动作
+const (
+	ActionFlag  = "flag"  // 放行，同时进入人工审核队列
+	ActionMask  = "mask"  // 将命中的内容替换为 *
+	ActionBlock = "block" // 拒绝提交
+)
+
+// 被检查的字段
+const (
+	FieldMessage = "message"
+	FieldName    = "name"
+	FieldMajor   = "major"
+)
+
+// Scope 被检查文本的上下文，部分规则只在特定上下文中生效
+type Scope struct {
+	Field        string
+	EarlyMessage bool // 消息是否处于会话早期，早期消息中的联系方式多为引流
+}
+
+// Finding 一条规则命中
+type Finding struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+	Match  string `json:"match"`
+	start  int
+	end    int
+}
+
+// Result 检查结果，Text 为按 mask 规则处理后的文本
+type Result struct {
+	Text     string
+	Blocked  bool
+	Flagged  bool
+	Findings []Finding
+}
+
+// Rules 返回命中的规则名称，按出现顺序去重
+func (r *Result) Rules() []string {
+	seen := make(map[string]bool)
+	var rules []string
+	for _, finding := range r.Findings {
+		if !seen[finding.Rule] {
+			seen[finding.Rule] = true
+			rules = append(rules, finding.Rule)
+		}
+	}
+	return rules
+}
+
+// Rule 内容规则
+type Rule interface {
+	Name() string
+	Find(text string, scope Scope) []Finding
+}
+
+// ContentFilter 内容过滤管道
+type ContentFilter interface {
+	Check(text string, scope Scope) *Result
+}
+
+// pipeline 依次执行各规则并合并结果
+type pipeline struct {
+	rules []Rule
+}
+
+// New 由规则列表创建内容过滤管道
+func New(rules ...Rule) ContentFilter {
+	return &pipeline{rules: rules}
+}
+
+// Check 检查文本，所有规则都会执行，便于审核时看到完整的命中情况
+func (p *pipeline) Check(text string, scope Scope) *Result {
+	result := &Result{Text: text}
+	var masks []Finding
+	for _, rule := range p.rules {
+		for _, finding := range rule.Find(text, scope) {
+			result.Findings = append(result.Findings, finding)
+			switch finding.Action {
+			case ActionBlock:
+				result.Blocked = true
+			case ActionFlag:
+				result.Flagged = true
+			case ActionMask:
+				masks = append(masks, finding)
+			}
+		}
+	}
+	if len(masks) > 0 {
+		result.Text = mask(text, masks)
+	}
+	return result
+}
+
+// mask 将命中的区间按字符替换为 *，区间可以重叠
+func mask(text string, findings []Finding) string {
+	sort.Slice(findings, func(i, j int) bool { return findings[i].start < findings[j].start })
+
+	var b strings.Builder
+	pos := 0
+	for _, finding := range findings {
+		if finding.end <= pos {
+			continue
+		}
+		start := finding.start
+		if start < pos {
+			start = pos
+		}
+		b.WriteString(text[pos:start])
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(text[start:finding.end])))
+		pos = finding.end
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
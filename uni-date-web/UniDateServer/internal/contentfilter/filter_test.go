@@ -0,0 +1,111 @@
+package contentfilter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+)
+
+func newTestFilter(t *testing.T) ContentFilter {
+	t.Helper()
+	filter, err := NewFromConfig(config.ContentFilterConfig{
+		WordLists: []config.WordListConfig{
+			{Name: "profanity", Action: ActionMask, Words: []string{"fuck", "ass", "傻逼"}},
+			{Name: "scam", Action: ActionBlock, Words: []string{"bitcoin"}},
+		},
+		URL:   config.ContactRuleConfig{Enabled: true, Action: ActionFlag},
+		Phone: config.ContactRuleConfig{Enabled: true, Action: ActionMask},
+	})
+	if err != nil {
+		t.Fatalf("创建内容过滤失败: %v", err)
+	}
+	return filter
+}
+
+func TestCheck(t *testing.T) {
+	filter := newTestFilter(t)
+	early := Scope{Field: FieldMessage, EarlyMessage: true}
+	late := Scope{Field: FieldMessage}
+	profile := Scope{Field: FieldName}
+
+	tests := []struct {
+		name    string
+		text    string
+		scope   Scope
+		want    string
+		blocked bool
+		flagged bool
+		rules   []string
+	}{
+		{"正常文本", "hello world", late, "hello world", false, false, nil},
+		{"英文词不区分大小写", "what the FUCK", late, "what the ****", false, false, []string{"profanity"}},
+		{"英文词按整词匹配", "a classic passage", late, "a classic passage", false, false, nil},
+		{"英文整词", "you ass", late, "you ***", false, false, []string{"profanity"}},
+		{"多次命中同一规则", "ass and ass", late, "*** and ***", false, false, []string{"profanity"}},
+		{"中文词按子串匹配", "你是傻逼吧", late, "你是**吧", false, false, []string{"profanity"}},
+		{"拒绝提交", "buy bitcoin now", late, "buy bitcoin now", true, false, []string{"scam"}},
+		{"资料中的网址", "visit www.example.com", profile, "visit www.example.com", false, true, []string{"url"}},
+		{"早期消息中的网址", "see example.cn/page", early, "see example.cn/page", false, true, []string{"url"}},
+		{"熟悉之后的网址", "see example.cn/page", late, "see example.cn/page", false, false, nil},
+		{"早期消息中的手机号", "call 13812345678", early, "call ***********", false, false, []string{"phone"}},
+		{"带区号和分隔符的手机号", "+86 138-1234-5678", early, "*****************", false, false, []string{"phone"}},
+		{"资料中的手机号", "13812345678", profile, "***********", false, false, []string{"phone"}},
+		{"熟悉之后的手机号", "call 13812345678", late, "call 13812345678", false, false, nil},
+		{"更长数字串中的手机号", "order 2013812345678999", early, "order 2013812345678999", false, false, nil},
+		{"多条规则同时命中", "fuck www.example.com", profile, "**** www.example.com", false, true, []string{"profanity", "url"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := filter.Check(tt.text, tt.scope)
+			if result.Text != tt.want {
+				t.Errorf("期望文本 %q，实际 %q", tt.want, result.Text)
+			}
+			if result.Blocked != tt.blocked || result.Flagged != tt.flagged {
+				t.Errorf("期望 blocked=%v flagged=%v，实际 %v %v", tt.blocked, tt.flagged, result.Blocked, result.Flagged)
+			}
+			if rules := result.Rules(); !reflect.DeepEqual(rules, tt.rules) {
+				t.Errorf("期望命中 %v，实际 %v", tt.rules, rules)
+			}
+		})
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		findings []Finding
+		want     string
+	}{
+		{"单个区间", "abcdefghij", []Finding{{start: 2, end: 4}}, "ab**efghij"},
+		{"重叠区间", "abcdefghij", []Finding{{start: 0, end: 5}, {start: 3, end: 8}}, "********ij"},
+		{"包含的区间", "abcdefghij", []Finding{{start: 1, end: 9}, {start: 3, end: 5}}, "a********j"},
+		{"乱序区间", "abcdefghij", []Finding{{start: 6, end: 8}, {start: 0, end: 2}}, "**cdef**ij"},
+		{"按字符计数", "你好世界", []Finding{{start: 3, end: 9}}, "你**界"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mask(tt.text, tt.findings); got != tt.want {
+				t.Errorf("期望 %q，实际 %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewFromConfigRejectsInvalidAction(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.ContentFilterConfig
+	}{
+		{"词表", config.ContentFilterConfig{WordLists: []config.WordListConfig{{Name: "bad", Action: "drop", Words: []string{"x"}}}}},
+		{"联系方式", config.ContentFilterConfig{URL: config.ContactRuleConfig{Enabled: true, Action: "drop"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFromConfig(tt.cfg); err == nil {
+				t.Fatalf("无效的处置动作应返回错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package contentfilter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+)
+
+var (
+	urlPattern   = regexp.MustCompile(`(?i)(?:https?://|www\.)\S+|\b[a-z0-9][a-z0-9-]*\.(?:com|cn|net|org|io|me|cc|top|xyz|link|app)\b(?:/\S*)?`)
+	phonePattern = regexp.MustCompile(`(?:\+?86[\s-]?)?\b1[3-9]\d(?:[\s-]?\d){8}\b|\+\d{1,3}[\s-]?\d(?:[\s-]?\d){6,12}`)
+)
+
+// patternRule 基于正则的规则
+type patternRule struct {
+	name      string
+	action    string
+	pattern   *regexp.Regexp
+	appliesTo func(scope Scope) bool
+}
+
+// NewPatternRule 创建正则规则，appliesTo 为空时对所有上下文生效，pattern 为空时不命中任何内容
+func NewPatternRule(name, action string, pattern *regexp.Regexp, appliesTo func(scope Scope) bool) Rule {
+	return &patternRule{name: name, action: action, pattern: pattern, appliesTo: appliesTo}
+}
+
+func (r *patternRule) Name() string {
+	return r.name
+}
+
+func (r *patternRule) Find(text string, scope Scope) []Finding {
+	if r.pattern == nil || (r.appliesTo != nil && !r.appliesTo(scope)) {
+		return nil
+	}
+	var findings []Finding
+	for _, loc := range r.pattern.FindAllStringIndex(text, -1) {
+		findings = append(findings, Finding{
+			Rule:   r.name,
+			Action: r.action,
+			Match:  text[loc[0]:loc[1]],
+			start:  loc[0],
+			end:    loc[1],
+		})
+	}
+	return findings
+}
+
+// NewWordListRule 创建词表规则，不区分大小写
+// 英文词按整词匹配，避免误伤包含该词的正常单词；中文词按子串匹配
+func NewWordListRule(name, action string, words []string) (Rule, error) {
+	var alternatives []string
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		quoted := regexp.QuoteMeta(word)
+		if isASCIIWord(word) {
+			quoted = `\b` + quoted + `\b`
+		}
+		alternatives = append(alternatives, quoted)
+	}
+	if len(alternatives) == 0 {
+		return NewPatternRule(name, action, nil, nil), nil
+	}
+
+	pattern, err := regexp.Compile(`(?i)` + strings.Join(alternatives, "|"))
+	if err != nil {
+		return nil, fmt.Errorf("词表 %s 无法编译: %w", name, err)
+	}
+	return NewPatternRule(name, action, pattern, nil), nil
+}
+
+// NewFromConfig 按配置创建内容过滤管道
+func NewFromConfig(cfg config.ContentFilterConfig) (ContentFilter, error) {
+	var rules []Rule
+	for _, list := range cfg.WordLists {
+		if !isValidAction(list.Action) {
+			return nil, fmt.Errorf("词表 %s 的处置动作无效: %s", list.Name, list.Action)
+		}
+		words := list.Words
+		if list.File != "" {
+			fileWords, err := readWordFile(list.File)
+			if err != nil {
+				return nil, err
+			}
+			words = append(words, fileWords...)
+		}
+		rule, err := NewWordListRule(list.Name, list.Action, words)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	// 联系方式检测只针对资料和会话早期的消息，熟悉之后交换联系方式是正常行为
+	contactScope := func(scope Scope) bool {
+		return scope.Field != FieldMessage || scope.EarlyMessage
+	}
+	contactRules := []struct {
+		name    string
+		cfg     config.ContactRuleConfig
+		pattern *regexp.Regexp
+	}{
+		{"url", cfg.URL, urlPattern},
+		{"phone", cfg.Phone, phonePattern},
+	}
+	for _, rule := range contactRules {
+		if !rule.cfg.Enabled {
+			continue
+		}
+		if !isValidAction(rule.cfg.Action) {
+			return nil, fmt.Errorf("规则 %s 的处置动作无效: %s", rule.name, rule.cfg.Action)
+		}
+		rules = append(rules, NewPatternRule(rule.name, rule.cfg.Action, rule.pattern, contactScope))
+	}
+
+	return New(rules...), nil
+}
+
+// readWordFile 读取词表文件，每行一个词，# 开头为注释
+func readWordFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取词表失败: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// isASCIIWord 判断是否为纯英文数字词
+func isASCIIWord(word string) bool {
+	for _, r := range word {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidAction 检查处置动作是否合法
+func isValidAction(action string) bool {
+	return action == ActionFlag || action == ActionMask || action == ActionBlock
+}
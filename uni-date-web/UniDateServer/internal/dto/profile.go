@@ -0,0 +1,124 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+)
+
+// Profile 用户本人看到的完整资料
+type Profile struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Phone          string     `json:"phone"`
+	Account        string     `json:"account"`
+	Avatar         string     `json:"avatar"`
+	Birthdate      string     `json:"birthdate"`
+	Age            *int       `json:"age,omitempty"`
+	Gender         string     `json:"gender"`
+	University     string     `json:"university"`
+	Major          string     `json:"major"`
+	Photos         []string   `json:"photos"`
+	Interests      []string   `json:"interests"`
+	IsVerified     bool       `json:"isVerified"`
+	IsVIP          bool       `json:"isVIP"`
+	Timezone       string     `json:"timezone"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"`
+	StatusReason   string     `json:"statusReason,omitempty"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+	Version        int        `json:"version"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// NewProfile 由用户记录生成本人资料，不包含密码和风控字段
+func NewProfile(user *models.User) *Profile {
+	return &Profile{
+		ID:             user.ID,
+		Name:           user.Name,
+		Phone:          user.Phone,
+		Account:        user.Account,
+		Avatar:         user.Avatar,
+		Birthdate:      formatDate(user.Birthdate),
+		Age:            Age(user.Birthdate, time.Now()),
+		Gender:         user.Gender,
+		University:     user.University,
+		Major:          user.Major,
+		Photos:         user.Photos,
+		Interests:      user.Interests,
+		IsVerified:     user.IsVerified,
+		IsVIP:          user.IsVIP,
+		Timezone:       user.Timezone,
+		Role:           user.Role,
+		Status:         user.Status,
+		StatusReason:   user.StatusReason,
+		SuspendedUntil: user.SuspendedUntil,
+		Version:        user.Version,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+	}
+}
+
+// PublicProfile 展示给其他用户的资料
+// 不包含手机号、账号和具体生日，年龄、学校和最近在线时间按隐私设置隐藏
+type PublicProfile struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Avatar       string     `json:"avatar"`
+	Age          *int       `json:"age,omitempty"`
+	Gender       string     `json:"gender"`
+	University   string     `json:"university,omitempty"`
+	Major        string     `json:"major"`
+	Photos       []string   `json:"photos"`
+	Interests    []string   `json:"interests"`
+	IsVerified   bool       `json:"isVerified"`
+	LastActiveAt *time.Time `json:"lastActiveAt,omitempty"`
+}
+
+// NewPublicProfile 按用户的隐私设置生成公开资料，lastActive 为空表示不展示最近在线时间
+func NewPublicProfile(user *models.User, settings *models.PrivacySettings, lastActive *time.Time, now time.Time) *PublicProfile {
+	profile := &PublicProfile{
+		ID:         user.ID,
+		Name:       user.Name,
+		Avatar:     user.Avatar,
+		Gender:     user.Gender,
+		University: user.University,
+		Major:      user.Major,
+		Photos:     user.Photos,
+		Interests:  user.Interests,
+		IsVerified: user.IsVerified,
+	}
+	if !settings.HideAge {
+		profile.Age = Age(user.Birthdate, now)
+	}
+	if settings.HideUniversity {
+		profile.University = ""
+	}
+	if !settings.HideLastActive {
+		profile.LastActiveAt = lastActive
+	}
+	return profile
+}
+
+// Age 根据生日计算周岁，生日为空或格式错误时返回 nil
+func Age(birthdate string, now time.Time) *int {
+	born, err := time.Parse("2006-01-02", formatDate(birthdate))
+	if err != nil {
+		return nil
+	}
+
+	age := now.Year() - born.Year()
+	if now.Month() < born.Month() || (now.Month() == born.Month() && now.Day() < born.Day()) {
+		age--
+	}
+	return &age
+}
+
+// formatDate 数据库返回的日期可能带有时间部分，只保留日期
+func formatDate(date string) string {
+	if len(date) > len("2006-01-02") {
+		return date[:len("2006-01-02")]
+	}
+	return date
+}
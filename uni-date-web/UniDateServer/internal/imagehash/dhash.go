@@ -0,0 +1,77 @@
+package imagehash
+
+import (
+	"image"
+	"math/bits"
+)
+
+// Bits 哈希位数
+const Bits = 64
+
+// 差值哈希采样尺寸，每行 9 个点比较出 8 位，共 8 行
+const (
+	sampleWidth  = 9
+	sampleHeight = 8
+)
+
+// DHash 计算图片的差值哈希（dHash）
+// 图片缩小为 9x8 的灰度图后逐行比较相邻像素亮度，对缩放、压缩和轻微调色不敏感
+func DHash(img image.Image) uint64 {
+	gray := downsample(img)
+
+	var hash uint64
+	for y := 0; y < sampleHeight; y++ {
+		for x := 0; x < sampleWidth-1; x++ {
+			hash <<= 1
+			if gray[y][x] > gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// Distance 返回两个哈希的汉明距离，越小越相似
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Similarity 将汉明距离换算为 0~1 的相似度
+func Similarity(distance int) float64 {
+	return 1 - float64(distance)/Bits
+}
+
+// downsample 按区域平均将图片缩小为采样尺寸的灰度矩阵
+func downsample(img image.Image) [sampleHeight][sampleWidth]float64 {
+	var sums [sampleHeight][sampleWidth]float64
+	var counts [sampleHeight][sampleWidth]int
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return sums
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		cy := (y - bounds.Min.Y) * sampleHeight / height
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			cx := (x - bounds.Min.X) * sampleWidth / width
+			r, g, b, _ := img.At(x, y).RGBA()
+			sums[cy][cx] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			counts[cy][cx]++
+		}
+	}
+
+	// 图片比采样尺寸还小时部分格子没有像素，取同一行前一个格子的值
+	for y := 0; y < sampleHeight; y++ {
+		for x := 0; x < sampleWidth; x++ {
+			switch {
+			case counts[y][x] > 0:
+				sums[y][x] /= float64(counts[y][x])
+			case x > 0:
+				sums[y][x] = sums[y][x-1]
+			}
+		}
+	}
+	return sums
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Scheduler 简单的周期任务调度器
+// 多实例部署时每个实例都会执行，任务本身需要保证幂等
+type Scheduler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler 创建调度器实例
+func NewScheduler() *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Every 按固定间隔执行任务，任务出错只记录日志
+// 传给任务的 ctx 在 Stop 时取消，正在执行的查询随之中止
+func (s *Scheduler) Every(name string, interval time.Duration, job func(ctx context.Context) error) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := job(s.ctx); err != nil {
+					log.Printf("定时任务 %s 执行失败: %v", name, err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 取消所有任务并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
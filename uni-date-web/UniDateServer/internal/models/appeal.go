@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// 申诉状态
+const (
+	AppealStatusPending  = "pending"
+	AppealStatusApproved = "approved"
+	AppealStatusRejected = "rejected"
+)
+
+// Appeal 被暂停或封禁的用户提交的申诉
+type Appeal struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID     string     `json:"userId" gorm:"type:uuid;not null;index:idx_appeals_user"`
+	UserStatus string     `json:"userStatus" gorm:"size:20;not null"` // 提交申诉时的账号状态
+	Message    string     `json:"message" gorm:"type:text;not null"`
+	Status     string     `json:"status" gorm:"size:20;not null;default:'pending';index:idx_appeals_status"`
+	ReviewerID *string    `json:"reviewerId" gorm:"type:uuid"`
+	ReviewNote string     `json:"reviewNote" gorm:"type:text"`
+	CreatedAt  time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	ReviewedAt *time.Time `json:"reviewedAt"`
+	User       User       `json:"-" gorm:"foreignKey:UserID"`
+}
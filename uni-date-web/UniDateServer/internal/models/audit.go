@@ -0,0 +1,77 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 审计事件类型
+const (
+	AuditActionLoginSuccess           = "auth.login_success"
+	AuditActionLoginFailure           = "auth.login_failure"
+	AuditActionPasswordChange         = "user.password_change"
+	AuditActionProfileUpdate          = "user.profile_update"
+	AuditActionBlock                  = "user.block"
+	AuditActionUnblock                = "user.unblock"
+	AuditActionReport                 = "user.report"
+	AuditActionDeletionRequest        = "user.deletion_request"
+	AuditActionDeletionCancel         = "user.deletion_cancel"
+	AuditActionAppeal                 = "user.appeal"
+	AuditActionAdminSetVerified       = "admin.set_verified"
+	AuditActionAdminSetVIP            = "admin.set_vip"
+	AuditActionAdminSuspend           = "admin.suspend"
+	AuditActionAdminBan               = "admin.ban"
+	AuditActionAdminReinstate         = "admin.reinstate"
+	AuditActionAdminDelete            = "admin.delete"
+	AuditActionAdminRestore           = "admin.restore"
+	AuditActionAdminResetPassword     = "admin.reset_password"
+	AuditActionAdminResolveAppeal     = "admin.resolve_appeal"
+	AuditActionAdminResolveModeration = "admin.resolve_moderation"
+	AuditActionAdminBlocklistPhoto    = "admin.blocklist_photo"
+	AuditActionAdminUnblocklistPhoto  = "admin.unblocklist_photo"
+	AuditActionSuspensionExpired      = "system.suspension_expired"
+	AuditActionRiskEscalated          = "system.risk_escalated"
+	AuditActionAccountPurged          = "system.account_purged"
+)
+
+// AuditEvent 审计事件，只允许追加，不允许修改或删除
+// 每条事件记录上一条的哈希形成哈希链，任何一条被篡改都会导致之后的校验失败
+// Metadata 中不得包含密码等敏感信息
+type AuditEvent struct {
+	ID        string            `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Seq       int64             `json:"seq" gorm:"not null;uniqueIndex"`
+	ActorID   string            `json:"actorId" gorm:"type:uuid;index:idx_audit_events_actor"`
+	Action    string            `json:"action" gorm:"size:50;not null;index:idx_audit_events_action"`
+	TargetID  string            `json:"targetId" gorm:"type:uuid;index:idx_audit_events_target"`
+	IP        string            `json:"ip" gorm:"size:45"`
+	UserAgent string            `json:"userAgent" gorm:"type:text"`
+	RequestID string            `json:"requestId" gorm:"size:64"`
+	Metadata  map[string]string `json:"metadata" gorm:"type:jsonb;serializer:json"`
+	PrevHash  string            `json:"prevHash" gorm:"size:64;not null"`
+	Hash      string            `json:"hash" gorm:"size:64;not null"`
+	CreatedAt time.Time         `json:"createdAt" gorm:"not null;index:idx_audit_events_created_at"`
+}
+
+// ComputeHash 计算事件的哈希，覆盖除 ID 和 Hash 以外的所有字段
+// CreatedAt 按微秒截断，与数据库存储精度一致
+func (e *AuditEvent) ComputeHash() string {
+	metadata, _ := json.Marshal(e.Metadata) // map 按键排序序列化，结果稳定
+	fields := []string{
+		strconv.FormatInt(e.Seq, 10),
+		e.ActorID,
+		e.Action,
+		e.TargetID,
+		e.IP,
+		e.UserAgent,
+		e.RequestID,
+		string(metadata),
+		e.PrevHash,
+		e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
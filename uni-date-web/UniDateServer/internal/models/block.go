@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// Block 用户拉黑记录，拉黑后双方互不可见
+type Block struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	BlockerID string    `json:"blockerId" gorm:"type:uuid;not null;uniqueIndex:idx_blocks_pair"`
+	BlockedID string    `json:"blockedId" gorm:"type:uuid;not null;uniqueIndex:idx_blocks_pair;index:idx_blocks_blocked"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	Blocker   User      `json:"-" gorm:"foreignKey:BlockerID"`
+	Blocked   User      `json:"-" gorm:"foreignKey:BlockedID"`
+}
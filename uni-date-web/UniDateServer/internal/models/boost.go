@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// 加速来源
+const (
+	BoostSourceVIP      = "vip"      // VIP 每日赠送
+	BoostSourcePurchase = "purchase" // 单独购买
+)
+
+// BoostProduct 单次加速的商品标识，与订阅套餐共用支付流程
+const BoostProduct = "boost"
+
+// Boost 资料加速
+// 购买成功后生成一条未使用的记录（StartsAt 为空），激活时填入起止时间
+// Impressions 和 Likes 统计加速期间在推荐列表中的曝光次数和收到的喜欢
+type Boost struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      string     `json:"userId" gorm:"type:uuid;not null;index:idx_boosts_user"`
+	Source      string     `json:"source" gorm:"size:20;not null"` // 'vip', 'purchase'
+	ProviderRef *string    `json:"-" gorm:"size:255;uniqueIndex"`  // 购买时的支付会话 ID
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt" gorm:"index:idx_boosts_ends_at"`
+	Impressions int        `json:"impressions" gorm:"default:0"`
+	Likes       int        `json:"likes" gorm:"default:0"`
+	CreatedAt   time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	User        User       `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// IsActive 判断加速在给定时间是否生效中
+func (b *Boost) IsActive(now time.Time) bool {
+	return b.StartsAt != nil && b.EndsAt != nil && !b.StartsAt.After(now) && b.EndsAt.After(now)
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// 数据导出状态
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusReady      = "ready"
+	DataExportStatusFailed     = "failed"
+	DataExportStatusExpired    = "expired" // 下载链接过期，文件已删除
+)
+
+// DataExport 个人数据导出任务
+type DataExport struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      string     `json:"userId" gorm:"type:uuid;not null;index:idx_data_exports_user"` // 待处理或处理中的任务每个用户只有一个，见 idx_data_exports_active
+	Status      string     `json:"status" gorm:"size:20;not null;default:'pending';index:idx_data_exports_status"`
+	FilePath    string     `json:"-" gorm:"size:255"`
+	Error       string     `json:"-" gorm:"type:text"`
+	CreatedAt   time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	StartedAt   *time.Time `json:"-"`
+	CompletedAt *time.Time `json:"completedAt"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+}
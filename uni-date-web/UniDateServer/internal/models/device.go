@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// Device 移动端推送设备
+type Device struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string    `json:"userId" gorm:"type:uuid;not null;index:idx_devices_user"`
+	Token     string    `json:"token" gorm:"size:255;uniqueIndex;not null"`
+	Platform  string    `json:"platform" gorm:"size:10;not null"` // 'ios' or 'android'
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+	User      User      `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// PushPreference 推送偏好，按通知类型单独关闭推送
+// 字段均为“关闭”语义，没有记录时默认全部推送
+type PushPreference struct {
+	UserID      string    `json:"userId" gorm:"primaryKey;type:uuid"`
+	MuteMatch   bool      `json:"muteMatch" gorm:"default:false"`
+	MuteMessage bool      `json:"muteMessage" gorm:"default:false"`
+	MuteLike    bool      `json:"muteLike" gorm:"default:false"`
+	MuteSystem  bool      `json:"muteSystem" gorm:"default:false"`
+	UpdatedAt   time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+	User        User      `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// IsMuted 判断某类通知是否已关闭推送
+func (p *PushPreference) IsMuted(notificationType string) bool {
+	switch notificationType {
+	case NotificationTypeMatch:
+		return p.MuteMatch
+	case NotificationTypeMessage:
+		return p.MuteMessage
+	case NotificationTypeLike:
+		return p.MuteLike
+	case NotificationTypeSystem:
+		return p.MuteSystem
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// 审核队列条目来源
+const (
+	ModerationSourceMessage = "message"
+	ModerationSourceProfile = "profile"
+	ModerationSourceReport  = "report"
+	ModerationSourceRisk    = "risk" // 风险评分自动提交
+	ModerationSourcePhoto   = "photo"
+)
+
+// 审核队列条目状态
+const (
+	ModerationStatusPending   = "pending"
+	ModerationStatusActioned  = "actioned"  // 确认违规并已处理
+	ModerationStatusDismissed = "dismissed" // 误报，无需处理
+)
+
+// ModerationItem 人工审核队列条目
+// SubjectUserID 为被审核内容的所有者，RefID 指向来源记录（消息、举报等）
+type ModerationItem struct {
+	ID            string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Source        string     `json:"source" gorm:"size:20;not null"`
+	SubjectUserID string     `json:"subjectUserId" gorm:"type:uuid;not null;index:idx_moderation_items_subject"`
+	RefID         string     `json:"refId" gorm:"type:uuid"`
+	Field         string     `json:"field" gorm:"size:20"`
+	Content       string     `json:"content" gorm:"type:text"`
+	Rules         []string   `json:"rules" gorm:"type:jsonb;serializer:json"`
+	Similarity    *float64   `json:"similarity,omitempty"`                // 照片与已有照片或违规图片的相似度，0~1
+	MatchedRef    string     `json:"matchedRef,omitempty" gorm:"size:36"` // 相似的照片或违规图片 ID
+	Status        string     `json:"status" gorm:"size:20;not null;default:'pending';index:idx_moderation_items_status"`
+	ReviewerID    *string    `json:"reviewerId" gorm:"type:uuid"`
+	ReviewNote    string     `json:"reviewNote" gorm:"type:text"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	ReviewedAt    *time.Time `json:"reviewedAt"`
+}
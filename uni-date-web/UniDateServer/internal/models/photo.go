@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Photo 用户上传的照片，Hash 为感知哈希，用于识别盗用和重复使用的照片
+type Photo struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string    `json:"userId" gorm:"type:uuid;not null;index:idx_photos_user"`
+	URL       string    `json:"url" gorm:"size:255;not null"`
+	Hash      int64     `json:"-" gorm:"not null"` // 64 位 dHash，按位存储为 bigint
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// PhotoBlocklistEntry 管理员维护的违规图片，上传的照片与其相似时进入审核队列
+type PhotoBlocklistEntry struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Hash      int64     `json:"-" gorm:"not null"`
+	Label     string    `json:"label" gorm:"size:100;not null"`
+	AddedBy   string    `json:"addedBy" gorm:"type:uuid;not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// TableName 指定违规图片表名
+func (PhotoBlocklistEntry) TableName() string {
+	return "photo_blocklist"
+}
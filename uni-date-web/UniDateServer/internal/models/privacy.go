@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// PrivacySettings 隐私设置，没有记录时使用 DefaultPrivacySettings
+type PrivacySettings struct {
+	UserID         string    `json:"-" gorm:"primaryKey;type:uuid"`
+	HideAge        bool      `json:"hideAge" gorm:"not null"`
+	HideUniversity bool      `json:"hideUniversity" gorm:"not null"`
+	Incognito      bool      `json:"incognito" gorm:"not null"` // 只对自己喜欢过的人可见，仅 VIP 期间生效
+	HideLastActive bool      `json:"hideLastActive" gorm:"not null"`
+	Discoverable   bool      `json:"discoverable" gorm:"not null"` // 为 false 时不出现在推荐中
+	UpdatedAt      time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+	User           User      `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// DefaultPrivacySettings 返回用户的默认隐私设置：所有信息可见、出现在推荐中
+func DefaultPrivacySettings(userID string) *PrivacySettings {
+	return &PrivacySettings{UserID: userID, Discoverable: true}
+}
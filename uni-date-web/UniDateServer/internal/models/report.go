@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// Report 用户举报记录
+type Report struct {
+	ID         string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ReporterID *string   `json:"reporterId" gorm:"type:uuid;index:idx_reports_reporter"` // 举报人注销后清空
+	ReportedID string    `json:"reportedId" gorm:"type:uuid;not null;index:idx_reports_reported"`
+	Reason     string    `json:"reason" gorm:"size:50;not null"`
+	Detail     string    `json:"detail" gorm:"type:text"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	Reporter   User      `json:"-" gorm:"foreignKey:ReporterID"`
+	Reported   User      `json:"-" gorm:"foreignKey:ReportedID"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// 订阅套餐
+const (
+	SubscriptionPlanMonthly  = "monthly"
+	SubscriptionPlanYearly   = "yearly"
+	SubscriptionPlanLifetime = "lifetime"
+	SubscriptionPlanComp     = "comp" // 管理员赠送，不经过支付
+)
+
+// 订阅状态
+const (
+	SubscriptionStatusActive   = "active"
+	SubscriptionStatusExpired  = "expired"
+	SubscriptionStatusCanceled = "canceled"
+)
+
+// Subscription VIP 订阅模型
+// 用户的 IsVIP 由是否存在有效订阅推导，不允许直接修改
+type Subscription struct {
+	ID                string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID            string     `json:"userId" gorm:"type:uuid;not null;index:idx_subscriptions_user"`
+	Plan              string     `json:"plan" gorm:"size:20;not null"`   // 'monthly', 'yearly', 'lifetime', 'comp'
+	Status            string     `json:"status" gorm:"size:20;not null"` // 'active', 'expired', 'canceled'
+	StartDate         time.Time  `json:"startDate" gorm:"not null"`
+	EndDate           *time.Time `json:"endDate"` // 终身套餐为空
+	CancelAtPeriodEnd bool       `json:"cancelAtPeriodEnd" gorm:"default:false"`
+	PaymentMethod     string     `json:"paymentMethod" gorm:"size:20"`
+	Amount            int64      `json:"amount"` // 以分为单位
+	Currency          string     `json:"currency" gorm:"size:10"`
+	ProviderRef       string     `json:"-" gorm:"size:255;uniqueIndex"` // 支付平台的订阅或支付 ID
+	CreatedAt         time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+	User              User       `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// IsValid 判断订阅在给定时间是否仍然有效
+func (s *Subscription) IsValid(now time.Time) bool {
+	if s.Status != SubscriptionStatusActive {
+		return false
+	}
+	return s.EndDate == nil || s.EndDate.After(now)
+}
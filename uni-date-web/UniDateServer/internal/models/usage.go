@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// UsageCounter 按用户本地日期统计的功能使用次数，用于每日配额
+type UsageCounter struct {
+	UserID    string    `json:"userId" gorm:"primaryKey;type:uuid"`
+	Feature   string    `json:"feature" gorm:"primaryKey;size:20"` // 'like', 'rewind' 等
+	Day       string    `json:"day" gorm:"primaryKey;type:date"`   // 用户时区下的日期 YYYY-MM-DD
+	Count     int       `json:"count" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
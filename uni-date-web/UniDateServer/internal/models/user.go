@@ -7,24 +7,37 @@ import (
 )
 
 // User 用户模型
+// Version 为资料版本号，每次本人修改资料时加一，用于乐观锁
 type User struct {
-	ID         string         `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	Name       string         `json:"name" gorm:"size:100;not null"`
-	Phone      string         `json:"phone" gorm:"size:20;uniqueIndex"`
-	Account    string         `json:"account" gorm:"size:100;uniqueIndex;not null"`
-	Password   string         `json:"password,omitempty" gorm:"size:255;not null"`
-	Avatar     string         `json:"avatar" gorm:"size:255"`
-	Birthdate  string         `json:"birthdate" gorm:"type:date"`
-	Gender     string         `json:"gender" gorm:"size:10"`
-	University string         `json:"university" gorm:"size:100;not null"`
-	Major      string         `json:"major" gorm:"size:100"`
-	Photos     []string       `json:"photos" gorm:"type:text[]"`
-	Interests  []string       `json:"interests" gorm:"type:text[]"`
-	IsVerified bool           `json:"isVerified" gorm:"default:false"`
-	IsVIP      bool           `json:"isVIP" gorm:"default:false"`
-	CreatedAt  time.Time      `json:"createdAt" gorm:"autoCreateTime"`
-	UpdatedAt  time.Time      `json:"updatedAt" gorm:"autoUpdateTime"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                string         `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name              string         `json:"name" gorm:"size:100;not null"`
+	Phone             string         `json:"phone" gorm:"size:20;uniqueIndex"`
+	Account           string         `json:"account" gorm:"size:100;uniqueIndex;not null"`
+	Password          string         `json:"password,omitempty" gorm:"size:255;not null"`
+	Avatar            string         `json:"avatar" gorm:"size:255"`
+	Birthdate         string         `json:"birthdate" gorm:"type:date"`
+	Gender            string         `json:"gender" gorm:"size:10"`
+	University        string         `json:"university" gorm:"size:100;not null"`
+	Major             string         `json:"major" gorm:"size:100"`
+	Photos            []string       `json:"photos" gorm:"type:text[]"`
+	Interests         []string       `json:"interests" gorm:"type:text[]"`
+	IsVerified        bool           `json:"isVerified" gorm:"default:false"`
+	IsVIP             bool           `json:"isVIP" gorm:"column:is_vip;default:false"`
+	Timezone          string         `json:"timezone" gorm:"size:50;default:'Asia/Shanghai'"` // IANA 时区，用于按本地日期重置每日配额
+	TimezoneChangedAt *time.Time     `json:"-"`                                               // 上次修改时区的时间，用于限制修改频率
+	Role              string         `json:"role" gorm:"size:20;not null;default:'user'"`     // 'user', 'admin'
+	Status            string         `json:"status" gorm:"size:20;not null;default:'active'"` // 'active', 'suspended', 'banned'，只能由管理员修改
+	StatusReason      string         `json:"statusReason,omitempty" gorm:"type:text"`
+	SuspendedUntil    *time.Time     `json:"suspendedUntil,omitempty"`
+	RegistrationIP    string         `json:"-" gorm:"size:45;index"`
+	RiskScore         int            `json:"-" gorm:"default:0"`
+	RiskLevel         string         `json:"-" gorm:"size:20"` // '', 'review', 'shadow_limited'，不返回给用户本人
+	RiskClearedAt     *time.Time     `json:"-"`                // 管理员排除风险的时间，之前的事件不再计分
+	PurgeAfter        *time.Time     `json:"-"`                // 用户自行注销后彻底清除数据的时间，之前重新登录可以撤销
+	Version           int            `json:"version" gorm:"not null;default:1"`
+	CreatedAt         time.Time      `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time      `json:"updatedAt" gorm:"autoUpdateTime"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // Match 匹配模型
@@ -67,7 +80,7 @@ type Message struct {
 // Notification 通知模型
 type Notification struct {
 	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	UserID    string    `json:"userId" gorm:"type:uuid;not null"`
+	UserID    string    `json:"userId" gorm:"type:uuid;not null;index:idx_notifications_user"`
 	Type      string    `json:"type" gorm:"size:20;not null"` // 'match', 'message', 'like', 'system'
 	Content   string    `json:"content" gorm:"type:text;not null"`
 	IsRead    bool      `json:"isRead" gorm:"default:false"`
@@ -75,3 +88,47 @@ type Notification struct {
 	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
 	User      User      `json:"-" gorm:"foreignKey:UserID"`
 }
+
+// 用户角色
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
+// 账号状态
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusBanned    = "banned"
+)
+
+// DeletedUserName 数据清除后账号的显示名称，对方仍能看到聊天记录
+const DeletedUserName = "已注销用户"
+
+// 风险等级，由风险评分自动设置
+const (
+	RiskLevelNone          = ""
+	RiskLevelReview        = "review"         // 需要人工审核
+	RiskLevelShadowLimited = "shadow_limited" // 限制曝光，本人无感知
+)
+
+// 性别
+const (
+	GenderMale   = "male"
+	GenderFemale = "female"
+	GenderOther  = "other"
+)
+
+// 交互类型
+const (
+	InteractionTypeLike    = "like"
+	InteractionTypeDislike = "dislike"
+)
+
+// 通知类型
+const (
+	NotificationTypeMatch   = "match"
+	NotificationTypeMessage = "message"
+	NotificationTypeLike    = "like"
+	NotificationTypeSystem  = "system"
+)
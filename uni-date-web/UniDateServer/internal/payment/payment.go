@@ -0,0 +1,49 @@
+package payment
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidSignature     = errors.New("无效的回调签名")
+	ErrUnknownPlan          = errors.New("未配置的订阅套餐")
+	ErrWebhookNotConfigured = errors.New("未配置回调签名密钥")
+)
+
+// 统一后的回调事件类型，与具体支付平台无关
+const (
+	EventCheckoutCompleted   = "checkout.completed"   // 首次支付成功
+	EventSubscriptionRenewed = "subscription.renewed" // 自动续费成功
+	EventSubscriptionEnded   = "subscription.ended"   // 订阅终止（取消到期或扣款失败）
+)
+
+// CheckoutSession 支付会话，客户端跳转到 URL 完成支付
+type CheckoutSession struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// WebhookEvent 支付平台回调事件
+// 平台事件类型不在关注范围内时 Type 为空
+type WebhookEvent struct {
+	ID            string
+	Type          string
+	UserID        string
+	Plan          string // 订阅套餐或 models.BoostProduct
+	ProviderRef   string // 订阅 ID，一次性支付时为支付会话 ID
+	PaymentMethod string
+	Amount        int64
+	Currency      string
+	PeriodEnd     *time.Time
+}
+
+// PaymentProvider 支付平台接口
+type PaymentProvider interface {
+	// CreateCheckout 为用户创建订阅或资料加速的支付会话
+	CreateCheckout(userID, plan string) (*CheckoutSession, error)
+	// CancelAtPeriodEnd 取消自动续费，当前周期结束后订阅终止
+	CancelAtPeriodEnd(providerRef string) error
+	// ParseWebhook 校验回调签名并解析事件
+	ParseWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
@@ -0,0 +1,264 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+)
+
+const stripeAPIBase = "https://api.stripe.com/v1"
+
+// stripeProvider Stripe 支付实现
+type stripeProvider struct {
+	client    *http.Client
+	config    config.PaymentConfig
+	apiBase   string
+	priceIDs  map[string]string
+	tolerance time.Duration
+}
+
+// NewStripeProvider 创建 Stripe 支付实例
+func NewStripeProvider(config config.PaymentConfig) PaymentProvider {
+	return &stripeProvider{
+		client:  &http.Client{Timeout: 15 * time.Second},
+		config:  config,
+		apiBase: stripeAPIBase,
+		priceIDs: map[string]string{
+			models.SubscriptionPlanMonthly:  config.MonthlyPriceID,
+			models.SubscriptionPlanYearly:   config.YearlyPriceID,
+			models.SubscriptionPlanLifetime: config.LifetimePriceID,
+			models.BoostProduct:             config.BoostPriceID,
+		},
+		tolerance: config.WebhookTolerance,
+	}
+}
+
+// CreateCheckout 创建 Stripe Checkout 会话
+// 终身套餐和资料加速为一次性支付，其余为自动续费订阅
+func (p *stripeProvider) CreateCheckout(userID, plan string) (*CheckoutSession, error) {
+	priceID := p.priceIDs[plan]
+	if priceID == "" {
+		return nil, ErrUnknownPlan
+	}
+
+	mode := "subscription"
+	if plan == models.SubscriptionPlanLifetime || plan == models.BoostProduct {
+		mode = "payment"
+	}
+
+	form := url.Values{
+		"mode":                    {mode},
+		"line_items[0][price]":    {priceID},
+		"line_items[0][quantity]": {"1"},
+		"client_reference_id":     {userID},
+		"metadata[user_id]":       {userID},
+		"metadata[plan]":          {plan},
+		"success_url":             {p.config.SuccessURL},
+		"cancel_url":              {p.config.CancelURL},
+	}
+	if mode == "subscription" {
+		// 续费事件中只能拿到订阅对象，需要把用户信息也写到订阅上
+		form.Set("subscription_data[metadata][user_id]", userID)
+		form.Set("subscription_data[metadata][plan]", plan)
+	}
+
+	var session CheckoutSession
+	if err := p.post("/checkout/sessions", form, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// CancelAtPeriodEnd 取消自动续费
+func (p *stripeProvider) CancelAtPeriodEnd(providerRef string) error {
+	form := url.Values{"cancel_at_period_end": {"true"}}
+	return p.post("/subscriptions/"+url.PathEscape(providerRef), form, nil)
+}
+
+// stripeEvent Stripe 回调事件中用到的字段
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// ParseWebhook 校验 Stripe-Signature 并将事件转换为统一格式
+func (p *stripeProvider) ParseWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	// 密钥为空时任何人都能用空密钥伪造签名，直接拒绝
+	if p.config.WebhookSecret == "" {
+		return nil, ErrWebhookNotConfigured
+	}
+	if err := p.verifySignature(payload, signature); err != nil {
+		return nil, err
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+
+	result := &WebhookEvent{ID: event.ID}
+	switch event.Type {
+	case "checkout.session.completed":
+		var session struct {
+			ID                 string            `json:"id"`
+			Subscription       string            `json:"subscription"`
+			AmountTotal        int64             `json:"amount_total"`
+			Currency           string            `json:"currency"`
+			PaymentMethodTypes []string          `json:"payment_method_types"`
+			Metadata           map[string]string `json:"metadata"`
+		}
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return nil, err
+		}
+		result.Type = EventCheckoutCompleted
+		result.UserID = session.Metadata["user_id"]
+		result.Plan = session.Metadata["plan"]
+		result.ProviderRef = session.Subscription
+		if result.ProviderRef == "" {
+			result.ProviderRef = session.ID
+		}
+		result.Amount = session.AmountTotal
+		result.Currency = session.Currency
+		if len(session.PaymentMethodTypes) > 0 {
+			result.PaymentMethod = session.PaymentMethodTypes[0]
+		}
+
+	case "invoice.paid":
+		var invoice struct {
+			Subscription  string `json:"subscription"`
+			BillingReason string `json:"billing_reason"`
+			AmountPaid    int64  `json:"amount_paid"`
+			Currency      string `json:"currency"`
+			Lines         struct {
+				Data []struct {
+					Period struct {
+						End int64 `json:"end"`
+					} `json:"period"`
+				} `json:"data"`
+			} `json:"lines"`
+		}
+		if err := json.Unmarshal(event.Data.Object, &invoice); err != nil {
+			return nil, err
+		}
+		// 首期账单由 checkout.session.completed 处理
+		if invoice.BillingReason != "subscription_cycle" {
+			return result, nil
+		}
+		result.Type = EventSubscriptionRenewed
+		result.ProviderRef = invoice.Subscription
+		result.Amount = invoice.AmountPaid
+		result.Currency = invoice.Currency
+		if len(invoice.Lines.Data) > 0 {
+			end := time.Unix(invoice.Lines.Data[0].Period.End, 0)
+			result.PeriodEnd = &end
+		}
+
+	case "customer.subscription.deleted":
+		var subscription struct {
+			ID       string            `json:"id"`
+			Metadata map[string]string `json:"metadata"`
+		}
+		if err := json.Unmarshal(event.Data.Object, &subscription); err != nil {
+			return nil, err
+		}
+		result.Type = EventSubscriptionEnded
+		result.ProviderRef = subscription.ID
+		result.UserID = subscription.Metadata["user_id"]
+	}
+
+	return result, nil
+}
+
+// verifySignature 校验 Stripe-Signature 头，格式为 t=时间戳,v1=签名
+// 签名为 HMAC-SHA256(webhookSecret, "时间戳.请求体")，并拒绝超出容忍时间的请求以防重放
+func (p *stripeProvider) verifySignature(payload []byte, header string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if p.tolerance > 0 {
+		age := time.Since(time.Unix(ts, 0))
+		if age > p.tolerance || age < -p.tolerance {
+			return ErrInvalidSignature
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.config.WebhookSecret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		actual, err := hex.DecodeString(signature)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(expected, actual) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// post 调用 Stripe API，out 为空时忽略响应内容
+func (p *stripeProvider) post(path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, p.apiBase+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.config.SecretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var result struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return fmt.Errorf("Stripe 请求失败: %d %s", resp.StatusCode, result.Error.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
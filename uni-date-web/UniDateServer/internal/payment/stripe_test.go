@@ -0,0 +1,56 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+)
+
+// sign 按 Stripe 的格式生成回调签名头
+func sign(secret string, payload []byte, at time.Time) string {
+	timestamp := fmt.Sprint(at.Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseWebhookRejectsEmptySecret(t *testing.T) {
+	provider := NewStripeProvider(config.PaymentConfig{WebhookTolerance: time.Minute})
+	payload := []byte(`{"id":"evt_1","type":"checkout.session.completed"}`)
+
+	_, err := provider.ParseWebhook(payload, sign("", payload, time.Now()))
+	if err != ErrWebhookNotConfigured {
+		t.Fatalf("期望 ErrWebhookNotConfigured，实际 %v", err)
+	}
+}
+
+func TestParseWebhookSignature(t *testing.T) {
+	provider := NewStripeProvider(config.PaymentConfig{WebhookSecret: "whsec_test", WebhookTolerance: time.Minute})
+	payload := []byte(`{"id":"evt_1","type":"customer.created"}`)
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   error
+	}{
+		{"正确签名", sign("whsec_test", payload, time.Now()), nil},
+		{"错误密钥", sign("whsec_other", payload, time.Now()), ErrInvalidSignature},
+		{"空密钥", sign("", payload, time.Now()), ErrInvalidSignature},
+		{"超出时间误差", sign("whsec_test", payload, time.Now().Add(-2*time.Minute)), ErrInvalidSignature},
+		{"缺少签名", "", ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.ParseWebhook(payload, tt.signature)
+			if err != tt.wantErr {
+				t.Fatalf("期望 %v，实际 %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package presence
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryTracker 进程内在线状态实现，适用于单实例部署
+type memoryTracker struct {
+	mu          sync.RWMutex
+	connections map[string]int
+	lastSeen    map[string]time.Time
+}
+
+// NewMemoryTracker 创建进程内在线状态追踪实例
+func NewMemoryTracker() Tracker {
+	return &memoryTracker{
+		connections: make(map[string]int),
+		lastSeen:    make(map[string]time.Time),
+	}
+}
+
+// Connect 登记一条连接
+func (t *memoryTracker) Connect(userID string) func() {
+	t.mu.Lock()
+	t.connections[userID]++
+	t.lastSeen[userID] = time.Now()
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			t.connections[userID]--
+			t.lastSeen[userID] = time.Now()
+			if t.connections[userID] <= 0 {
+				delete(t.connections, userID)
+			}
+		})
+	}
+}
+
+// IsOnline 判断用户是否在线
+func (t *memoryTracker) IsOnline(userID string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.connections[userID] > 0
+}
+
+// LastSeen 返回用户最近一次在线的时间
+func (t *memoryTracker) LastSeen(userID string) (time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.connections[userID] > 0 {
+		return time.Now(), true
+	}
+	seen, ok := t.lastSeen[userID]
+	return seen, ok
+}
+
+// Close 进程内实现没有后台任务，无需释放
+func (t *memoryTracker) Close() error {
+	return nil
+}
@@ -0,0 +1,15 @@
+package presence
+
+import "time"
+
+// Tracker 在线状态追踪
+// 用户持有实时连接（如 SSE）期间视为在线，用于判断是否需要走移动端推送
+type Tracker interface {
+	// Connect 登记一条连接，返回释放函数，连接断开时调用
+	Connect(userID string) func()
+	IsOnline(userID string) bool
+	// LastSeen 返回用户最近一次在线的时间，在线时为当前时间，无记录时第二个返回值为 false
+	LastSeen(userID string) (time.Time, bool)
+	// Close 停止后台任务
+	Close() error
+}
@@ -0,0 +1,155 @@
+package presence
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 连接记录的有效期，实例崩溃后残留的记录在该时间后自动失效
+const redisPresenceTTL = 90 * time.Second
+
+// 最近在线时间的保留期
+const redisLastSeenTTL = 30 * 24 * time.Hour
+
+// redisTracker 基于 Redis 的在线状态实现，多实例共享
+// 每个用户对应一个有序集合，成员为连接 ID，分数为过期时间戳
+type redisTracker struct {
+	client *redis.Client
+
+	mu    sync.Mutex
+	local map[string]string // 本实例持有的连接：连接 ID -> 用户 ID
+
+	closeOnce sync.Once
+	done      chan struct{} // 关闭后通知续期任务退出
+	stopped   chan struct{} // 续期任务已退出
+}
+
+// NewRedisTracker 创建 Redis 在线状态追踪实例，并启动后台续期
+func NewRedisTracker(client *redis.Client) Tracker {
+	t := &redisTracker{
+		client:  client,
+		local:   make(map[string]string),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go t.refreshLoop()
+	return t
+}
+
+// Connect 登记一条连接
+func (t *redisTracker) Connect(userID string) func() {
+	connID := newConnectionID()
+
+	t.mu.Lock()
+	t.local[connID] = userID
+	t.mu.Unlock()
+	t.touch(userID, connID)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.local, connID)
+			t.mu.Unlock()
+			ctx := context.Background()
+			t.client.ZRem(ctx, presenceKey(userID), connID)
+			t.client.Set(ctx, lastSeenKey(userID), time.Now().Unix(), redisLastSeenTTL)
+		})
+	}
+}
+
+// IsOnline 判断用户在任一实例上是否有未过期的连接
+func (t *redisTracker) IsOnline(userID string) bool {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	count, err := t.client.ZCount(context.Background(), presenceKey(userID), now, "+inf").Result()
+	if err != nil {
+		// Redis 不可用时按离线处理，宁可多推送也不要漏推送
+		return false
+	}
+	return count > 0
+}
+
+// touch 写入或续期一条连接记录
+func (t *redisTracker) touch(userID, connID string) {
+	ctx := context.Background()
+	key := presenceKey(userID)
+	expiresAt := time.Now().Add(redisPresenceTTL).Unix()
+
+	pipe := t.client.TxPipeline()
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(expiresAt), Member: connID})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+	pipe.Expire(ctx, key, redisPresenceTTL)
+	pipe.Set(ctx, lastSeenKey(userID), time.Now().Unix(), redisLastSeenTTL)
+	pipe.Exec(ctx)
+}
+
+// LastSeen 返回用户最近一次在线的时间
+func (t *redisTracker) LastSeen(userID string) (time.Time, bool) {
+	if t.IsOnline(userID) {
+		return time.Now(), true
+	}
+	unix, err := t.client.Get(context.Background(), lastSeenKey(userID)).Int64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(unix, 0), true
+}
+
+// Close 停止后台续期，等待续期任务退出后返回，之后可以安全地关闭 Redis 客户端
+func (t *redisTracker) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.done)
+	})
+	<-t.stopped
+	return nil
+}
+
+// refreshLoop 定期为本实例持有的连接续期，直到 Close 被调用
+func (t *redisTracker) refreshLoop() {
+	defer close(t.stopped)
+
+	ticker := time.NewTicker(redisPresenceTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+		}
+
+		t.mu.Lock()
+		connections := make(map[string]string, len(t.local))
+		for connID, userID := range t.local {
+			connections[connID] = userID
+		}
+		t.mu.Unlock()
+
+		for connID, userID := range connections {
+			t.touch(userID, connID)
+		}
+	}
+}
+
+// presenceKey 用户在线状态的 Redis 键
+func presenceKey(userID string) string {
+	return "presence:" + userID
+}
+
+// lastSeenKey 用户最近在线时间的 Redis 键
+func lastSeenKey(userID string) string {
+	return "presence:last:" + userID
+}
+
+// newConnectionID 生成随机连接 ID
+func newConnectionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
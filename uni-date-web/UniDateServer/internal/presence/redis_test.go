@@ -0,0 +1,33 @@
+package presence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestRedisTrackerCloseStopsRefresh(t *testing.T) {
+	// 没有连接时续期任务不会访问 Redis，不需要真实的服务
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	tracker := NewRedisTracker(client).(*redisTracker)
+
+	closed := make(chan struct{})
+	go func() {
+		tracker.Close()
+		tracker.Close() // 可以重复调用
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close 没有等到续期任务退出")
+	}
+	select {
+	case <-tracker.stopped:
+	default:
+		t.Fatal("续期任务没有退出")
+	}
+}
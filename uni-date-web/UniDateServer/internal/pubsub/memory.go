@@ -0,0 +1,117 @@
+package pubsub
+
+import (
+	"sync"
+)
+
+// 每个订阅者的缓冲区大小，缓冲区满时丢弃消息以免阻塞发布者
+const memoryBufferSize = 64
+
+// memoryPubSub 进程内发布订阅实现
+type memoryPubSub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*memorySubscription]struct{}
+	closed      bool
+}
+
+// NewMemoryPubSub 创建进程内发布订阅实例
+func NewMemoryPubSub() PubSub {
+	return &memoryPubSub{
+		subscribers: make(map[string]map[*memorySubscription]struct{}),
+	}
+}
+
+// Publish 向频道内的所有订阅者发送消息
+func (p *memoryPubSub) Publish(channel string, payload []byte) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	for sub := range p.subscribers[channel] {
+		select {
+		case sub.ch <- &Message{Channel: channel, Payload: payload}:
+		default:
+			// 订阅者处理过慢，丢弃该消息
+		}
+	}
+	return nil
+}
+
+// Subscribe 订阅频道
+func (p *memoryPubSub) Subscribe(channel string) (Subscription, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrClosed
+	}
+
+	sub := &memorySubscription{
+		parent:  p,
+		channel: channel,
+		ch:      make(chan *Message, memoryBufferSize),
+	}
+	if p.subscribers[channel] == nil {
+		p.subscribers[channel] = make(map[*memorySubscription]struct{})
+	}
+	p.subscribers[channel][sub] = struct{}{}
+	return sub, nil
+}
+
+// Close 关闭所有订阅
+func (p *memoryPubSub) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	for _, subs := range p.subscribers {
+		for sub := range subs {
+			close(sub.ch)
+		}
+	}
+	p.subscribers = nil
+	return nil
+}
+
+// remove 移除订阅者
+func (p *memoryPubSub) remove(sub *memorySubscription) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	subs, ok := p.subscribers[sub.channel]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(p.subscribers, sub.channel)
+	}
+	close(sub.ch)
+}
+
+// memorySubscription 进程内订阅句柄
+type memorySubscription struct {
+	parent  *memoryPubSub
+	channel string
+	ch      chan *Message
+}
+
+// Messages 返回接收消息的通道
+func (s *memorySubscription) Messages() <-chan *Message {
+	return s.ch
+}
+
+// Close 取消订阅
+func (s *memorySubscription) Close() error {
+	s.parent.remove(s)
+	return nil
+}
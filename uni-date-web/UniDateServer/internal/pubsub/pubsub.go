@@ -0,0 +1,28 @@
+package pubsub
+
+import (
+	"errors"
+)
+
+var ErrClosed = errors.New("pubsub 已关闭")
+
+// Message 发布订阅消息
+type Message struct {
+	Channel string
+	Payload []byte
+}
+
+// Subscription 订阅句柄
+type Subscription interface {
+	// Messages 返回接收消息的通道，订阅关闭后通道会被关闭
+	Messages() <-chan *Message
+	Close() error
+}
+
+// PubSub 发布订阅抽象
+// 单实例部署使用内存实现，多实例部署使用 Redis 实现以便跨实例广播
+type PubSub interface {
+	Publish(channel string, payload []byte) error
+	Subscribe(channel string) (Subscription, error)
+	Close() error
+}
@@ -0,0 +1,84 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisPubSub 基于 Redis 的发布订阅实现，用于多实例之间广播
+type redisPubSub struct {
+	client *redis.Client
+}
+
+// NewRedisPubSub 创建 Redis 发布订阅实例
+func NewRedisPubSub(client *redis.Client) PubSub {
+	return &redisPubSub{
+		client: client,
+	}
+}
+
+// Publish 发布消息到 Redis 频道
+func (p *redisPubSub) Publish(channel string, payload []byte) error {
+	return p.client.Publish(context.Background(), channel, payload).Err()
+}
+
+// Subscribe 订阅 Redis 频道
+func (p *redisPubSub) Subscribe(channel string) (Subscription, error) {
+	ctx := context.Background()
+	ps := p.client.Subscribe(ctx, channel)
+	// 等待订阅确认，确保返回后不会错过消息
+	if _, err := ps.Receive(ctx); err != nil {
+		ps.Close()
+		return nil, err
+	}
+
+	sub := &redisSubscription{
+		ps:   ps,
+		ch:   make(chan *Message, memoryBufferSize),
+		done: make(chan struct{}),
+	}
+	go sub.forward()
+	return sub, nil
+}
+
+// Close Redis 客户端由调用方管理，这里无需处理
+func (p *redisPubSub) Close() error {
+	return nil
+}
+
+// redisSubscription Redis 订阅句柄
+type redisSubscription struct {
+	ps        *redis.PubSub
+	ch        chan *Message
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// forward 将 Redis 消息转换为通用消息格式
+func (s *redisSubscription) forward() {
+	defer close(s.ch)
+	for msg := range s.ps.Channel() {
+		select {
+		case s.ch <- &Message{Channel: msg.Channel, Payload: []byte(msg.Payload)}:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Messages 返回接收消息的通道
+func (s *redisSubscription) Messages() <-chan *Message {
+	return s.ch
+}
+
+// Close 取消订阅
+func (s *redisSubscription) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.ps.Close()
+	})
+	return err
+}
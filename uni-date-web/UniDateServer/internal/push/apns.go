@@ -0,0 +1,144 @@
+package push
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	apnsProductionHost = "https://api.push.apple.com"
+	apnsSandboxHost    = "https://api.sandbox.push.apple.com"
+	// Apple 要求提供方令牌在 20 到 60 分钟之间刷新
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// apnsProvider 基于令牌认证的 APNs 推送实现
+type apnsProvider struct {
+	client *http.Client
+	host   string
+	keyID  string
+	teamID string
+	topic  string
+	key    *ecdsa.PrivateKey
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsProvider 创建 APNs 推送实例
+func NewAPNsProvider(config config.APNsConfig) (PushProvider, error) {
+	pem, err := os.ReadFile(config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(pem)
+	if err != nil {
+		return nil, err
+	}
+
+	host := apnsSandboxHost
+	if config.Production {
+		host = apnsProductionHost
+	}
+
+	return &apnsProvider{
+		client: &http.Client{Timeout: 10 * time.Second},
+		host:   host,
+		keyID:  config.KeyID,
+		teamID: config.TeamID,
+		topic:  config.Topic,
+		key:    key,
+	}, nil
+}
+
+// Send 发送推送
+func (p *apnsProvider) Send(token string, message *Message) error {
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": message.Title,
+				"body":  message.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for k, v := range message.Data {
+		payload[k] = v
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	authToken, err := p.providerToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.host+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+authToken)
+	req.Header.Set("apns-topic", p.topic)
+	req.Header.Set("apns-push-type", "alert")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		result.Reason == "BadDeviceToken",
+		result.Reason == "Unregistered",
+		result.Reason == "DeviceTokenNotForTopic":
+		return ErrInvalidToken
+	}
+	return fmt.Errorf("APNs 推送失败: %d %s", resp.StatusCode, result.Reason)
+}
+
+// providerToken 获取提供方令牌，过期前复用
+func (p *apnsProvider) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Since(p.issuedAt) < apnsTokenTTL {
+		return p.token, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = p.keyID
+
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		return "", err
+	}
+	p.token = signed
+	p.issuedAt = now
+	return signed, nil
+}
@@ -0,0 +1,28 @@
+package push
+
+import (
+	"errors"
+)
+
+var (
+	// ErrInvalidToken 设备令牌已失效（卸载应用、令牌过期等），调用方应删除该令牌
+	ErrInvalidToken = errors.New("无效的设备令牌")
+)
+
+// 设备平台
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+)
+
+// Message 推送消息
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// PushProvider 推送服务提供方接口
+type PushProvider interface {
+	Send(token string, message *Message) error
+}
@@ -0,0 +1,64 @@
+package push
+
+import (
+	"sync"
+)
+
+// SentMessage 记录的一次推送
+type SentMessage struct {
+	Token   string
+	Message Message
+}
+
+// RecordingProvider 记录推送内容的假实现，用于测试和本地开发
+type RecordingProvider struct {
+	mu            sync.Mutex
+	sent          []SentMessage
+	invalidTokens map[string]bool
+}
+
+// NewRecordingProvider 创建记录型推送实例
+func NewRecordingProvider() *RecordingProvider {
+	return &RecordingProvider{
+		invalidTokens: make(map[string]bool),
+	}
+}
+
+// Send 记录推送，令牌被标记为无效时返回 ErrInvalidToken
+func (p *RecordingProvider) Send(token string, message *Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.invalidTokens[token] {
+		return ErrInvalidToken
+	}
+	p.sent = append(p.sent, SentMessage{Token: token, Message: *message})
+	return nil
+}
+
+// MarkInvalid 将令牌标记为无效，模拟用户卸载应用
+func (p *RecordingProvider) MarkInvalid(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.invalidTokens[token] = true
+}
+
+// Sent 返回已记录的推送
+func (p *RecordingProvider) Sent() []SentMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sent := make([]SentMessage, len(p.sent))
+	copy(sent, p.sent)
+	return sent
+}
+
+// Reset 清空记录
+func (p *RecordingProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sent = nil
+	p.invalidTokens = make(map[string]bool)
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// AccountPurgeRepository 注销账号数据清除仓库接口
+type AccountPurgeRepository interface {
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.User, error)
+	ListExportFiles(ctx context.Context, userID string) ([]string, error)
+	Purge(ctx context.Context, userID string) error
+}
+
+// accountPurgeRepository 注销账号数据清除仓库实现
+type accountPurgeRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountPurgeRepository 创建注销账号数据清除仓库实例
+func NewAccountPurgeRepository(db *gorm.DB) AccountPurgeRepository {
+	return &accountPurgeRepository{
+		db: db,
+	}
+}
+
+// ListDue 查询宽限期已过、等待清除数据的账号
+func (r *accountPurgeRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND purge_after <= ?", now).
+		Order("purge_after").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// ListExportFiles 查询用户尚未删除的数据导出文件
+func (r *accountPurgeRepository) ListExportFiles(ctx context.Context, userID string) ([]string, error) {
+	var files []string
+	err := r.db.WithContext(ctx).Model(&models.DataExport{}).
+		Where("user_id = ? AND file_path <> ''", userID).
+		Pluck("file_path", &files).Error
+	return files, err
+}
+
+// purgeStatements 按用户删除个人数据的语句，参数均为用户ID
+// 订阅记录作为交易凭证保留，审计记录不可修改
+// 举报和审核队列作为审核证据保留，用户提交的举报只清空举报人
+var purgeStatements = []string{
+	"DELETE FROM interactions WHERE from_user_id = @id OR to_user_id = @id",
+	"DELETE FROM notifications WHERE user_id = @id",
+	"DELETE FROM devices WHERE user_id = @id",
+	"DELETE FROM push_preferences WHERE user_id = @id",
+	"DELETE FROM usage_counters WHERE user_id = @id",
+	"DELETE FROM blocks WHERE blocker_id = @id OR blocked_id = @id",
+	"UPDATE reports SET reporter_id = NULL WHERE reporter_id = @id",
+	"DELETE FROM appeals WHERE user_id = @id",
+	"DELETE FROM boosts WHERE user_id = @id",
+	"DELETE FROM photos WHERE user_id = @id",
+	"DELETE FROM data_exports WHERE user_id = @id",
+	"DELETE FROM privacy_settings WHERE user_id = @id",
+	"UPDATE matches SET is_active = FALSE WHERE user1_id = @id OR user2_id = @id",
+}
+
+// Purge 在一个事务中删除用户的个人数据，并将账号改写为不含个人信息的占位记录
+// 聊天记录保留给对方，发送者显示为已注销用户；账号名和手机号被释放，可以重新注册
+// 时区、角色、状态等恢复为新账号的默认值，不保留注销前的处罚和风控记录
+func (r *accountPurgeRepository) Purge(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		args := map[string]interface{}{"id": userID}
+		for _, statement := range purgeStatements {
+			if err := tx.Exec(statement, args).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().Model(&models.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"name":                models.DeletedUserName,
+				"account":             gorm.Expr("'deleted:' || id"),
+				"phone":               nil,
+				"password":            "",
+				"avatar":              "",
+				"birthdate":           nil,
+				"gender":              "",
+				"university":          "",
+				"major":               "",
+				"photos":              nil,
+				"interests":           nil,
+				"is_verified":         false,
+				"is_vip":              false,
+				"timezone":            gorm.Expr("DEFAULT"),
+				"timezone_changed_at": nil,
+				"role":                models.UserRoleUser,
+				"status":              models.UserStatusActive,
+				"status_reason":       "",
+				"suspended_until":     nil,
+				"registration_ip":     "",
+				"risk_score":          0,
+				"risk_level":          "",
+				"risk_cleared_at":     nil,
+				"purge_after":         nil,
+			}).Error
+	})
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// AppealRepository 申诉仓库接口
+type AppealRepository interface {
+	Create(ctx context.Context, appeal *models.Appeal) error
+	Update(ctx context.Context, appeal *models.Appeal) error
+	GetByID(ctx context.Context, id string) (*models.Appeal, error)
+	GetPendingByUser(ctx context.Context, userID string) (*models.Appeal, error)
+	List(ctx context.Context, status string, offset, limit int) ([]models.Appeal, int64, error)
+}
+
+// appealRepository 申诉仓库实现
+type appealRepository struct {
+	db *gorm.DB
+}
+
+// NewAppealRepository 创建申诉仓库实例
+func NewAppealRepository(db *gorm.DB) AppealRepository {
+	return &appealRepository{
+		db: db,
+	}
+}
+
+// Create 创建申诉
+func (r *appealRepository) Create(ctx context.Context, appeal *models.Appeal) error {
+	return r.db.WithContext(ctx).Create(appeal).Error
+}
+
+// Update 更新申诉
+func (r *appealRepository) Update(ctx context.Context, appeal *models.Appeal) error {
+	return r.db.WithContext(ctx).Save(appeal).Error
+}
+
+// GetByID 通过ID查询申诉
+func (r *appealRepository) GetByID(ctx context.Context, id string) (*models.Appeal, error) {
+	var appeal models.Appeal
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&appeal).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+// GetPendingByUser 查询用户待处理的申诉
+func (r *appealRepository) GetPendingByUser(ctx context.Context, userID string) (*models.Appeal, error) {
+	var appeal models.Appeal
+	err := r.db.WithContext(ctx).Where("user_id = ? AND status = ?", userID, models.AppealStatusPending).
+		First(&appeal).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+// List 按状态分页查询申诉，按提交时间正序，先提交的先处理
+func (r *appealRepository) List(ctx context.Context, status string, offset, limit int) ([]models.Appeal, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Appeal{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var appeals []models.Appeal
+	err := query.Order("created_at").
+		Offset(offset).Limit(limit).
+		Find(&appeals).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return appeals, total, nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// 追加审计事件时使用的事务级咨询锁，保证哈希链按顺序生成
+const auditChainLockKey = 0x617564697431
+
+// AuditFilter 审计事件查询条件，字段为空时不过滤
+type AuditFilter struct {
+	ActorID  string
+	TargetID string
+	Action   string
+	From     *time.Time
+	To       *time.Time
+}
+
+// AuditRepository 审计事件仓库接口，只允许追加
+type AuditRepository interface {
+	Append(ctx context.Context, event *models.AuditEvent) error
+	List(ctx context.Context, filter AuditFilter, offset, limit int) ([]models.AuditEvent, int64, error)
+	ListAfterSeq(ctx context.Context, seq int64, limit int) ([]models.AuditEvent, error)
+}
+
+// auditRepository 审计事件仓库实现
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository 创建审计事件仓库实例
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+// Append 追加审计事件，在事务内串行地接到哈希链末尾
+func (r *auditRepository) Append(ctx context.Context, event *models.AuditEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error; err != nil {
+			return err
+		}
+
+		var last models.AuditEvent
+		err := tx.Order("seq DESC").First(&last).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			event.Seq = 1
+			event.PrevHash = ""
+		case err != nil:
+			return err
+		default:
+			event.Seq = last.Seq + 1
+			event.PrevHash = last.Hash
+		}
+		event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		event.Hash = event.ComputeHash()
+
+		query := tx
+		if event.ActorID == "" {
+			query = query.Omit("ActorID")
+		}
+		if event.TargetID == "" {
+			query = query.Omit("TargetID")
+		}
+		return query.Create(event).Error
+	})
+}
+
+// List 按条件分页查询审计事件，按时间倒序
+func (r *auditRepository) List(ctx context.Context, filter AuditFilter, offset, limit int) ([]models.AuditEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.AuditEvent{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []models.AuditEvent
+	err := query.Order("seq DESC").
+		Offset(offset).Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// ListAfterSeq 按顺序查询序号大于 seq 的事件，用于分批校验哈希链
+func (r *auditRepository) ListAfterSeq(ctx context.Context, seq int64, limit int) ([]models.AuditEvent, error) {
+	var events []models.AuditEvent
+	err := r.db.WithContext(ctx).Where("seq > ?", seq).
+		Order("seq").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlockRepository 拉黑仓库接口
+type BlockRepository interface {
+	Create(ctx context.Context, block *models.Block) error
+	Delete(ctx context.Context, blockerID, blockedID string) (int64, error)
+	IsBlockedEither(ctx context.Context, userAID, userBID string) (bool, error)
+}
+
+// blockRepository 拉黑仓库实现
+type blockRepository struct {
+	db *gorm.DB
+}
+
+// NewBlockRepository 创建拉黑仓库实例
+func NewBlockRepository(db *gorm.DB) BlockRepository {
+	return &blockRepository{
+		db: db,
+	}
+}
+
+// Create 创建拉黑记录，重复拉黑时忽略
+func (r *blockRepository) Create(ctx context.Context, block *models.Block) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(block).Error
+}
+
+// Delete 取消拉黑，返回受影响的行数
+func (r *blockRepository) Delete(ctx context.Context, blockerID, blockedID string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.Block{})
+	return result.RowsAffected, result.Error
+}
+
+// IsBlockedEither 判断两个用户之间是否存在任一方向的拉黑
+func (r *blockRepository) IsBlockedEither(ctx context.Context, userAID, userBID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)",
+			userAID, userBID, userBID, userAID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 生效中加速的判断条件，与 models.Boost.IsActive 保持一致
+const activeBoostCondition = "boosts.starts_at <= ? AND boosts.ends_at > ?"
+
+// BoostRepository 资料加速仓库接口
+type BoostRepository interface {
+	Create(ctx context.Context, boost *models.Boost) error
+	CreateIfAbsent(ctx context.Context, boost *models.Boost) error
+	GetActiveByUser(ctx context.Context, userID string, now time.Time) (*models.Boost, error)
+	ClaimUnused(ctx context.Context, userID string, startsAt, endsAt time.Time) (*models.Boost, error)
+	CountUnused(ctx context.Context, userID string) (int64, error)
+	ListByUser(ctx context.Context, userID string, offset, limit int) ([]models.Boost, int64, error)
+	IncrementImpressions(ctx context.Context, userIDs []string, now time.Time) error
+	IncrementLikes(ctx context.Context, userID string, now time.Time) error
+}
+
+// boostRepository 资料加速仓库实现
+type boostRepository struct {
+	db *gorm.DB
+}
+
+// NewBoostRepository 创建资料加速仓库实例
+func NewBoostRepository(db *gorm.DB) BoostRepository {
+	return &boostRepository{
+		db: db,
+	}
+}
+
+// Create 创建加速记录
+func (r *boostRepository) Create(ctx context.Context, boost *models.Boost) error {
+	return r.db.WithContext(ctx).Create(boost).Error
+}
+
+// CreateIfAbsent 按支付 ID 幂等地创建加速记录，重复的回调不会重复发放
+func (r *boostRepository) CreateIfAbsent(ctx context.Context, boost *models.Boost) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider_ref"}},
+		DoNothing: true,
+	}).Create(boost).Error
+}
+
+// GetActiveByUser 查询用户生效中的加速
+func (r *boostRepository) GetActiveByUser(ctx context.Context, userID string, now time.Time) (*models.Boost, error) {
+	var boost models.Boost
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Where(activeBoostCondition, now, now).
+		Order("ends_at DESC").
+		First(&boost).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &boost, nil
+}
+
+// ClaimUnused 原子地取出一次最早购买的未使用加速并激活
+// 没有可用的加速时返回 nil
+func (r *boostRepository) ClaimUnused(ctx context.Context, userID string, startsAt, endsAt time.Time) (*models.Boost, error) {
+	var boosts []models.Boost
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE boosts SET starts_at = ?, ends_at = ?
+		WHERE id = (
+			SELECT id FROM boosts
+			WHERE user_id = ? AND starts_at IS NULL
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		startsAt, endsAt, userID,
+	).Scan(&boosts).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(boosts) == 0 {
+		return nil, nil
+	}
+	return &boosts[0], nil
+}
+
+// CountUnused 统计用户已购买但未使用的加速次数
+func (r *boostRepository) CountUnused(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Boost{}).
+		Where("user_id = ? AND starts_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// ListByUser 分页查询用户已激活的加速记录，按开始时间倒序
+func (r *boostRepository) ListByUser(ctx context.Context, userID string, offset, limit int) ([]models.Boost, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Boost{}).Where("user_id = ? AND starts_at IS NOT NULL", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var boosts []models.Boost
+	err := query.Order("starts_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&boosts).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return boosts, total, nil
+}
+
+// IncrementImpressions 为一批用户生效中的加速各记一次曝光
+func (r *boostRepository) IncrementImpressions(ctx context.Context, userIDs []string, now time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.Boost{}).
+		Where("user_id IN ?", userIDs).
+		Where(activeBoostCondition, now, now).
+		Update("impressions", gorm.Expr("impressions + 1")).Error
+}
+
+// IncrementLikes 为用户生效中的加速记一次喜欢
+func (r *boostRepository) IncrementLikes(ctx context.Context, userID string, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Boost{}).
+		Where("user_id = ?", userID).
+		Where(activeBoostCondition, now, now).
+		Update("likes", gorm.Expr("likes + 1")).Error
+}
@@ -0,0 +1,53 @@
+package repositories_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/db"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/repotest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestContract 对 PostgreSQL 实现执行契约测试
+// 需要通过 UNI_DATE_TEST_DSN 指定一个专用于测试的数据库，测试会清空其中所有表
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("UNI_DATE_TEST_DSN")
+	if dsn == "" {
+		t.Skip("未设置 UNI_DATE_TEST_DSN，跳过 PostgreSQL 契约测试")
+	}
+
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	migrator, err := db.NewMigrator(database)
+	if err != nil {
+		t.Fatalf("加载迁移失败: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+
+	repotest.Run(t, func(t *testing.T) repositories.Set {
+		var tables []string
+		err := database.Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'").
+			Scan(&tables).Error
+		if err != nil {
+			t.Fatalf("查询表失败: %v", err)
+		}
+		for i, table := range tables {
+			tables[i] = `"` + table + `"`
+		}
+		if err := database.Exec("TRUNCATE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+			t.Fatalf("清空表失败: %v", err)
+		}
+		return repositories.NewSet(database, 3)
+	})
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DataExportRepository 数据导出任务仓库接口
+type DataExportRepository interface {
+	Create(ctx context.Context, export *models.DataExport) error
+	CreateIfNoneActive(ctx context.Context, export *models.DataExport) (bool, error)
+	Update(ctx context.Context, export *models.DataExport) error
+	GetByID(ctx context.Context, id string) (*models.DataExport, error)
+	GetLatestByUser(ctx context.Context, userID string) (*models.DataExport, error)
+	ClaimPending(ctx context.Context, now time.Time) (*models.DataExport, error)
+	ListExpired(ctx context.Context, now time.Time) ([]models.DataExport, error)
+	FailStale(ctx context.Context, startedBefore time.Time) (int64, error)
+}
+
+// dataExportRepository 数据导出任务仓库实现
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository 创建数据导出任务仓库实例
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepository{
+		db: db,
+	}
+}
+
+// Create 创建导出任务
+func (r *dataExportRepository) Create(ctx context.Context, export *models.DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+// CreateIfNoneActive 用户没有待处理或处理中的导出任务时创建，已有时不创建并返回 false
+// 依赖 idx_data_exports_active 部分唯一索引，并发申请只会创建一个
+func (r *dataExportRepository) CreateIfNoneActive(ctx context.Context, export *models.DataExport) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status IN ('pending', 'processing')"}}},
+		DoNothing:   true,
+	}).Create(export)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Update 更新导出任务
+func (r *dataExportRepository) Update(ctx context.Context, export *models.DataExport) error {
+	return r.db.WithContext(ctx).Save(export).Error
+}
+
+// GetByID 通过ID查询导出任务
+func (r *dataExportRepository) GetByID(ctx context.Context, id string) (*models.DataExport, error) {
+	var export models.DataExport
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetLatestByUser 查询用户最近一次导出任务
+func (r *dataExportRepository) GetLatestByUser(ctx context.Context, userID string) (*models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ClaimPending 原子地取出一个最早创建的待处理任务并标记为处理中
+// 多实例同时执行时不会取到同一个任务，没有待处理任务时返回 nil
+func (r *dataExportRepository) ClaimPending(ctx context.Context, now time.Time) (*models.DataExport, error) {
+	var exports []models.DataExport
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE data_exports SET status = ?, started_at = ?
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = ?
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.DataExportStatusProcessing, now, models.DataExportStatusPending,
+	).Scan(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(exports) == 0 {
+		return nil, nil
+	}
+	return &exports[0], nil
+}
+
+// ListExpired 查询下载链接已过期但文件还未清理的任务
+func (r *dataExportRepository) ListExpired(ctx context.Context, now time.Time) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := r.db.WithContext(ctx).Where("status = ? AND expires_at <= ?", models.DataExportStatusReady, now).
+		Find(&exports).Error
+	return exports, err
+}
+
+// FailStale 将开始时间早于 startedBefore 仍在处理中的任务标记为失败，通常是实例中途退出
+func (r *dataExportRepository) FailStale(ctx context.Context, startedBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.DataExport{}).
+		Where("status = ? AND started_at < ?", models.DataExportStatusProcessing, startedBefore).
+		Updates(map[string]interface{}{
+			"status": models.DataExportStatusFailed,
+			"error":  "处理超时",
+		})
+	return result.RowsAffected, result.Error
+}
@@ -4,14 +4,11 @@ import (
 	"fmt"
 
 	"github.com/ShijieLu222/uni-date-server/config"
-	"github.com/ShijieLu222/uni-date-server/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
-
 // InitDB 初始化数据库连接
 func InitDB(config *config.Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -30,17 +27,10 @@ func InitDB(config *config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	DB = db
+	if config.Database.QueryTimeout > 0 {
+		if err := registerQueryTimeout(db, config.Database.QueryTimeout); err != nil {
+			return nil, err
+		}
+	}
 	return db, nil
 }
-
-// AutoMigrate 自动迁移数据库表结构
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&models.User{},
-		&models.Match{},
-		&models.Interaction{},
-		&models.Message{},
-		&models.Notification{},
-	)
-}
@@ -0,0 +1,276 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles 按版本号排序的迁移文件，命名为 <版本号>_<名称>.up.sql / .down.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus 迁移的执行状态，AppliedAt 为空表示尚未执行
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// SchemaVersionError 数据库的结构版本与程序内置的迁移不一致
+type SchemaVersionError struct {
+	Current  int64
+	Expected int64
+}
+
+func (e *SchemaVersionError) Error() string {
+	if e.Current < e.Expected {
+		return fmt.Sprintf("数据库结构版本为 %d，程序需要 %d，请先执行 migrate up", e.Current, e.Expected)
+	}
+	return fmt.Sprintf("数据库结构版本为 %d，高于程序支持的 %d，请升级程序", e.Current, e.Expected)
+}
+
+// createSchemaMigrations 记录已执行迁移的表
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS "schema_migrations" (
+  "version" BIGINT PRIMARY KEY,
+  "name" VARCHAR(255) NOT NULL,
+  "applied_at" TIMESTAMP WITH TIME ZONE NOT NULL
+)`
+
+// schemaMigration schema_migrations 表中的一条记录
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator 数据库迁移接口
+type Migrator interface {
+	// Up 按顺序执行所有未执行的迁移，返回本次执行的迁移
+	Up() ([]Migration, error)
+	// Down 按倒序回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+	Down(steps int) ([]Migration, error)
+	Status() ([]MigrationStatus, error)
+	// CheckVersion 检查数据库的结构版本是否与内置的最新迁移一致，不一致时返回 *SchemaVersionError
+	CheckVersion() error
+}
+
+// migrator 数据库迁移实现，每个迁移在独立的事务中执行
+type migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator 创建数据库迁移实例
+// 建索引、改表等迁移可能远超单条语句的超时，迁移执行的语句不附加查询超时
+func NewMigrator(db *gorm.DB) (Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &migrator{
+		db:         db.WithContext(withoutQueryTimeout(context.Background())),
+		migrations: migrations,
+	}, nil
+}
+
+// Up 按顺序执行所有未执行的迁移
+func (m *migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("执行迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down 按倒序回滚最近执行的 steps 个迁移
+func (m *migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("回滚迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Status 返回所有内置迁移的执行状态
+func (m *migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// CheckVersion 检查数据库的结构版本，中间有遗漏的迁移同样视为版本不一致
+func (m *migrator) CheckVersion() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	var current int64
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	var expected int64
+	if len(m.migrations) > 0 {
+		expected = m.migrations[len(m.migrations)-1].Version
+	}
+
+	if current != expected {
+		return &SchemaVersionError{Current: current, Expected: expected}
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			return &SchemaVersionError{Current: migration.Version - 1, Expected: expected}
+		}
+	}
+	return nil
+}
+
+// applied 查询已执行的迁移，schema_migrations 表不存在时自动创建
+func (m *migrator) applied() (map[int64]schemaMigration, error) {
+	if err := m.db.Exec(createSchemaMigrations).Error; err != nil {
+		return nil, err
+	}
+
+	var records []schemaMigration
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// loadMigrations 读取迁移文件并按版本号排序，每个版本必须同时有 up 和 down 文件
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(files, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, path := range paths {
+		base := strings.TrimPrefix(path, "migrations/")
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("无法识别的迁移文件: %s", base)
+		}
+		base = strings.TrimSuffix(strings.TrimSuffix(base, ".sql"), "."+direction)
+
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !ok || err != nil || version < 1 {
+			return nil, fmt.Errorf("迁移文件名缺少版本号: %s", path)
+		}
+
+		content, err := fs.ReadFile(files, path)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("迁移版本 %d 重复: %s 和 %s", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("迁移 %d_%s 缺少 up 或 down 文件", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
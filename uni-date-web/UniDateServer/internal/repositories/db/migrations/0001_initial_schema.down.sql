@@ -0,0 +1,22 @@
+-- 回滚初始表结构，会删除所有数据
+
+DROP TABLE IF EXISTS "privacy_settings";
+DROP TABLE IF EXISTS "data_exports";
+DROP TABLE IF EXISTS "photo_blocklist";
+DROP TABLE IF EXISTS "photos";
+DROP TABLE IF EXISTS "moderation_items";
+DROP TABLE IF EXISTS "boosts";
+DROP TABLE IF EXISTS "appeals";
+DROP TABLE IF EXISTS "reports";
+DROP TABLE IF EXISTS "audit_events";
+DROP TABLE IF EXISTS "blocks";
+DROP TABLE IF EXISTS "usage_counters";
+DROP TABLE IF EXISTS "subscriptions";
+DROP TABLE IF EXISTS "push_preferences";
+DROP TABLE IF EXISTS "devices";
+DROP TABLE IF EXISTS "notifications";
+DROP TABLE IF EXISTS "messages";
+DROP TABLE IF EXISTS "interactions";
+DROP TABLE IF EXISTS "matches";
+DROP TABLE IF EXISTS "users";
+DROP FUNCTION IF EXISTS audit_events_immutable();
@@ -0,0 +1,302 @@
+-- 初始表结构
+-- 使用 IF NOT EXISTS，已经通过旧的 scripts/init_db.sql 手动建表的数据库也可以直接执行：
+-- 旧脚本只创建了 users、matches、interactions、messages、notifications 五张表，
+-- 其中 users 表之后新增的列由下方的 ADD COLUMN IF NOT EXISTS 补齐，其余表和索引在这里新建
+
+-- gen_random_uuid 在 PostgreSQL 13 起内置，更早的版本由 pgcrypto 提供
+CREATE EXTENSION IF NOT EXISTS "pgcrypto";
+
+-- 创建用户表
+CREATE TABLE IF NOT EXISTS "users" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "name" VARCHAR(100) NOT NULL,
+  "phone" VARCHAR(20) UNIQUE,
+  "account" VARCHAR(100) NOT NULL UNIQUE,
+  "password" VARCHAR(255) NOT NULL,
+  "avatar" VARCHAR(255),
+  "birthdate" DATE,
+  "gender" VARCHAR(10),
+  "university" VARCHAR(100) NOT NULL,
+  "major" VARCHAR(100),
+  "photos" TEXT[],
+  "interests" TEXT[],
+  "is_verified" BOOLEAN DEFAULT FALSE,
+  "is_vip" BOOLEAN DEFAULT FALSE,
+  "timezone" VARCHAR(50) DEFAULT 'Asia/Shanghai',
+  "role" VARCHAR(20) NOT NULL DEFAULT 'user',
+  "status" VARCHAR(20) NOT NULL DEFAULT 'active',
+  "status_reason" TEXT,
+  "suspended_until" TIMESTAMP WITH TIME ZONE,
+  "registration_ip" VARCHAR(45),
+  "risk_score" INTEGER DEFAULT 0,
+  "risk_level" VARCHAR(20),
+  "risk_cleared_at" TIMESTAMP WITH TIME ZONE,
+  "purge_after" TIMESTAMP WITH TIME ZONE,
+  "version" INTEGER NOT NULL DEFAULT 1,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  "deleted_at" TIMESTAMP WITH TIME ZONE
+);
+
+-- 旧的 init_db.sql 创建的 users 表缺少以下列，新建的数据库上这些语句不会做任何修改
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "timezone" VARCHAR(50) DEFAULT 'Asia/Shanghai';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "status" VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "status_reason" TEXT;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "suspended_until" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "registration_ip" VARCHAR(45);
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "risk_score" INTEGER DEFAULT 0;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "risk_level" VARCHAR(20);
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "risk_cleared_at" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "purge_after" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "version" INTEGER NOT NULL DEFAULT 1;
+
+-- 创建匹配表
+CREATE TABLE IF NOT EXISTS "matches" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "user1_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "user2_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "matched_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  "is_active" BOOLEAN DEFAULT TRUE
+);
+
+-- 创建交互记录表
+CREATE TABLE IF NOT EXISTS "interactions" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "from_user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "to_user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "type" VARCHAR(10) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建消息表
+CREATE TABLE IF NOT EXISTS "messages" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "match_id" UUID NOT NULL REFERENCES "matches"("id") ON DELETE CASCADE,
+  "sender_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "receiver_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "content" TEXT NOT NULL,
+  "content_type" VARCHAR(10) NOT NULL,
+  "is_read" BOOLEAN DEFAULT FALSE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建通知表
+CREATE TABLE IF NOT EXISTS "notifications" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "type" VARCHAR(20) NOT NULL,
+  "content" TEXT NOT NULL,
+  "is_read" BOOLEAN DEFAULT FALSE,
+  "related_id" UUID,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建推送设备表
+CREATE TABLE IF NOT EXISTS "devices" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "token" VARCHAR(255) NOT NULL UNIQUE,
+  "platform" VARCHAR(10) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建推送偏好表
+CREATE TABLE IF NOT EXISTS "push_preferences" (
+  "user_id" UUID PRIMARY KEY REFERENCES "users"("id") ON DELETE CASCADE,
+  "mute_match" BOOLEAN DEFAULT FALSE,
+  "mute_message" BOOLEAN DEFAULT FALSE,
+  "mute_like" BOOLEAN DEFAULT FALSE,
+  "mute_system" BOOLEAN DEFAULT FALSE,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建VIP订阅表
+CREATE TABLE IF NOT EXISTS "subscriptions" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "plan" VARCHAR(20) NOT NULL,
+  "status" VARCHAR(20) NOT NULL,
+  "start_date" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "end_date" TIMESTAMP WITH TIME ZONE,
+  "cancel_at_period_end" BOOLEAN DEFAULT FALSE,
+  "payment_method" VARCHAR(20),
+  "amount" BIGINT,
+  "currency" VARCHAR(10),
+  "provider_ref" VARCHAR(255) UNIQUE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建每日使用次数表
+CREATE TABLE IF NOT EXISTS "usage_counters" (
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "feature" VARCHAR(20) NOT NULL,
+  "day" DATE NOT NULL,
+  "count" INTEGER NOT NULL DEFAULT 0,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  PRIMARY KEY ("user_id", "feature", "day")
+);
+
+-- 创建拉黑表
+CREATE TABLE IF NOT EXISTS "blocks" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "blocker_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "blocked_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建审计事件表，只允许追加，seq/prev_hash/hash 构成哈希链
+CREATE TABLE IF NOT EXISTS "audit_events" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "seq" BIGINT NOT NULL UNIQUE,
+  "actor_id" UUID,
+  "action" VARCHAR(50) NOT NULL,
+  "target_id" UUID,
+  "ip" VARCHAR(45),
+  "user_agent" TEXT,
+  "request_id" VARCHAR(64),
+  "metadata" JSONB,
+  "prev_hash" VARCHAR(64) NOT NULL,
+  "hash" VARCHAR(64) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- 禁止修改或删除审计事件
+CREATE OR REPLACE FUNCTION audit_events_immutable() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_no_update_delete ON audit_events;
+CREATE TRIGGER audit_events_no_update_delete
+  BEFORE UPDATE OR DELETE ON audit_events
+  FOR EACH ROW EXECUTE FUNCTION audit_events_immutable();
+
+-- 创建举报表
+CREATE TABLE IF NOT EXISTS "reports" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "reporter_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "reported_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "reason" VARCHAR(50) NOT NULL,
+  "detail" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建申诉表
+CREATE TABLE IF NOT EXISTS "appeals" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "user_status" VARCHAR(20) NOT NULL,
+  "message" TEXT NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+  "reviewer_id" UUID REFERENCES "users"("id"),
+  "review_note" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  "reviewed_at" TIMESTAMP WITH TIME ZONE
+);
+
+-- 创建资料加速表
+CREATE TABLE IF NOT EXISTS "boosts" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "source" VARCHAR(20) NOT NULL,
+  "provider_ref" VARCHAR(255) UNIQUE,
+  "starts_at" TIMESTAMP WITH TIME ZONE,
+  "ends_at" TIMESTAMP WITH TIME ZONE,
+  "impressions" INTEGER NOT NULL DEFAULT 0,
+  "likes" INTEGER NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建人工审核队列表
+CREATE TABLE IF NOT EXISTS "moderation_items" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "source" VARCHAR(20) NOT NULL,
+  "subject_user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "ref_id" UUID,
+  "field" VARCHAR(20),
+  "content" TEXT,
+  "rules" JSONB,
+  "similarity" DOUBLE PRECISION,
+  "matched_ref" VARCHAR(36),
+  "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+  "reviewer_id" UUID REFERENCES "users"("id"),
+  "review_note" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  "reviewed_at" TIMESTAMP WITH TIME ZONE
+);
+
+-- 创建照片表，hash 为 64 位感知哈希
+CREATE TABLE IF NOT EXISTS "photos" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "url" VARCHAR(255) NOT NULL,
+  "hash" BIGINT NOT NULL,
+  "width" INTEGER,
+  "height" INTEGER,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建违规图片表
+CREATE TABLE IF NOT EXISTS "photo_blocklist" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "hash" BIGINT NOT NULL,
+  "label" VARCHAR(100) NOT NULL,
+  "added_by" UUID NOT NULL REFERENCES "users"("id"),
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建个人数据导出表
+CREATE TABLE IF NOT EXISTS "data_exports" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+  "file_path" VARCHAR(255),
+  "error" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  "started_at" TIMESTAMP WITH TIME ZONE,
+  "completed_at" TIMESTAMP WITH TIME ZONE,
+  "expires_at" TIMESTAMP WITH TIME ZONE
+);
+
+-- 创建隐私设置表
+CREATE TABLE IF NOT EXISTS "privacy_settings" (
+  "user_id" UUID PRIMARY KEY REFERENCES "users"("id") ON DELETE CASCADE,
+  "hide_age" BOOLEAN NOT NULL DEFAULT FALSE,
+  "hide_university" BOOLEAN NOT NULL DEFAULT FALSE,
+  "incognito" BOOLEAN NOT NULL DEFAULT FALSE,
+  "hide_last_active" BOOLEAN NOT NULL DEFAULT FALSE,
+  "discoverable" BOOLEAN NOT NULL DEFAULT TRUE,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_users_account ON users(account);
+CREATE INDEX IF NOT EXISTS idx_matches_users ON matches(user1_id, user2_id);
+CREATE INDEX IF NOT EXISTS idx_interactions_users ON interactions(from_user_id, to_user_id);
+CREATE INDEX IF NOT EXISTS idx_messages_match ON messages(match_id);
+CREATE INDEX IF NOT EXISTS idx_messages_sender_receiver ON messages(sender_id, receiver_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id);
+CREATE INDEX IF NOT EXISTS idx_devices_user ON devices(user_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user ON subscriptions(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_blocks_pair ON blocks(blocker_id, blocked_id);
+CREATE INDEX IF NOT EXISTS idx_blocks_blocked ON blocks(blocked_id);
+CREATE INDEX IF NOT EXISTS idx_boosts_user ON boosts(user_id);
+CREATE INDEX IF NOT EXISTS idx_boosts_ends_at ON boosts(ends_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events(action);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events(target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_reports_reporter ON reports(reporter_id);
+CREATE INDEX IF NOT EXISTS idx_reports_reported ON reports(reported_id);
+CREATE INDEX IF NOT EXISTS idx_appeals_user ON appeals(user_id);
+CREATE INDEX IF NOT EXISTS idx_appeals_status ON appeals(status);
+CREATE INDEX IF NOT EXISTS idx_moderation_items_subject ON moderation_items(subject_user_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_items_status ON moderation_items(status);
+CREATE INDEX IF NOT EXISTS idx_users_registration_ip ON users(registration_ip);
+CREATE INDEX IF NOT EXISTS idx_photos_user ON photos(user_id);
+CREATE INDEX IF NOT EXISTS idx_data_exports_user ON data_exports(user_id);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports(status);
@@ -0,0 +1,4 @@
+-- 记录用户上次修改时区的时间，用于限制修改频率
+-- 每日配额按用户时区的零点重置，频繁切换时区可以多次重置配额
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "timezone_changed_at" TIMESTAMP WITH TIME ZONE;
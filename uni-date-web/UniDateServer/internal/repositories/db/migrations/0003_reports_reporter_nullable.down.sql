@@ -0,0 +1,2 @@
+DELETE FROM "reports" WHERE "reporter_id" IS NULL;
+ALTER TABLE "reports" ALTER COLUMN "reporter_id" SET NOT NULL;
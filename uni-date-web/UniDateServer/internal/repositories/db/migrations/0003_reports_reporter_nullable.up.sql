@@ -0,0 +1,3 @@
+-- 注销账号清除数据时保留其提交的举报作为审核证据，只清空举报人
+
+ALTER TABLE "reports" ALTER COLUMN "reporter_id" DROP NOT NULL;
@@ -0,0 +1,12 @@
+-- 同一用户同时只能有一个待处理或处理中的导出任务，并发申请时由数据库保证只创建一个
+-- 建索引前将重复的任务标记为失败，每个用户只保留最新的一个
+
+UPDATE "data_exports" SET "status" = 'failed', "error" = '重复的导出任务'
+WHERE "status" IN ('pending', 'processing')
+  AND "id" NOT IN (
+    SELECT DISTINCT ON ("user_id") "id" FROM "data_exports"
+    WHERE "status" IN ('pending', 'processing')
+    ORDER BY "user_id", "created_at" DESC
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_exports_active ON data_exports(user_id) WHERE status IN ('pending', 'processing');
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/go-redis/redis/v8"
+)
+
+// InitRedis 初始化 Redis 连接
+func InitRedis(config *config.Config) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", config.Redis.Host, config.Redis.Port),
+		Password: config.Redis.Password,
+		DB:       config.Redis.DB,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
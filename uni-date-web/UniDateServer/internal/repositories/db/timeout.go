@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 保存当前语句超时状态的键
+const queryTimeoutKey = "uni_date:query_timeout"
+
+// skipQueryTimeoutKey 标记上下文中的语句不附加超时
+type skipQueryTimeoutKey struct{}
+
+// withoutQueryTimeout 返回不附加语句超时的上下文，迁移等耗时的维护操作使用
+func withoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipQueryTimeoutKey{}, true)
+}
+
+// queryTimeout 当前语句的超时状态
+type queryTimeout struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// registerQueryTimeout 为每条 SQL 附加超时：在调用方上下文的基础上再限制 timeout，语句执行完成后立即释放
+// 释放后恢复调用方的上下文，同一个查询对象先 Count 再 Find 时后一条语句不会拿到已取消的上下文
+// Row/Rows 在回调返回后才读取结果，不在这里加超时，由调用方的上下文控制
+// 上下文经 withoutQueryTimeout 标记时不加超时
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	begin := func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if skip, _ := parent.Value(skipQueryTimeoutKey{}).(bool); skip {
+			return
+		}
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryTimeoutKey, queryTimeout{parent: parent, cancel: cancel})
+	}
+	end := func(tx *gorm.DB) {
+		if value, ok := tx.InstanceGet(queryTimeoutKey); ok {
+			state := value.(queryTimeout)
+			state.cancel()
+			tx.Statement.Context = state.parent
+		}
+	}
+
+	callbacks := db.Callback()
+	registers := []func() error{
+		func() error {
+			return callbacks.Query().Before("gorm:query").Register("uni_date:query_timeout_begin", begin)
+		},
+		func() error { return callbacks.Query().After("gorm:query").Register("uni_date:query_timeout_end", end) },
+		func() error {
+			return callbacks.Create().Before("gorm:create").Register("uni_date:create_timeout_begin", begin)
+		},
+		func() error {
+			return callbacks.Create().After("gorm:create").Register("uni_date:create_timeout_end", end)
+		},
+		func() error {
+			return callbacks.Update().Before("gorm:update").Register("uni_date:update_timeout_begin", begin)
+		},
+		func() error {
+			return callbacks.Update().After("gorm:update").Register("uni_date:update_timeout_end", end)
+		},
+		func() error {
+			return callbacks.Delete().Before("gorm:delete").Register("uni_date:delete_timeout_begin", begin)
+		},
+		func() error {
+			return callbacks.Delete().After("gorm:delete").Register("uni_date:delete_timeout_end", end)
+		},
+		func() error { return callbacks.Raw().Before("gorm:raw").Register("uni_date:raw_timeout_begin", begin) },
+		func() error { return callbacks.Raw().After("gorm:raw").Register("uni_date:raw_timeout_end", end) },
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
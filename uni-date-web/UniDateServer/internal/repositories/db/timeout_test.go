@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type timeoutRecord struct {
+	ID   string
+	Name string
+}
+
+func TestQueryTimeoutCountThenFind(t *testing.T) {
+	database, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	if err := registerQueryTimeout(database, time.Second); err != nil {
+		t.Fatalf("注册超时回调失败: %v", err)
+	}
+
+	// 记录每条语句执行时上下文的状态
+	var seen []error
+	var deadlines []bool
+	err = database.Callback().Query().After("uni_date:query_timeout_begin").Before("gorm:query").
+		Register("test:record_context", func(tx *gorm.DB) {
+			_, hasDeadline := tx.Statement.Context.Deadline()
+			seen = append(seen, tx.Statement.Context.Err())
+			deadlines = append(deadlines, hasDeadline)
+		})
+	if err != nil {
+		t.Fatalf("注册记录回调失败: %v", err)
+	}
+
+	parent := context.Background()
+	query := database.WithContext(parent).Model(&timeoutRecord{}).Where("name = ?", "a")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		t.Fatalf("Count 失败: %v", err)
+	}
+	if query.Statement.Context != parent {
+		t.Fatalf("Count 之后没有恢复调用方的上下文")
+	}
+
+	var records []timeoutRecord
+	if err := query.Limit(10).Find(&records).Error; err != nil {
+		t.Fatalf("Find 失败: %v", err)
+	}
+
+	if len(seen) != 2 {
+		t.Fatalf("期望执行 2 条语句，实际 %d 条", len(seen))
+	}
+	for i := range seen {
+		if seen[i] != nil {
+			t.Errorf("第 %d 条语句执行时上下文已失效: %v", i+1, seen[i])
+		}
+		if !deadlines[i] {
+			t.Errorf("第 %d 条语句没有附加超时", i+1)
+		}
+	}
+}
+
+func TestQueryTimeoutKeepsCallerCancellation(t *testing.T) {
+	database, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	if err := registerQueryTimeout(database, time.Second); err != nil {
+		t.Fatalf("注册超时回调失败: %v", err)
+	}
+
+	var seen error
+	err = database.Callback().Query().After("uni_date:query_timeout_begin").Before("gorm:query").
+		Register("test:record_context", func(tx *gorm.DB) {
+			seen = tx.Statement.Context.Err()
+		})
+	if err != nil {
+		t.Fatalf("注册记录回调失败: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var records []timeoutRecord
+	database.WithContext(ctx).Find(&records)
+	if seen != context.Canceled {
+		t.Fatalf("期望语句继承调用方的取消，实际 %v", seen)
+	}
+}
+
+func TestMigratorSkipsQueryTimeout(t *testing.T) {
+	database, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	if err := registerQueryTimeout(database, time.Second); err != nil {
+		t.Fatalf("注册超时回调失败: %v", err)
+	}
+
+	var deadlines []bool
+	record := func(tx *gorm.DB) {
+		_, hasDeadline := tx.Statement.Context.Deadline()
+		deadlines = append(deadlines, hasDeadline)
+	}
+	if err := database.Callback().Raw().After("uni_date:raw_timeout_begin").Before("gorm:raw").Register("test:record_context", record); err != nil {
+		t.Fatalf("注册记录回调失败: %v", err)
+	}
+
+	m, err := NewMigrator(database)
+	if err != nil {
+		t.Fatalf("加载迁移失败: %v", err)
+	}
+	m.(*migrator).db.Exec("CREATE INDEX CONCURRENTLY idx ON t (c)")
+	database.Exec("SELECT 1")
+
+	if len(deadlines) != 2 {
+		t.Fatalf("期望执行 2 条语句，实际 %d 条", len(deadlines))
+	}
+	if deadlines[0] {
+		t.Errorf("迁移语句不应附加超时")
+	}
+	if !deadlines[1] {
+		t.Errorf("普通语句应附加超时")
+	}
+}
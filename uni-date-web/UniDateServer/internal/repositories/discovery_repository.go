@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// Candidate 推荐候选人及其是否处于加速中
+type Candidate struct {
+	User    models.User `gorm:"embedded"`
+	Boosted bool
+}
+
+// DiscoveryRepository 推荐仓库接口
+type DiscoveryRepository interface {
+	ListCandidates(ctx context.Context, userID, university string, now time.Time, limit int) ([]Candidate, error)
+	IsDiscoverable(ctx context.Context, viewerID, userID string) (bool, error)
+}
+
+// discoveryRepository 推荐仓库实现
+type discoveryRepository struct {
+	db *gorm.DB
+}
+
+// NewDiscoveryRepository 创建推荐仓库实例
+func NewDiscoveryRepository(db *gorm.DB) DiscoveryRepository {
+	return &discoveryRepository{
+		db: db,
+	}
+}
+
+// ListCandidates 查询用户还没有操作过的推荐候选人
+// 同校用户优先；同一梯队内加速中的用户排在前面
+func (r *discoveryRepository) ListCandidates(ctx context.Context, userID, university string, now time.Time, limit int) ([]Candidate, error) {
+	var candidates []Candidate
+	err := visibleTo(r.db.WithContext(ctx).Table("users"), userID).
+		Select("users.*, EXISTS (SELECT 1 FROM boosts WHERE boosts.user_id = users.id AND "+activeBoostCondition+") AS boosted", now, now).
+		Where("NOT EXISTS (SELECT 1 FROM interactions WHERE interactions.from_user_id = ? AND interactions.to_user_id = users.id)", userID).
+		Order(gorm.Expr("(users.university = ?) DESC, boosted DESC, users.created_at DESC", university)).
+		Limit(limit).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// IsDiscoverable 判断用户是否可以出现在查看者的推荐中，不考虑查看者是否已经操作过
+func (r *discoveryRepository) IsDiscoverable(ctx context.Context, viewerID, userID string) (bool, error) {
+	var count int64
+	err := visibleTo(r.db.WithContext(ctx).Table("users"), viewerID).
+		Where("users.id = ?", userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// visibleTo 限定为对查看者可见的用户
+// 排除查看者自己、已注销、被暂停/封禁或被限制曝光的用户和任一方向存在拉黑关系的用户
+// 排除关闭了可被发现的用户；开启隐身模式的 VIP 只对自己喜欢过的人可见
+func visibleTo(query *gorm.DB, viewerID string) *gorm.DB {
+	return query.
+		Joins("LEFT JOIN privacy_settings ON privacy_settings.user_id = users.id").
+		Where("users.id <> ? AND users.deleted_at IS NULL AND users.status = ?", viewerID, models.UserStatusActive).
+		Where("users.risk_level IS DISTINCT FROM ?", models.RiskLevelShadowLimited).
+		Where("COALESCE(privacy_settings.discoverable, TRUE)").
+		Where("(NOT (COALESCE(privacy_settings.incognito, FALSE) AND users.is_vip) OR EXISTS (SELECT 1 FROM interactions WHERE interactions.from_user_id = users.id AND interactions.to_user_id = ? AND interactions.type = ?))", viewerID, models.InteractionTypeLike).
+		Where("NOT EXISTS (SELECT 1 FROM blocks WHERE (blocks.blocker_id = ? AND blocks.blocked_id = users.id) OR (blocks.blocker_id = users.id AND blocks.blocked_id = ?))", viewerID, viewerID)
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// InteractionRepository 用户交互（滑动）仓库接口
+type InteractionRepository interface {
+	Create(ctx context.Context, interaction *models.Interaction) error
+	GetByUsers(ctx context.Context, fromUserID, toUserID string) (*models.Interaction, error)
+	GetLatestByUser(ctx context.Context, fromUserID string) (*models.Interaction, error)
+	Delete(ctx context.Context, id string) (int64, error)
+	ListPendingLikes(ctx context.Context, userID string, offset, limit int) ([]ReceivedLike, int64, error)
+	ListAllByUser(ctx context.Context, fromUserID string) ([]models.Interaction, error)
+}
+
+// ReceivedLike 收到的喜欢及对方资料
+type ReceivedLike struct {
+	User    models.User `gorm:"embedded"`
+	LikedAt time.Time
+}
+
+// interactionRepository 用户交互仓库实现
+type interactionRepository struct {
+	db *gorm.DB
+}
+
+// NewInteractionRepository 创建用户交互仓库实例
+func NewInteractionRepository(db *gorm.DB) InteractionRepository {
+	return &interactionRepository{
+		db: db,
+	}
+}
+
+// Create 创建交互记录
+func (r *interactionRepository) Create(ctx context.Context, interaction *models.Interaction) error {
+	return r.db.WithContext(ctx).Create(interaction).Error
+}
+
+// GetByUsers 查询 from 对 to 的交互记录
+func (r *interactionRepository) GetByUsers(ctx context.Context, fromUserID, toUserID string) (*models.Interaction, error) {
+	var interaction models.Interaction
+	err := r.db.WithContext(ctx).Where("from_user_id = ? AND to_user_id = ?", fromUserID, toUserID).
+		Order("created_at DESC").
+		First(&interaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &interaction, nil
+}
+
+// GetLatestByUser 查询用户最近一次交互
+func (r *interactionRepository) GetLatestByUser(ctx context.Context, fromUserID string) (*models.Interaction, error) {
+	var interaction models.Interaction
+	err := r.db.WithContext(ctx).Where("from_user_id = ?", fromUserID).
+		Order("created_at DESC").
+		First(&interaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &interaction, nil
+}
+
+// Delete 删除交互记录，返回删除的行数
+func (r *interactionRepository) Delete(ctx context.Context, id string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Interaction{})
+	return result.RowsAffected, result.Error
+}
+
+// ListPendingLikes 分页查询喜欢了该用户、但该用户尚未操作过的人，按喜欢时间倒序
+// 排除任一方向存在拉黑关系的用户、已注销、被封禁和被限制曝光的用户
+func (r *interactionRepository) ListPendingLikes(ctx context.Context, userID string, offset, limit int) ([]ReceivedLike, int64, error) {
+	query := r.db.WithContext(ctx).Table("interactions").
+		Joins("JOIN users ON users.id = interactions.from_user_id AND users.deleted_at IS NULL AND users.status <> ? AND users.risk_level IS DISTINCT FROM ?", models.UserStatusBanned, models.RiskLevelShadowLimited).
+		Where("interactions.to_user_id = ? AND interactions.type = ?", userID, models.InteractionTypeLike).
+		Where("NOT EXISTS (SELECT 1 FROM interactions mine WHERE mine.from_user_id = ? AND mine.to_user_id = interactions.from_user_id)", userID).
+		Where("NOT EXISTS (SELECT 1 FROM blocks WHERE (blocks.blocker_id = ? AND blocks.blocked_id = interactions.from_user_id) OR (blocks.blocker_id = interactions.from_user_id AND blocks.blocked_id = ?))", userID, userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var likes []ReceivedLike
+	err := query.Select("users.*, interactions.created_at AS liked_at").
+		Order("interactions.created_at DESC").
+		Offset(offset).Limit(limit).
+		Scan(&likes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return likes, total, nil
+}
+
+// ListAllByUser 查询用户发起的全部交互，按时间正序
+func (r *interactionRepository) ListAllByUser(ctx context.Context, fromUserID string) ([]models.Interaction, error) {
+	var interactions []models.Interaction
+	err := r.db.WithContext(ctx).Where("from_user_id = ?", fromUserID).
+		Order("created_at ASC").
+		Find(&interactions).Error
+	return interactions, err
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// MatchRepository 匹配仓库接口
+type MatchRepository interface {
+	Create(ctx context.Context, match *models.Match) error
+	GetByID(ctx context.Context, id string) (*models.Match, error)
+	GetByUsers(ctx context.Context, userAID, userBID string) (*models.Match, error)
+	Deactivate(ctx context.Context, userAID, userBID string) error
+	Delete(ctx context.Context, id string) error
+	DeactivateAllForUser(ctx context.Context, userID string) error
+	ListAllByUser(ctx context.Context, userID string) ([]models.Match, error)
+}
+
+// matchRepository 匹配仓库实现
+type matchRepository struct {
+	db *gorm.DB
+}
+
+// NewMatchRepository 创建匹配仓库实例
+func NewMatchRepository(db *gorm.DB) MatchRepository {
+	return &matchRepository{
+		db: db,
+	}
+}
+
+// Create 创建匹配
+func (r *matchRepository) Create(ctx context.Context, match *models.Match) error {
+	return r.db.WithContext(ctx).Create(match).Error
+}
+
+// GetByID 通过ID查询匹配
+func (r *matchRepository) GetByID(ctx context.Context, id string) (*models.Match, error) {
+	var match models.Match
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&match).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &match, nil
+}
+
+// GetByUsers 查询两个用户之间的匹配，与双方顺序无关
+func (r *matchRepository) GetByUsers(ctx context.Context, userAID, userBID string) (*models.Match, error) {
+	var match models.Match
+	err := r.db.WithContext(ctx).Where("(user1_id = ? AND user2_id = ?) OR (user1_id = ? AND user2_id = ?)",
+		userAID, userBID, userBID, userAID).
+		First(&match).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &match, nil
+}
+
+// Deactivate 将两个用户之间的匹配设为失效
+func (r *matchRepository) Deactivate(ctx context.Context, userAID, userBID string) error {
+	return r.db.WithContext(ctx).Model(&models.Match{}).
+		Where("(user1_id = ? AND user2_id = ?) OR (user1_id = ? AND user2_id = ?)",
+			userAID, userBID, userBID, userAID).
+		Update("is_active", false).Error
+}
+
+// Delete 删除匹配
+func (r *matchRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Match{}).Error
+}
+
+// DeactivateAllForUser 将用户的所有匹配设为失效
+func (r *matchRepository) DeactivateAllForUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Model(&models.Match{}).
+		Where("user1_id = ? OR user2_id = ?", userID, userID).
+		Update("is_active", false).Error
+}
+
+// ListAllByUser 查询用户参与的全部匹配，包括已失效的，按匹配时间正序
+func (r *matchRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.db.WithContext(ctx).Where("user1_id = ? OR user2_id = ?", userID, userID).
+		Order("matched_at ASC").
+		Find(&matches).Error
+	return matches, err
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// accountPurgeRepository 注销账号数据清除仓库的内存实现
+type accountPurgeRepository struct {
+	store *Store
+}
+
+// NewAccountPurgeRepository 创建注销账号数据清除仓库实例
+func NewAccountPurgeRepository(store *Store) repositories.AccountPurgeRepository {
+	return &accountPurgeRepository{
+		store: store,
+	}
+}
+
+// ListDue 查询宽限期已过、等待清除数据的账号
+func (r *accountPurgeRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	users := filterUsers(r.store.tables.users, func(user models.User) bool {
+		return user.DeletedAt.Valid && user.PurgeAfter != nil && !user.PurgeAfter.After(now)
+	})
+	sortBy(users, func(a, b models.User) bool {
+		return a.PurgeAfter.Before(*b.PurgeAfter)
+	})
+	return paginate(users, 0, limit), nil
+}
+
+// ListExportFiles 查询用户尚未删除的数据导出文件
+func (r *accountPurgeRepository) ListExportFiles(ctx context.Context, userID string) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var files []string
+	for _, export := range r.store.tables.dataExports {
+		if export.UserID == userID && export.FilePath != "" {
+			files = append(files, export.FilePath)
+		}
+	}
+	return files, nil
+}
+
+// Purge 删除用户的个人数据，并将账号改写为不含个人信息的占位记录
+// 与 PostgreSQL 实现保持一致：聊天记录保留给对方，订阅、审计、举报和审核队列不删除
+func (r *accountPurgeRepository) Purge(ctx context.Context, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t := r.store.tables
+	deleteWhere(t.interactions, func(interaction models.Interaction) bool {
+		return interaction.FromUserID == userID || interaction.ToUserID == userID
+	})
+	deleteWhere(t.notifications, func(notification models.Notification) bool {
+		return notification.UserID == userID
+	})
+	deleteWhere(t.devices, func(device models.Device) bool {
+		return device.UserID == userID
+	})
+	delete(t.pushPreferences, userID)
+	deleteWhere(t.usageCounters, func(counter models.UsageCounter) bool {
+		return counter.UserID == userID
+	})
+	deleteWhere(t.blocks, func(block models.Block) bool {
+		return block.BlockerID == userID || block.BlockedID == userID
+	})
+	for id, report := range t.reports {
+		if report.ReporterID != nil && *report.ReporterID == userID {
+			report.ReporterID = nil
+			t.reports[id] = report
+		}
+	}
+	deleteWhere(t.appeals, func(appeal models.Appeal) bool {
+		return appeal.UserID == userID
+	})
+	deleteWhere(t.boosts, func(boost models.Boost) bool {
+		return boost.UserID == userID
+	})
+	deleteWhere(t.photos, func(photo models.Photo) bool {
+		return photo.UserID == userID
+	})
+	deleteWhere(t.dataExports, func(export models.DataExport) bool {
+		return export.UserID == userID
+	})
+	delete(t.privacySettings, userID)
+	deactivateMatches(t, func(match models.Match) bool {
+		return match.User1ID == userID || match.User2ID == userID
+	})
+
+	user, ok := t.users[userID]
+	if !ok {
+		return nil
+	}
+	t.users[userID] = models.User{
+		ID:        user.ID,
+		Name:      models.DeletedUserName,
+		Account:   "deleted:" + user.ID,
+		Timezone:  "Asia/Shanghai",
+		Role:      models.UserRoleUser,
+		Status:    models.UserStatusActive,
+		Version:   user.Version,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: r.store.now(),
+		DeletedAt: user.DeletedAt,
+	}
+	return nil
+}
+
+// deleteWhere 删除表中满足条件的记录
+func deleteWhere[K comparable, V any](table map[K]V, match func(V) bool) {
+	for key, value := range table {
+		if match(value) {
+			delete(table, key)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// appealRepository 申诉仓库的内存实现
+type appealRepository struct {
+	store *Store
+}
+
+// NewAppealRepository 创建申诉仓库实例
+func NewAppealRepository(store *Store) repositories.AppealRepository {
+	return &appealRepository{
+		store: store,
+	}
+}
+
+// Create 创建申诉
+func (r *appealRepository) Create(ctx context.Context, appeal *models.Appeal) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if appeal.ID == "" {
+		appeal.ID = newID()
+	}
+	if appeal.Status == "" {
+		appeal.Status = models.AppealStatusPending
+	}
+	appeal.CreatedAt = r.store.now()
+	r.store.tables.appeals[appeal.ID] = *appeal
+	return nil
+}
+
+// Update 更新申诉
+func (r *appealRepository) Update(ctx context.Context, appeal *models.Appeal) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.tables.appeals[appeal.ID] = *appeal
+	return nil
+}
+
+// GetByID 通过ID查询申诉
+func (r *appealRepository) GetByID(ctx context.Context, id string) (*models.Appeal, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	appeal, ok := r.store.tables.appeals[id]
+	if !ok {
+		return nil, nil
+	}
+	return &appeal, nil
+}
+
+// GetPendingByUser 查询用户待处理的申诉
+func (r *appealRepository) GetPendingByUser(ctx context.Context, userID string) (*models.Appeal, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, appeal := range r.store.tables.appeals {
+		if appeal.UserID == userID && appeal.Status == models.AppealStatusPending {
+			return &appeal, nil
+		}
+	}
+	return nil, nil
+}
+
+// List 按状态分页查询申诉，按提交时间正序，先提交的先处理
+func (r *appealRepository) List(ctx context.Context, status string, offset, limit int) ([]models.Appeal, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	appeals := filter(r.store.tables.appeals, func(appeal models.Appeal) bool {
+		return status == "" || appeal.Status == status
+	})
+	sortBy(appeals, func(a, b models.Appeal) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return paginate(appeals, offset, limit), int64(len(appeals)), nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// auditRepository 审计事件仓库的内存实现，只允许追加
+type auditRepository struct {
+	store *Store
+}
+
+// NewAuditRepository 创建审计事件仓库实例
+func NewAuditRepository(store *Store) repositories.AuditRepository {
+	return &auditRepository{
+		store: store,
+	}
+}
+
+// Append 追加审计事件，串行地接到哈希链末尾
+func (r *auditRepository) Append(ctx context.Context, event *models.AuditEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	events := r.store.tables.auditEvents
+	if len(events) == 0 {
+		event.Seq = 1
+		event.PrevHash = ""
+	} else {
+		last := events[len(events)-1]
+		event.Seq = last.Seq + 1
+		event.PrevHash = last.Hash
+	}
+	event.ID = newID()
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	event.Hash = event.ComputeHash()
+
+	stored := *event
+	stored.Metadata = maps.Clone(event.Metadata)
+	r.store.tables.auditEvents = append(events, stored)
+	return nil
+}
+
+// List 按条件分页查询审计事件，按时间倒序
+func (r *auditRepository) List(ctx context.Context, filter repositories.AuditFilter, offset, limit int) ([]models.AuditEvent, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var events []models.AuditEvent
+	for i := len(r.store.tables.auditEvents) - 1; i >= 0; i-- {
+		event := r.store.tables.auditEvents[i]
+		if filter.ActorID != "" && event.ActorID != filter.ActorID {
+			continue
+		}
+		if filter.TargetID != "" && event.TargetID != filter.TargetID {
+			continue
+		}
+		if filter.Action != "" && event.Action != filter.Action {
+			continue
+		}
+		if filter.From != nil && event.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !event.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return paginate(events, offset, limit), int64(len(events)), nil
+}
+
+// ListAfterSeq 按顺序查询序号大于 seq 的事件，用于分批校验哈希链
+func (r *auditRepository) ListAfterSeq(ctx context.Context, seq int64, limit int) ([]models.AuditEvent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var events []models.AuditEvent
+	for _, event := range r.store.tables.auditEvents {
+		if event.Seq > seq {
+			events = append(events, event)
+		}
+	}
+	return paginate(events, 0, limit), nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// blockRepository 拉黑仓库的内存实现
+type blockRepository struct {
+	store *Store
+}
+
+// NewBlockRepository 创建拉黑仓库实例
+func NewBlockRepository(store *Store) repositories.BlockRepository {
+	return &blockRepository{
+		store: store,
+	}
+}
+
+// Create 创建拉黑记录，重复拉黑时忽略
+func (r *blockRepository) Create(ctx context.Context, block *models.Block) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.tables.blocks {
+		if existing.BlockerID == block.BlockerID && existing.BlockedID == block.BlockedID {
+			return nil
+		}
+	}
+
+	if block.ID == "" {
+		block.ID = newID()
+	}
+	block.CreatedAt = r.store.now()
+	r.store.tables.blocks[block.ID] = *block
+	return nil
+}
+
+// Delete 取消拉黑，返回受影响的行数
+func (r *blockRepository) Delete(ctx context.Context, blockerID, blockedID string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var affected int64
+	for id, block := range r.store.tables.blocks {
+		if block.BlockerID == blockerID && block.BlockedID == blockedID {
+			delete(r.store.tables.blocks, id)
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+// IsBlockedEither 判断两个用户之间是否存在任一方向的拉黑
+func (r *blockRepository) IsBlockedEither(ctx context.Context, userAID, userBID string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return isBlockedEither(r.store.tables, userAID, userBID), nil
+}
+
+// isBlockedEither 判断两个用户之间是否存在任一方向的拉黑
+func isBlockedEither(t *tables, userAID, userBID string) bool {
+	for _, block := range t.blocks {
+		if (block.BlockerID == userAID && block.BlockedID == userBID) ||
+			(block.BlockerID == userBID && block.BlockedID == userAID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// boostRepository 资料加速仓库的内存实现
+type boostRepository struct {
+	store *Store
+}
+
+// NewBoostRepository 创建资料加速仓库实例
+func NewBoostRepository(store *Store) repositories.BoostRepository {
+	return &boostRepository{
+		store: store,
+	}
+}
+
+// Create 创建加速记录，支付 ID 不能重复
+func (r *boostRepository) Create(ctx context.Context, boost *models.Boost) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.hasProviderRef(boost.ProviderRef) {
+		return gorm.ErrDuplicatedKey
+	}
+	r.insert(boost)
+	return nil
+}
+
+// CreateIfAbsent 按支付 ID 幂等地创建加速记录，重复的回调不会重复发放
+func (r *boostRepository) CreateIfAbsent(ctx context.Context, boost *models.Boost) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.hasProviderRef(boost.ProviderRef) {
+		r.insert(boost)
+	}
+	return nil
+}
+
+// GetActiveByUser 查询用户生效中的加速
+func (r *boostRepository) GetActiveByUser(ctx context.Context, userID string, now time.Time) (*models.Boost, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	boosts := filter(r.store.tables.boosts, func(boost models.Boost) bool {
+		return boost.UserID == userID && boost.IsActive(now)
+	})
+	if len(boosts) == 0 {
+		return nil, nil
+	}
+	sortBy(boosts, func(a, b models.Boost) bool {
+		return a.EndsAt.After(*b.EndsAt)
+	})
+	return &boosts[0], nil
+}
+
+// ClaimUnused 原子地取出一次最早购买的未使用加速并激活
+// 没有可用的加速时返回 nil
+func (r *boostRepository) ClaimUnused(ctx context.Context, userID string, startsAt, endsAt time.Time) (*models.Boost, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	unused := filter(r.store.tables.boosts, func(boost models.Boost) bool {
+		return boost.UserID == userID && boost.StartsAt == nil
+	})
+	if len(unused) == 0 {
+		return nil, nil
+	}
+	sortBy(unused, func(a, b models.Boost) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	boost := unused[0]
+	boost.StartsAt = &startsAt
+	boost.EndsAt = &endsAt
+	r.store.tables.boosts[boost.ID] = boost
+	return &boost, nil
+}
+
+// CountUnused 统计用户已购买但未使用的加速次数
+func (r *boostRepository) CountUnused(ctx context.Context, userID string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	unused := filter(r.store.tables.boosts, func(boost models.Boost) bool {
+		return boost.UserID == userID && boost.StartsAt == nil
+	})
+	return int64(len(unused)), nil
+}
+
+// ListByUser 分页查询用户已激活的加速记录，按开始时间倒序
+func (r *boostRepository) ListByUser(ctx context.Context, userID string, offset, limit int) ([]models.Boost, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	boosts := filter(r.store.tables.boosts, func(boost models.Boost) bool {
+		return boost.UserID == userID && boost.StartsAt != nil
+	})
+	sortBy(boosts, func(a, b models.Boost) bool {
+		return a.StartsAt.After(*b.StartsAt)
+	})
+	return paginate(boosts, offset, limit), int64(len(boosts)), nil
+}
+
+// IncrementImpressions 为一批用户生效中的加速各记一次曝光
+func (r *boostRepository) IncrementImpressions(ctx context.Context, userIDs []string, now time.Time) error {
+	r.increment(now, func(boost *models.Boost) bool {
+		if !slices.Contains(userIDs, boost.UserID) {
+			return false
+		}
+		boost.Impressions++
+		return true
+	})
+	return nil
+}
+
+// IncrementLikes 为用户生效中的加速记一次喜欢
+func (r *boostRepository) IncrementLikes(ctx context.Context, userID string, now time.Time) error {
+	r.increment(now, func(boost *models.Boost) bool {
+		if boost.UserID != userID {
+			return false
+		}
+		boost.Likes++
+		return true
+	})
+	return nil
+}
+
+// increment 对生效中的加速执行 apply，apply 返回 false 表示不修改
+func (r *boostRepository) increment(now time.Time, apply func(boost *models.Boost) bool) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, boost := range r.store.tables.boosts {
+		if boost.IsActive(now) && apply(&boost) {
+			r.store.tables.boosts[id] = boost
+		}
+	}
+}
+
+// hasProviderRef 判断支付 ID 是否已经存在，调用方需持有锁
+func (r *boostRepository) hasProviderRef(providerRef *string) bool {
+	if providerRef == nil {
+		return false
+	}
+	for _, boost := range r.store.tables.boosts {
+		if boost.ProviderRef != nil && *boost.ProviderRef == *providerRef {
+			return true
+		}
+	}
+	return false
+}
+
+// insert 写入一条加速记录，调用方需持有锁
+func (r *boostRepository) insert(boost *models.Boost) {
+	if boost.ID == "" {
+		boost.ID = newID()
+	}
+	boost.CreatedAt = r.store.now()
+	r.store.tables.boosts[boost.ID] = *boost
+}
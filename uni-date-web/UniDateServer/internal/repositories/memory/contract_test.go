@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repositories.Set {
+		return NewSet(NewStore())
+	})
+}
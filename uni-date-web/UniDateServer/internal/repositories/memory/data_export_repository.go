@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// dataExportRepository 数据导出任务仓库的内存实现
+type dataExportRepository struct {
+	store *Store
+}
+
+// NewDataExportRepository 创建数据导出任务仓库实例
+func NewDataExportRepository(store *Store) repositories.DataExportRepository {
+	return &dataExportRepository{
+		store: store,
+	}
+}
+
+// Create 创建导出任务
+func (r *dataExportRepository) Create(ctx context.Context, export *models.DataExport) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.insert(export)
+	return nil
+}
+
+// CreateIfNoneActive 用户没有待处理或处理中的导出任务时创建，已有时不创建并返回 false
+func (r *dataExportRepository) CreateIfNoneActive(ctx context.Context, export *models.DataExport) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.tables.dataExports {
+		if existing.UserID == export.UserID &&
+			(existing.Status == models.DataExportStatusPending || existing.Status == models.DataExportStatusProcessing) {
+			return false, nil
+		}
+	}
+	r.insert(export)
+	return true, nil
+}
+
+// Update 更新导出任务
+func (r *dataExportRepository) Update(ctx context.Context, export *models.DataExport) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.tables.dataExports[export.ID] = *export
+	return nil
+}
+
+// GetByID 通过ID查询导出任务
+func (r *dataExportRepository) GetByID(ctx context.Context, id string) (*models.DataExport, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	export, ok := r.store.tables.dataExports[id]
+	if !ok {
+		return nil, nil
+	}
+	return &export, nil
+}
+
+// GetLatestByUser 查询用户最近一次导出任务
+func (r *dataExportRepository) GetLatestByUser(ctx context.Context, userID string) (*models.DataExport, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var latest *models.DataExport
+	for _, export := range r.store.tables.dataExports {
+		if export.UserID == userID && (latest == nil || export.CreatedAt.After(latest.CreatedAt)) {
+			export := export
+			latest = &export
+		}
+	}
+	return latest, nil
+}
+
+// ClaimPending 原子地取出一个最早创建的待处理任务并标记为处理中
+// 没有待处理任务时返回 nil
+func (r *dataExportRepository) ClaimPending(ctx context.Context, now time.Time) (*models.DataExport, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pending := filter(r.store.tables.dataExports, func(export models.DataExport) bool {
+		return export.Status == models.DataExportStatusPending
+	})
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	sortBy(pending, func(a, b models.DataExport) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	export := pending[0]
+	export.Status = models.DataExportStatusProcessing
+	export.StartedAt = &now
+	r.store.tables.dataExports[export.ID] = export
+	return &export, nil
+}
+
+// ListExpired 查询下载链接已过期但文件还未清理的任务
+func (r *dataExportRepository) ListExpired(ctx context.Context, now time.Time) ([]models.DataExport, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return filter(r.store.tables.dataExports, func(export models.DataExport) bool {
+		return export.Status == models.DataExportStatusReady && export.ExpiresAt != nil && !export.ExpiresAt.After(now)
+	}), nil
+}
+
+// FailStale 将开始时间早于 startedBefore 仍在处理中的任务标记为失败，通常是实例中途退出
+func (r *dataExportRepository) FailStale(ctx context.Context, startedBefore time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var affected int64
+	for id, export := range r.store.tables.dataExports {
+		if export.Status != models.DataExportStatusProcessing || export.StartedAt == nil || !export.StartedAt.Before(startedBefore) {
+			continue
+		}
+		export.Status = models.DataExportStatusFailed
+		export.Error = "处理超时"
+		r.store.tables.dataExports[id] = export
+		affected++
+	}
+	return affected, nil
+}
+
+// insert 写入导出任务，调用方需持有锁
+func (r *dataExportRepository) insert(export *models.DataExport) {
+	if export.ID == "" {
+		export.ID = newID()
+	}
+	if export.Status == "" {
+		export.Status = models.DataExportStatusPending
+	}
+	export.CreatedAt = r.store.now()
+	r.store.tables.dataExports[export.ID] = *export
+}
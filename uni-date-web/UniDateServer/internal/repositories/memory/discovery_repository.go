@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// discoveryRepository 推荐仓库的内存实现
+type discoveryRepository struct {
+	store *Store
+}
+
+// NewDiscoveryRepository 创建推荐仓库实例
+func NewDiscoveryRepository(store *Store) repositories.DiscoveryRepository {
+	return &discoveryRepository{
+		store: store,
+	}
+}
+
+// ListCandidates 查询用户还没有操作过的推荐候选人
+// 同校用户优先；同一梯队内加速中的用户排在前面
+func (r *discoveryRepository) ListCandidates(ctx context.Context, userID, university string, now time.Time, limit int) ([]repositories.Candidate, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t := r.store.tables
+	var candidates []repositories.Candidate
+	for _, user := range t.users {
+		if !isVisibleTo(t, user, userID) || hasInteraction(t, userID, user.ID) {
+			continue
+		}
+		candidates = append(candidates, repositories.Candidate{User: cloneUser(user), Boosted: isBoosted(t, user.ID, now)})
+	}
+	sortBy(candidates, func(a, b repositories.Candidate) bool {
+		aSame, bSame := a.User.University == university, b.User.University == university
+		if aSame != bSame {
+			return aSame
+		}
+		if a.Boosted != b.Boosted {
+			return a.Boosted
+		}
+		return a.User.CreatedAt.After(b.User.CreatedAt)
+	})
+	return paginate(candidates, 0, limit), nil
+}
+
+// IsDiscoverable 判断用户是否可以出现在查看者的推荐中，不考虑查看者是否已经操作过
+func (r *discoveryRepository) IsDiscoverable(ctx context.Context, viewerID, userID string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.tables.users[userID]
+	return ok && isVisibleTo(r.store.tables, user, viewerID), nil
+}
+
+// isVisibleTo 判断用户是否对查看者可见，规则与 PostgreSQL 实现的 visibleTo 一致
+// 排除查看者自己、已注销、被暂停/封禁或被限制曝光的用户和任一方向存在拉黑关系的用户
+// 排除关闭了可被发现的用户；开启隐身模式的 VIP 只对自己喜欢过的人可见
+func isVisibleTo(t *tables, user models.User, viewerID string) bool {
+	if user.ID == viewerID || user.DeletedAt.Valid || user.Status != models.UserStatusActive {
+		return false
+	}
+	if user.RiskLevel == models.RiskLevelShadowLimited {
+		return false
+	}
+	settings, ok := t.privacySettings[user.ID]
+	if !ok {
+		settings = *models.DefaultPrivacySettings(user.ID)
+	}
+	if !settings.Discoverable {
+		return false
+	}
+	if settings.Incognito && user.IsVIP && !hasLiked(t, user.ID, viewerID) {
+		return false
+	}
+	return !isBlockedEither(t, viewerID, user.ID)
+}
+
+// isBoosted 判断用户是否有生效中的加速
+func isBoosted(t *tables, userID string, now time.Time) bool {
+	for _, boost := range t.boosts {
+		if boost.UserID == userID && boost.IsActive(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// interactionRepository 用户交互仓库的内存实现
+type interactionRepository struct {
+	store *Store
+}
+
+// NewInteractionRepository 创建用户交互仓库实例
+func NewInteractionRepository(store *Store) repositories.InteractionRepository {
+	return &interactionRepository{
+		store: store,
+	}
+}
+
+// Create 创建交互记录
+func (r *interactionRepository) Create(ctx context.Context, interaction *models.Interaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if interaction.ID == "" {
+		interaction.ID = newID()
+	}
+	interaction.CreatedAt = r.store.now()
+	r.store.tables.interactions[interaction.ID] = *interaction
+	return nil
+}
+
+// GetByUsers 查询 from 对 to 的交互记录
+func (r *interactionRepository) GetByUsers(ctx context.Context, fromUserID, toUserID string) (*models.Interaction, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return latestInteraction(r.store.tables, func(interaction models.Interaction) bool {
+		return interaction.FromUserID == fromUserID && interaction.ToUserID == toUserID
+	}), nil
+}
+
+// GetLatestByUser 查询用户最近一次交互
+func (r *interactionRepository) GetLatestByUser(ctx context.Context, fromUserID string) (*models.Interaction, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return latestInteraction(r.store.tables, func(interaction models.Interaction) bool {
+		return interaction.FromUserID == fromUserID
+	}), nil
+}
+
+// Delete 删除交互记录，返回删除的行数
+func (r *interactionRepository) Delete(ctx context.Context, id string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tables.interactions[id]; !ok {
+		return 0, nil
+	}
+	delete(r.store.tables.interactions, id)
+	return 1, nil
+}
+
+// ListPendingLikes 分页查询喜欢了该用户、但该用户尚未操作过的人，按喜欢时间倒序
+// 排除任一方向存在拉黑关系的用户、已注销、被封禁和被限制曝光的用户
+func (r *interactionRepository) ListPendingLikes(ctx context.Context, userID string, offset, limit int) ([]repositories.ReceivedLike, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t := r.store.tables
+	var likes []repositories.ReceivedLike
+	for _, interaction := range t.interactions {
+		if interaction.ToUserID != userID || interaction.Type != models.InteractionTypeLike {
+			continue
+		}
+		liker, ok := t.users[interaction.FromUserID]
+		if !ok || liker.DeletedAt.Valid || liker.Status == models.UserStatusBanned || liker.RiskLevel == models.RiskLevelShadowLimited {
+			continue
+		}
+		if hasInteraction(t, userID, liker.ID) || isBlockedEither(t, userID, liker.ID) {
+			continue
+		}
+		likes = append(likes, repositories.ReceivedLike{User: cloneUser(liker), LikedAt: interaction.CreatedAt})
+	}
+	sortBy(likes, func(a, b repositories.ReceivedLike) bool {
+		return a.LikedAt.After(b.LikedAt)
+	})
+	return paginate(likes, offset, limit), int64(len(likes)), nil
+}
+
+// ListAllByUser 查询用户发起的全部交互，按时间正序
+func (r *interactionRepository) ListAllByUser(ctx context.Context, fromUserID string) ([]models.Interaction, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	interactions := filter(r.store.tables.interactions, func(interaction models.Interaction) bool {
+		return interaction.FromUserID == fromUserID
+	})
+	sortBy(interactions, func(a, b models.Interaction) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return interactions, nil
+}
+
+// latestInteraction 返回满足条件的最近一次交互，没有时返回 nil
+func latestInteraction(t *tables, match func(models.Interaction) bool) *models.Interaction {
+	var latest *models.Interaction
+	for _, interaction := range t.interactions {
+		if match(interaction) && (latest == nil || interaction.CreatedAt.After(latest.CreatedAt)) {
+			interaction := interaction
+			latest = &interaction
+		}
+	}
+	return latest
+}
+
+// hasInteraction 判断 from 是否对 to 操作过
+func hasInteraction(t *tables, fromUserID, toUserID string) bool {
+	for _, interaction := range t.interactions {
+		if interaction.FromUserID == fromUserID && interaction.ToUserID == toUserID {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLiked 判断 from 是否喜欢过 to
+func hasLiked(t *tables, fromUserID, toUserID string) bool {
+	for _, interaction := range t.interactions {
+		if interaction.FromUserID == fromUserID && interaction.ToUserID == toUserID && interaction.Type == models.InteractionTypeLike {
+			return true
+		}
+	}
+	return false
+}
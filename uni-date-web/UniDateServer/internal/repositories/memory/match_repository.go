@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// matchRepository 匹配仓库的内存实现
+type matchRepository struct {
+	store *Store
+}
+
+// NewMatchRepository 创建匹配仓库实例
+func NewMatchRepository(store *Store) repositories.MatchRepository {
+	return &matchRepository{
+		store: store,
+	}
+}
+
+// Create 创建匹配
+func (r *matchRepository) Create(ctx context.Context, match *models.Match) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if match.ID == "" {
+		match.ID = newID()
+	}
+	match.MatchedAt = r.store.now()
+	// is_active 的默认值为 TRUE，false 是零值，写入时会被数据库默认值替代
+	match.IsActive = true
+	r.store.tables.matches[match.ID] = *match
+	return nil
+}
+
+// GetByID 通过ID查询匹配
+func (r *matchRepository) GetByID(ctx context.Context, id string) (*models.Match, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	match, ok := r.store.tables.matches[id]
+	if !ok {
+		return nil, nil
+	}
+	return &match, nil
+}
+
+// GetByUsers 查询两个用户之间的匹配，与双方顺序无关
+func (r *matchRepository) GetByUsers(ctx context.Context, userAID, userBID string) (*models.Match, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	matches := filter(r.store.tables.matches, func(match models.Match) bool {
+		return isBetween(match, userAID, userBID)
+	})
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	sortBy(matches, func(a, b models.Match) bool {
+		return a.MatchedAt.Before(b.MatchedAt)
+	})
+	return &matches[0], nil
+}
+
+// Deactivate 将两个用户之间的匹配设为失效
+func (r *matchRepository) Deactivate(ctx context.Context, userAID, userBID string) error {
+	return r.deactivate(func(match models.Match) bool {
+		return isBetween(match, userAID, userBID)
+	})
+}
+
+// Delete 删除匹配
+func (r *matchRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.tables.matches, id)
+	return nil
+}
+
+// DeactivateAllForUser 将用户的所有匹配设为失效
+func (r *matchRepository) DeactivateAllForUser(ctx context.Context, userID string) error {
+	return r.deactivate(func(match models.Match) bool {
+		return match.User1ID == userID || match.User2ID == userID
+	})
+}
+
+// ListAllByUser 查询用户参与的全部匹配，包括已失效的，按匹配时间正序
+func (r *matchRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Match, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	matches := filter(r.store.tables.matches, func(match models.Match) bool {
+		return match.User1ID == userID || match.User2ID == userID
+	})
+	sortBy(matches, func(a, b models.Match) bool {
+		return a.MatchedAt.Before(b.MatchedAt)
+	})
+	return matches, nil
+}
+
+// deactivate 将满足条件的匹配设为失效
+func (r *matchRepository) deactivate(match func(models.Match) bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deactivateMatches(r.store.tables, match)
+	return nil
+}
+
+// deactivateMatches 将满足条件的匹配设为失效
+func deactivateMatches(t *tables, match func(models.Match) bool) {
+	for id, m := range t.matches {
+		if match(m) {
+			m.IsActive = false
+			t.matches[id] = m
+		}
+	}
+}
+
+// isBetween 判断匹配是否属于两个用户，与双方顺序无关
+func isBetween(match models.Match, userAID, userBID string) bool {
+	return (match.User1ID == userAID && match.User2ID == userBID) ||
+		(match.User1ID == userBID && match.User2ID == userAID)
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// messageRepository 消息仓库的内存实现
+type messageRepository struct {
+	store *Store
+}
+
+// NewMessageRepository 创建消息仓库实例
+func NewMessageRepository(store *Store) repositories.MessageRepository {
+	return &messageRepository{
+		store: store,
+	}
+}
+
+// Create 创建消息
+func (r *messageRepository) Create(ctx context.Context, message *models.Message) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if message.ID == "" {
+		message.ID = newID()
+	}
+	message.CreatedAt = r.store.now()
+	r.store.tables.messages[message.ID] = *message
+	return nil
+}
+
+// CountByMatch 统计匹配下的消息数量
+func (r *messageRepository) CountByMatch(ctx context.Context, matchID string) (int64, error) {
+	return r.count(func(message models.Message) bool {
+		return message.MatchID == matchID
+	}), nil
+}
+
+// CountBySender 统计发送者在匹配下发送的消息数量
+func (r *messageRepository) CountBySender(ctx context.Context, matchID, senderID string) (int64, error) {
+	return r.count(func(message models.Message) bool {
+		return message.MatchID == matchID && message.SenderID == senderID
+	}), nil
+}
+
+// ListByMatch 查询匹配下早于 before 的消息，按时间倒序，excludeSenderID 不为空时排除该发送者的消息
+func (r *messageRepository) ListByMatch(ctx context.Context, matchID, excludeSenderID string, before *time.Time, limit int) ([]models.Message, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	messages := filter(r.store.tables.messages, func(message models.Message) bool {
+		if message.MatchID != matchID {
+			return false
+		}
+		if excludeSenderID != "" && message.SenderID == excludeSenderID {
+			return false
+		}
+		return before == nil || message.CreatedAt.Before(*before)
+	})
+	sortBy(messages, func(a, b models.Message) bool {
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	return paginate(messages, 0, limit), nil
+}
+
+// ListAllByUser 查询用户发送和收到的全部消息，按时间正序
+func (r *messageRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Message, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	messages := filter(r.store.tables.messages, func(message models.Message) bool {
+		return message.SenderID == userID || message.ReceiverID == userID
+	})
+	sortBy(messages, func(a, b models.Message) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return messages, nil
+}
+
+// count 统计满足条件的消息数量
+func (r *messageRepository) count(match func(models.Message) bool) int64 {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return int64(len(filter(r.store.tables.messages, match)))
+}
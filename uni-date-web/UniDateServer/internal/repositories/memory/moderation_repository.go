@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// moderationRepository 审核队列仓库的内存实现
+type moderationRepository struct {
+	store *Store
+}
+
+// NewModerationRepository 创建审核队列仓库实例
+func NewModerationRepository(store *Store) repositories.ModerationRepository {
+	return &moderationRepository{
+		store: store,
+	}
+}
+
+// Create 加入审核队列
+func (r *moderationRepository) Create(ctx context.Context, item *models.ModerationItem) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if item.ID == "" {
+		item.ID = newID()
+	}
+	if item.Status == "" {
+		item.Status = models.ModerationStatusPending
+	}
+	item.CreatedAt = r.store.now()
+	r.store.tables.moderationItems[item.ID] = cloneModerationItem(*item)
+	return nil
+}
+
+// Update 更新审核条目
+func (r *moderationRepository) Update(ctx context.Context, item *models.ModerationItem) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.tables.moderationItems[item.ID] = cloneModerationItem(*item)
+	return nil
+}
+
+// GetByID 通过ID查询审核条目
+func (r *moderationRepository) GetByID(ctx context.Context, id string) (*models.ModerationItem, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	item, ok := r.store.tables.moderationItems[id]
+	if !ok {
+		return nil, nil
+	}
+	item = cloneModerationItem(item)
+	return &item, nil
+}
+
+// List 按状态和来源分页查询审核条目，按加入时间正序
+func (r *moderationRepository) List(ctx context.Context, status, source string, offset, limit int) ([]models.ModerationItem, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	items := filter(r.store.tables.moderationItems, func(item models.ModerationItem) bool {
+		return (status == "" || item.Status == status) && (source == "" || item.Source == source)
+	})
+	sortBy(items, func(a, b models.ModerationItem) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	total := int64(len(items))
+	items = paginate(items, offset, limit)
+	for i := range items {
+		items[i] = cloneModerationItem(items[i])
+	}
+	return items, total, nil
+}
+
+// cloneModerationItem 复制审核条目中的切片，避免与调用方共享底层数组
+func cloneModerationItem(item models.ModerationItem) models.ModerationItem {
+	item.Rules = slices.Clone(item.Rules)
+	return item
+}
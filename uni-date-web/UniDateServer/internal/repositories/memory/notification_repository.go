@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// notificationRepository 通知仓库的内存实现
+type notificationRepository struct {
+	store *Store
+}
+
+// NewNotificationRepository 创建通知仓库实例
+func NewNotificationRepository(store *Store) repositories.NotificationRepository {
+	return &notificationRepository{
+		store: store,
+	}
+}
+
+// Create 创建通知
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if notification.ID == "" {
+		notification.ID = newID()
+	}
+	// 与 GORM 的 autoCreateTime 一致，只在未指定时填充创建时间
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = r.store.now()
+	}
+	r.store.tables.notifications[notification.ID] = *notification
+	return nil
+}
+
+// ListByUser 分页查询用户的通知，按时间倒序
+func (r *notificationRepository) ListByUser(ctx context.Context, userID string, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	notifications := filter(r.store.tables.notifications, func(notification models.Notification) bool {
+		return notification.UserID == userID && (!unreadOnly || !notification.IsRead)
+	})
+	sortBy(notifications, func(a, b models.Notification) bool {
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	return paginate(notifications, offset, limit), int64(len(notifications)), nil
+}
+
+// ListAfter 查询某条通知之后创建的通知，按 (创建时间, ID) 正序，用于断线重连补发
+// afterID 不属于该用户或不存在时返回空列表
+func (r *notificationRepository) ListAfter(ctx context.Context, userID, afterID string, limit int) ([]models.Notification, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	after, ok := r.store.tables.notifications[afterID]
+	if !ok || after.UserID != userID {
+		return nil, nil
+	}
+	notifications := filter(r.store.tables.notifications, func(notification models.Notification) bool {
+		return notification.UserID == userID && notificationBefore(after, notification)
+	})
+	sortBy(notifications, notificationBefore)
+	return paginate(notifications, 0, limit), nil
+}
+
+// CountUnread 统计用户未读通知数量
+func (r *notificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	unread := filter(r.store.tables.notifications, func(notification models.Notification) bool {
+		return notification.UserID == userID && !notification.IsRead
+	})
+	return int64(len(unread)), nil
+}
+
+// MarkRead 将用户指定的通知标记为已读，返回受影响的行数
+func (r *notificationRepository) MarkRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	return r.markRead(func(notification models.Notification) bool {
+		return notification.UserID == userID && slices.Contains(ids, notification.ID)
+	}), nil
+}
+
+// MarkAllRead 将用户的全部通知标记为已读，返回受影响的行数
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	return r.markRead(func(notification models.Notification) bool {
+		return notification.UserID == userID
+	}), nil
+}
+
+// Delete 删除用户的一条通知，返回受影响的行数
+func (r *notificationRepository) Delete(ctx context.Context, userID, id string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	notification, ok := r.store.tables.notifications[id]
+	if !ok || notification.UserID != userID {
+		return 0, nil
+	}
+	delete(r.store.tables.notifications, id)
+	return 1, nil
+}
+
+// DeleteByRelatedID 删除关联到某个对象的全部通知
+func (r *notificationRepository) DeleteByRelatedID(ctx context.Context, relatedID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, notification := range r.store.tables.notifications {
+		if notification.RelatedID == relatedID {
+			delete(r.store.tables.notifications, id)
+		}
+	}
+	return nil
+}
+
+// ListAllByUser 查询用户的全部通知，按时间正序
+func (r *notificationRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Notification, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	notifications := filter(r.store.tables.notifications, func(notification models.Notification) bool {
+		return notification.UserID == userID
+	})
+	sortBy(notifications, func(a, b models.Notification) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return notifications, nil
+}
+
+// markRead 将满足条件的未读通知标记为已读，返回受影响的行数
+func (r *notificationRepository) markRead(match func(models.Notification) bool) int64 {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var affected int64
+	for id, notification := range r.store.tables.notifications {
+		if !notification.IsRead && match(notification) {
+			notification.IsRead = true
+			r.store.tables.notifications[id] = notification
+			affected++
+		}
+	}
+	return affected
+}
+
+// notificationBefore 按 (创建时间, ID) 比较两条通知，与 PostgreSQL 的行比较一致
+func notificationBefore(a, b models.Notification) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
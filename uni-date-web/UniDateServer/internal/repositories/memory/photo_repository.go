@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"math/bits"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// photoRepository 照片仓库的内存实现
+type photoRepository struct {
+	store *Store
+}
+
+// NewPhotoRepository 创建照片仓库实例
+func NewPhotoRepository(store *Store) repositories.PhotoRepository {
+	return &photoRepository{
+		store: store,
+	}
+}
+
+// Create 保存照片记录
+func (r *photoRepository) Create(ctx context.Context, photo *models.Photo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if photo.ID == "" {
+		photo.ID = newID()
+	}
+	photo.CreatedAt = r.store.now()
+	r.store.tables.photos[photo.ID] = *photo
+	return nil
+}
+
+// GetByID 通过ID查询照片
+func (r *photoRepository) GetByID(ctx context.Context, id string) (*models.Photo, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	photo, ok := r.store.tables.photos[id]
+	if !ok {
+		return nil, nil
+	}
+	return &photo, nil
+}
+
+// ListSimilar 查询汉明距离不超过 maxDistance 的照片，按距离从近到远
+// excludeUserID 不为空时排除该用户自己的照片
+func (r *photoRepository) ListSimilar(ctx context.Context, hash int64, excludeUserID string, maxDistance, limit int) ([]repositories.SimilarPhoto, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var photos []repositories.SimilarPhoto
+	for _, photo := range r.store.tables.photos {
+		if excludeUserID != "" && photo.UserID == excludeUserID {
+			continue
+		}
+		if distance := hammingDistance(hash, photo.Hash); distance <= maxDistance {
+			photos = append(photos, repositories.SimilarPhoto{Photo: photo, Distance: distance})
+		}
+	}
+	sortBy(photos, func(a, b repositories.SimilarPhoto) bool {
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return paginate(photos, 0, limit), nil
+}
+
+// CreateBlocklistEntry 添加违规图片
+func (r *photoRepository) CreateBlocklistEntry(ctx context.Context, entry *models.PhotoBlocklistEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if entry.ID == "" {
+		entry.ID = newID()
+	}
+	entry.CreatedAt = r.store.now()
+	r.store.tables.photoBlocklist[entry.ID] = *entry
+	return nil
+}
+
+// DeleteBlocklistEntry 删除违规图片，不存在时返回 false
+func (r *photoRepository) DeleteBlocklistEntry(ctx context.Context, id string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tables.photoBlocklist[id]; !ok {
+		return false, nil
+	}
+	delete(r.store.tables.photoBlocklist, id)
+	return true, nil
+}
+
+// ListBlocklist 分页查询违规图片，按添加时间倒序
+func (r *photoRepository) ListBlocklist(ctx context.Context, offset, limit int) ([]models.PhotoBlocklistEntry, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entries := filter(r.store.tables.photoBlocklist, func(models.PhotoBlocklistEntry) bool {
+		return true
+	})
+	sortBy(entries, func(a, b models.PhotoBlocklistEntry) bool {
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	return paginate(entries, offset, limit), int64(len(entries)), nil
+}
+
+// FindBlocklisted 查询与哈希最相似且距离不超过 maxDistance 的违规图片
+func (r *photoRepository) FindBlocklisted(ctx context.Context, hash int64, maxDistance int) (*repositories.SimilarBlocklistEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var closest *repositories.SimilarBlocklistEntry
+	for _, entry := range r.store.tables.photoBlocklist {
+		distance := hammingDistance(hash, entry.Hash)
+		if distance <= maxDistance && (closest == nil || distance < closest.Distance) {
+			closest = &repositories.SimilarBlocklistEntry{PhotoBlocklistEntry: entry, Distance: distance}
+		}
+	}
+	return closest, nil
+}
+
+// ListAllByUser 查询用户上传的全部照片，按上传时间正序
+func (r *photoRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Photo, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	photos := filter(r.store.tables.photos, func(photo models.Photo) bool {
+		return photo.UserID == userID
+	})
+	sortBy(photos, func(a, b models.Photo) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return photos, nil
+}
+
+// hammingDistance 计算两个 64 位感知哈希之间的汉明距离
+func hammingDistance(a, b int64) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
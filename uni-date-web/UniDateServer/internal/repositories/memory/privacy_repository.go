@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// privacyRepository 隐私设置仓库的内存实现
+type privacyRepository struct {
+	store *Store
+}
+
+// NewPrivacyRepository 创建隐私设置仓库实例
+func NewPrivacyRepository(store *Store) repositories.PrivacyRepository {
+	return &privacyRepository{
+		store: store,
+	}
+}
+
+// Get 查询用户隐私设置，没有记录时返回 nil
+func (r *privacyRepository) Get(ctx context.Context, userID string) (*models.PrivacySettings, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	settings, ok := r.store.tables.privacySettings[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &settings, nil
+}
+
+// ListByUsers 批量查询隐私设置，没有记录的用户不在结果中
+func (r *privacyRepository) ListByUsers(ctx context.Context, userIDs []string) ([]models.PrivacySettings, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var settings []models.PrivacySettings
+	for _, userID := range userIDs {
+		if s, ok := r.store.tables.privacySettings[userID]; ok {
+			settings = append(settings, s)
+		}
+	}
+	return settings, nil
+}
+
+// Save 保存用户隐私设置
+func (r *privacyRepository) Save(ctx context.Context, settings *models.PrivacySettings) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	settings.UpdatedAt = r.store.now()
+	r.store.tables.privacySettings[settings.UserID] = *settings
+	return nil
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// pushRepository 推送仓库的内存实现
+type pushRepository struct {
+	store *Store
+}
+
+// NewPushRepository 创建推送仓库实例
+func NewPushRepository(store *Store) repositories.PushRepository {
+	return &pushRepository{
+		store: store,
+	}
+}
+
+// UpsertDevice 登记设备，令牌已存在时转移到当前用户（同一台设备换号登录）
+func (r *pushRepository) UpsertDevice(ctx context.Context, device *models.Device) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	for id, existing := range r.store.tables.devices {
+		if existing.Token != device.Token {
+			continue
+		}
+		existing.UserID = device.UserID
+		existing.Platform = device.Platform
+		existing.UpdatedAt = now
+		r.store.tables.devices[id] = existing
+		*device = existing
+		return nil
+	}
+
+	if device.ID == "" {
+		device.ID = newID()
+	}
+	device.CreatedAt = now
+	device.UpdatedAt = now
+	r.store.tables.devices[device.ID] = *device
+	return nil
+}
+
+// ListDevicesByUser 查询用户的全部设备
+func (r *pushRepository) ListDevicesByUser(ctx context.Context, userID string) ([]models.Device, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return filter(r.store.tables.devices, func(device models.Device) bool {
+		return device.UserID == userID
+	}), nil
+}
+
+// DeleteDevice 删除用户的一个设备，返回受影响的行数
+func (r *pushRepository) DeleteDevice(ctx context.Context, userID, token string) (int64, error) {
+	return r.deleteDevices(func(device models.Device) bool {
+		return device.UserID == userID && device.Token == token
+	}), nil
+}
+
+// DeleteDeviceByToken 删除失效的设备令牌
+func (r *pushRepository) DeleteDeviceByToken(ctx context.Context, token string) error {
+	r.deleteDevices(func(device models.Device) bool {
+		return device.Token == token
+	})
+	return nil
+}
+
+// GetPreference 查询用户推送偏好，没有记录时返回 nil
+func (r *pushRepository) GetPreference(ctx context.Context, userID string) (*models.PushPreference, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	preference, ok := r.store.tables.pushPreferences[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &preference, nil
+}
+
+// SavePreference 保存用户推送偏好
+func (r *pushRepository) SavePreference(ctx context.Context, preference *models.PushPreference) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	preference.UpdatedAt = r.store.now()
+	r.store.tables.pushPreferences[preference.UserID] = *preference
+	return nil
+}
+
+// deleteDevices 删除满足条件的设备，返回受影响的行数
+func (r *pushRepository) deleteDevices(match func(models.Device) bool) int64 {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var affected int64
+	for id, device := range r.store.tables.devices {
+		if match(device) {
+			delete(r.store.tables.devices, id)
+			affected++
+		}
+	}
+	return affected
+}
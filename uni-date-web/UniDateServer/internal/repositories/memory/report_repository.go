@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// reportRepository 举报仓库的内存实现
+type reportRepository struct {
+	store *Store
+}
+
+// NewReportRepository 创建举报仓库实例
+func NewReportRepository(store *Store) repositories.ReportRepository {
+	return &reportRepository{
+		store: store,
+	}
+}
+
+// Create 创建举报
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if report.ID == "" {
+		report.ID = newID()
+	}
+	report.CreatedAt = r.store.now()
+	r.store.tables.reports[report.ID] = *report
+	return nil
+}
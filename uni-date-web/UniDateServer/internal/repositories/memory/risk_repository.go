@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// riskRepository 风险评分事件仓库的内存实现
+type riskRepository struct {
+	store *Store
+}
+
+// NewRiskRepository 创建风险评分事件仓库实例
+func NewRiskRepository(store *Store) repositories.RiskRepository {
+	return &riskRepository{
+		store: store,
+	}
+}
+
+// ListRegistrationTimes 查询 since 之后使用该 IP 注册的账号的注册时间，包含已注销账号
+func (r *riskRepository) ListRegistrationTimes(ctx context.Context, ip string, since time.Time) ([]time.Time, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var times []time.Time
+	for _, user := range r.store.tables.users {
+		if user.RegistrationIP == ip && !user.CreatedAt.Before(since) {
+			times = append(times, user.CreatedAt)
+		}
+	}
+	return times, nil
+}
+
+// ListFirstMessages 查询用户在各匹配中发送的第一条文本消息，只返回 since 之后发送的
+func (r *riskRepository) ListFirstMessages(ctx context.Context, senderID string, since time.Time) ([]repositories.FirstMessage, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	firstByMatch := make(map[string]models.Message)
+	for _, message := range r.store.tables.messages {
+		if message.SenderID != senderID || message.ContentType != "text" {
+			continue
+		}
+		if first, ok := firstByMatch[message.MatchID]; !ok || message.CreatedAt.Before(first.CreatedAt) {
+			firstByMatch[message.MatchID] = message
+		}
+	}
+
+	var messages []repositories.FirstMessage
+	for _, message := range firstByMatch {
+		if !message.CreatedAt.Before(since) {
+			messages = append(messages, repositories.FirstMessage{
+				MatchID:   message.MatchID,
+				Content:   message.Content,
+				CreatedAt: message.CreatedAt,
+			})
+		}
+	}
+	return messages, nil
+}
+
+// ListSwipeTimes 查询 since 之后用户的滑动时间
+func (r *riskRepository) ListSwipeTimes(ctx context.Context, userID string, since time.Time) ([]time.Time, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var times []time.Time
+	for _, interaction := range r.store.tables.interactions {
+		if interaction.FromUserID == userID && !interaction.CreatedAt.Before(since) {
+			times = append(times, interaction.CreatedAt)
+		}
+	}
+	return times, nil
+}
+
+// ListDuplicatePhotos 查询 photos 中与其他未注销账号上传的照片相似的照片
+// 照片按上传时计算的感知哈希比较，汉明距离不超过 maxDistance 视为相似
+func (r *riskRepository) ListDuplicatePhotos(ctx context.Context, userID string, photos []string, maxDistance int) ([]string, error) {
+	if len(photos) == 0 {
+		return nil, nil
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t := r.store.tables
+	var duplicates []string
+	for _, mine := range t.photos {
+		if mine.UserID != userID || !slices.Contains(photos, mine.URL) || slices.Contains(duplicates, mine.URL) {
+			continue
+		}
+		for _, other := range t.photos {
+			if other.UserID == mine.UserID || hammingDistance(mine.Hash, other.Hash) > maxDistance {
+				continue
+			}
+			if owner, ok := t.users[other.UserID]; ok && !owner.DeletedAt.Valid {
+				duplicates = append(duplicates, mine.URL)
+				break
+			}
+		}
+	}
+	return duplicates, nil
+}
@@ -0,0 +1,182 @@
+// Package memory 基于内存的存储库实现，不依赖数据库，用于本地开发和测试
+// 数据只保存在进程内，重启后丢失，只能单实例运行
+package memory
+
+import (
+	"crypto/rand"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// Store 内存存储，保存所有表的数据，所有存储库共享同一个实例
+type Store struct {
+	mu       sync.Mutex // 保护所有表，事务期间一直持有
+	tables   *tables
+	lastTime time.Time
+}
+
+// usageKey 每日使用次数的主键
+type usageKey struct {
+	UserID  string
+	Feature string
+	Day     string
+}
+
+// tables 各表的数据，以主键为键，值均为副本，不与调用方共享
+type tables struct {
+	users           map[string]models.User
+	interactions    map[string]models.Interaction
+	matches         map[string]models.Match
+	messages        map[string]models.Message
+	notifications   map[string]models.Notification
+	blocks          map[string]models.Block
+	devices         map[string]models.Device
+	pushPreferences map[string]models.PushPreference
+	subscriptions   map[string]models.Subscription
+	usageCounters   map[usageKey]models.UsageCounter
+	boosts          map[string]models.Boost
+	auditEvents     []models.AuditEvent // 按 Seq 递增
+	reports         map[string]models.Report
+	appeals         map[string]models.Appeal
+	moderationItems map[string]models.ModerationItem
+	photos          map[string]models.Photo
+	photoBlocklist  map[string]models.PhotoBlocklistEntry
+	dataExports     map[string]models.DataExport
+	privacySettings map[string]models.PrivacySettings
+}
+
+// NewStore 创建空的内存存储
+func NewStore() *Store {
+	return &Store{
+		tables: &tables{
+			users:           make(map[string]models.User),
+			interactions:    make(map[string]models.Interaction),
+			matches:         make(map[string]models.Match),
+			messages:        make(map[string]models.Message),
+			notifications:   make(map[string]models.Notification),
+			blocks:          make(map[string]models.Block),
+			devices:         make(map[string]models.Device),
+			pushPreferences: make(map[string]models.PushPreference),
+			subscriptions:   make(map[string]models.Subscription),
+			usageCounters:   make(map[usageKey]models.UsageCounter),
+			boosts:          make(map[string]models.Boost),
+			reports:         make(map[string]models.Report),
+			appeals:         make(map[string]models.Appeal),
+			moderationItems: make(map[string]models.ModerationItem),
+			photos:          make(map[string]models.Photo),
+			photoBlocklist:  make(map[string]models.PhotoBlocklistEntry),
+			dataExports:     make(map[string]models.DataExport),
+			privacySettings: make(map[string]models.PrivacySettings),
+		},
+	}
+}
+
+// NewSet 创建基于内存存储的存储库集合
+func NewSet(store *Store) repositories.Set {
+	return repositories.Set{
+		Users:         NewUserRepository(store),
+		Notifications: NewNotificationRepository(store),
+		Push:          NewPushRepository(store),
+		Subscriptions: NewSubscriptionRepository(store),
+		Usage:         NewUsageRepository(store),
+		Interactions:  NewInteractionRepository(store),
+		Matches:       NewMatchRepository(store),
+		Blocks:        NewBlockRepository(store),
+		Messages:      NewMessageRepository(store),
+		Boosts:        NewBoostRepository(store),
+		Discovery:     NewDiscoveryRepository(store),
+		Audit:         NewAuditRepository(store),
+		Reports:       NewReportRepository(store),
+		Appeals:       NewAppealRepository(store),
+		Moderation:    NewModerationRepository(store),
+		Risk:          NewRiskRepository(store),
+		Photos:        NewPhotoRepository(store),
+		DataExports:   NewDataExportRepository(store),
+		AccountPurge:  NewAccountPurgeRepository(store),
+		Privacy:       NewPrivacyRepository(store),
+		Tx:            NewTxManager(store),
+	}
+}
+
+// snapshot 复制当前所有表，事务在副本上执行，调用方需持有 mu
+// 记录中的切片和指针在写入时已复制，这里只复制表本身
+func (s *Store) snapshot() *tables {
+	t := s.tables
+	return &tables{
+		users:           maps.Clone(t.users),
+		interactions:    maps.Clone(t.interactions),
+		matches:         maps.Clone(t.matches),
+		messages:        maps.Clone(t.messages),
+		notifications:   maps.Clone(t.notifications),
+		blocks:          maps.Clone(t.blocks),
+		devices:         maps.Clone(t.devices),
+		pushPreferences: maps.Clone(t.pushPreferences),
+		subscriptions:   maps.Clone(t.subscriptions),
+		usageCounters:   maps.Clone(t.usageCounters),
+		boosts:          maps.Clone(t.boosts),
+		auditEvents:     slices.Clone(t.auditEvents),
+		reports:         maps.Clone(t.reports),
+		appeals:         maps.Clone(t.appeals),
+		moderationItems: maps.Clone(t.moderationItems),
+		photos:          maps.Clone(t.photos),
+		photoBlocklist:  maps.Clone(t.photoBlocklist),
+		dataExports:     maps.Clone(t.dataExports),
+		privacySettings: maps.Clone(t.privacySettings),
+	}
+}
+
+// now 返回严格递增的当前时间，保证按创建时间排序的结果稳定，调用方需持有 mu
+func (s *Store) now() time.Time {
+	now := time.Now()
+	if !now.After(s.lastTime) {
+		now = s.lastTime.Add(time.Microsecond)
+	}
+	s.lastTime = now
+	return now
+}
+
+// newID 生成随机的 UUID v4，对应 PostgreSQL 的 gen_random_uuid()
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// filter 返回表中满足条件的记录，顺序不确定，需要调用方排序
+func filter[K comparable, V any](table map[K]V, keep func(V) bool) []V {
+	var result []V
+	for _, value := range table {
+		if keep(value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// sortBy 按 less 排序，less 相同的记录顺序不确定
+func sortBy[V any](values []V, less func(a, b V) bool) {
+	sort.Slice(values, func(i, j int) bool {
+		return less(values[i], values[j])
+	})
+}
+
+// paginate 对已排序的结果按 offset 和 limit 截取，limit 小于 0 表示不限制
+func paginate[V any](values []V, offset, limit int) []V {
+	if offset >= len(values) {
+		return nil
+	}
+	values = values[offset:]
+	if limit >= 0 && limit < len(values) {
+		values = values[:limit]
+	}
+	return values
+}
@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// subscriptionRepository 订阅仓库的内存实现
+type subscriptionRepository struct {
+	store *Store
+}
+
+// NewSubscriptionRepository 创建订阅仓库实例
+func NewSubscriptionRepository(store *Store) repositories.SubscriptionRepository {
+	return &subscriptionRepository{
+		store: store,
+	}
+}
+
+// Create 创建订阅，支付平台 ID 不能重复
+func (r *subscriptionRepository) Create(ctx context.Context, subscription *models.Subscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if subscription.ProviderRef != "" {
+		for _, existing := range r.store.tables.subscriptions {
+			if existing.ProviderRef == subscription.ProviderRef {
+				return gorm.ErrDuplicatedKey
+			}
+		}
+	}
+
+	if subscription.ID == "" {
+		subscription.ID = newID()
+	}
+	now := r.store.now()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+	r.store.tables.subscriptions[subscription.ID] = *subscription
+	return nil
+}
+
+// Update 更新订阅
+func (r *subscriptionRepository) Update(ctx context.Context, subscription *models.Subscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	subscription.UpdatedAt = r.store.now()
+	r.store.tables.subscriptions[subscription.ID] = *subscription
+	return nil
+}
+
+// GetByProviderRef 通过支付平台 ID 查询订阅
+func (r *subscriptionRepository) GetByProviderRef(ctx context.Context, providerRef string) (*models.Subscription, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, subscription := range r.store.tables.subscriptions {
+		if subscription.ProviderRef == providerRef {
+			return &subscription, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetCurrentByUser 查询用户当前有效的订阅，多条时优先返回到期最晚的，终身套餐最优先
+func (r *subscriptionRepository) GetCurrentByUser(ctx context.Context, userID string) (*models.Subscription, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	subscriptions := filter(r.store.tables.subscriptions, func(subscription models.Subscription) bool {
+		return subscription.UserID == userID && subscription.IsValid(now)
+	})
+	if len(subscriptions) == 0 {
+		return nil, nil
+	}
+	sortBy(subscriptions, func(a, b models.Subscription) bool {
+		if a.EndDate == nil || b.EndDate == nil {
+			return a.EndDate == nil && b.EndDate != nil
+		}
+		return a.EndDate.After(*b.EndDate)
+	})
+	return &subscriptions[0], nil
+}
+
+// ExpireDue 将已到期的订阅标记为过期，返回受影响的行数
+func (r *subscriptionRepository) ExpireDue(ctx context.Context, now time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var affected int64
+	for id, subscription := range r.store.tables.subscriptions {
+		if subscription.Status != models.SubscriptionStatusActive || subscription.EndDate == nil || subscription.EndDate.After(now) {
+			continue
+		}
+		subscription.Status = models.SubscriptionStatusExpired
+		subscription.UpdatedAt = r.store.now()
+		r.store.tables.subscriptions[id] = subscription
+		affected++
+	}
+	return affected, nil
+}
+
+// SyncUserVIP 根据是否存在有效订阅刷新用户的 VIP 标记，标记变化时递增用户的版本号
+func (r *subscriptionRepository) SyncUserVIP(ctx context.Context, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.tables.users[userID]
+	if !ok {
+		return nil
+	}
+	isVIP := r.hasValidSubscription(userID, time.Now())
+	if user.IsVIP == isVIP {
+		return nil
+	}
+	user.IsVIP = isVIP
+	user.Version++
+	user.UpdatedAt = r.store.now()
+	r.store.tables.users[userID] = user
+	return nil
+}
+
+// SyncAllVIP 撤销所有已无有效订阅用户的 VIP 标记，返回受影响的行数
+func (r *subscriptionRepository) SyncAllVIP(ctx context.Context) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var affected int64
+	for id, user := range r.store.tables.users {
+		if !user.IsVIP || r.hasValidSubscription(id, now) {
+			continue
+		}
+		user.IsVIP = false
+		user.Version++
+		user.UpdatedAt = r.store.now()
+		r.store.tables.users[id] = user
+		affected++
+	}
+	return affected, nil
+}
+
+// EndByPlan 立即终止用户某个套餐下所有有效的订阅，返回受影响的行数
+func (r *subscriptionRepository) EndByPlan(ctx context.Context, userID, plan string, now time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var affected int64
+	for id, subscription := range r.store.tables.subscriptions {
+		if subscription.UserID != userID || subscription.Plan != plan || !subscription.IsValid(now) {
+			continue
+		}
+		subscription.Status = models.SubscriptionStatusCanceled
+		subscription.EndDate = &now
+		subscription.UpdatedAt = now
+		r.store.tables.subscriptions[id] = subscription
+		affected++
+	}
+	return affected, nil
+}
+
+// hasValidSubscription 判断用户是否存在有效订阅，调用方需持有锁
+func (r *subscriptionRepository) hasValidSubscription(userID string, now time.Time) bool {
+	for _, subscription := range r.store.tables.subscriptions {
+		if subscription.UserID == userID && subscription.IsValid(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// txManager 事务管理的内存实现
+// 事务期间一直持有存储的锁，事务之间以及事务与其他读写都串行执行，不会出现序列化失败，因此不需要重试
+type txManager struct {
+	store *Store
+}
+
+// NewTxManager 创建事务管理实例
+func NewTxManager(store *Store) repositories.TxManager {
+	return &txManager{
+		store: store,
+	}
+}
+
+// WithinTx 在表的副本上执行 fn，fn 成功后用副本替换存储中的表，返回错误时直接丢弃副本
+// fn 中只能使用 tx 中的存储库，使用事务外的存储库会因为等待锁而死锁
+func (m *txManager) WithinTx(ctx context.Context, fn func(tx repositories.Repos) error) error {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// 事务内的存储库使用独立的锁，操作的是表的副本
+	txStore := &Store{
+		tables:   m.store.snapshot(),
+		lastTime: m.store.lastTime,
+	}
+	err := fn(repositories.Repos{
+		Users:         NewUserRepository(txStore),
+		Interactions:  NewInteractionRepository(txStore),
+		Matches:       NewMatchRepository(txStore),
+		Messages:      NewMessageRepository(txStore),
+		Notifications: NewNotificationRepository(txStore),
+		Blocks:        NewBlockRepository(txStore),
+		Boosts:        NewBoostRepository(txStore),
+	})
+	if err != nil {
+		return err
+	}
+
+	m.store.tables = txStore.tables
+	m.store.lastTime = txStore.lastTime
+	return nil
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+func TestWithinTxRollbackKeepsOtherWrites(t *testing.T) {
+	set := NewSet(NewStore())
+	errAbort := errors.New("abort")
+
+	// 事务执行期间在事务外写入消息，事务回滚后这条消息必须保留
+	written := make(chan error, 1)
+	err := set.Tx.WithinTx(context.Background(), func(tx repositories.Repos) error {
+		if err := tx.Interactions.Create(context.Background(), &models.Interaction{FromUserID: "a", ToUserID: "b", Type: models.InteractionTypeLike}); err != nil {
+			return err
+		}
+		go func() {
+			written <- set.Messages.Create(context.Background(), &models.Message{MatchID: "m", SenderID: "a", Content: "hi"})
+		}()
+		select {
+		case err := <-written:
+			// 写入没有等待事务结束，回滚时不能丢掉它
+			written <- err
+		case <-time.After(50 * time.Millisecond):
+		}
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatalf("期望返回事务中的错误，实际 %v", err)
+	}
+	if err := <-written; err != nil {
+		t.Fatalf("写入消息失败: %v", err)
+	}
+
+	count, err := set.Messages.CountByMatch(context.Background(), "m")
+	if err != nil {
+		t.Fatalf("统计消息失败: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("事务外写入的消息被回滚")
+	}
+	interaction, err := set.Interactions.GetByUsers(context.Background(), "a", "b")
+	if err != nil {
+		t.Fatalf("查询互动失败: %v", err)
+	}
+	if interaction != nil {
+		t.Fatalf("回滚的事务中创建的互动不应保留")
+	}
+}
+
+func TestWithinTxCommit(t *testing.T) {
+	set := NewSet(NewStore())
+
+	err := set.Tx.WithinTx(context.Background(), func(tx repositories.Repos) error {
+		return tx.Interactions.Create(context.Background(), &models.Interaction{FromUserID: "a", ToUserID: "b", Type: models.InteractionTypeLike})
+	})
+	if err != nil {
+		t.Fatalf("事务执行失败: %v", err)
+	}
+
+	interaction, err := set.Interactions.GetByUsers(context.Background(), "a", "b")
+	if err != nil {
+		t.Fatalf("查询互动失败: %v", err)
+	}
+	if interaction == nil || interaction.ID == "" {
+		t.Fatalf("提交的事务中创建的互动应当保留")
+	}
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// usageRepository 每日使用次数仓库的内存实现
+type usageRepository struct {
+	store *Store
+}
+
+// NewUsageRepository 创建使用次数仓库实例
+func NewUsageRepository(store *Store) repositories.UsageRepository {
+	return &usageRepository{
+		store: store,
+	}
+}
+
+// Get 查询某天的使用次数，没有记录时为 0
+func (r *usageRepository) Get(ctx context.Context, userID, feature, day string) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.tables.usageCounters[usageKey{userID, feature, day}].Count, nil
+}
+
+// IncrementWithin 在不超过 limit 的前提下原子地加一
+// 返回加一后的次数；已达上限时返回 false 且不修改计数
+func (r *usageRepository) IncrementWithin(ctx context.Context, userID, feature, day string, limit int) (int, bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := usageKey{userID, feature, day}
+	counter, ok := r.store.tables.usageCounters[key]
+	if !ok {
+		counter = models.UsageCounter{UserID: userID, Feature: feature, Day: day}
+	} else if counter.Count >= limit {
+		return limit, false, nil
+	}
+	counter.Count++
+	counter.UpdatedAt = r.store.now()
+	r.store.tables.usageCounters[key] = counter
+	return counter.Count, true, nil
+}
+
+// Decrement 返还一次使用次数，不会低于 0
+func (r *usageRepository) Decrement(ctx context.Context, userID, feature, day string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := usageKey{userID, feature, day}
+	counter, ok := r.store.tables.usageCounters[key]
+	if !ok || counter.Count <= 0 {
+		return nil
+	}
+	counter.Count--
+	counter.UpdatedAt = r.store.now()
+	r.store.tables.usageCounters[key] = counter
+	return nil
+}
@@ -0,0 +1,286 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// defaultTimezone 用户未设置时区时的默认值，与数据库列的默认值一致
+const defaultTimezone = "Asia/Shanghai"
+
+// userSchema 用于按列名修改用户字段，列名与 PostgreSQL 实现一致
+var userSchema = func() *schema.Schema {
+	s, err := schema.Parse(&models.User{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		panic(err)
+	}
+	return s
+}()
+
+// userRepository 用户仓库的内存实现
+type userRepository struct {
+	store *Store
+}
+
+// NewUserRepository 创建用户仓库实例
+func NewUserRepository(store *Store) repositories.UserRepository {
+	return &userRepository{
+		store: store,
+	}
+}
+
+// Create 创建新用户，账号和手机号不能与已有用户（包括已注销的）重复
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.tables.users {
+		if existing.Account == user.Account || (user.Phone != "" && existing.Phone == user.Phone) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	if user.ID == "" {
+		user.ID = newID()
+	}
+	if user.Timezone == "" {
+		user.Timezone = defaultTimezone
+	}
+	if user.Role == "" {
+		user.Role = models.UserRoleUser
+	}
+	if user.Status == "" {
+		user.Status = models.UserStatusActive
+	}
+	if user.Version == 0 {
+		user.Version = 1
+	}
+	now := r.store.now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.store.tables.users[user.ID] = cloneUser(*user)
+	return nil
+}
+
+// GetByAccount 通过账号查询用户
+func (r *userRepository) GetByAccount(ctx context.Context, account string) (*models.User, error) {
+	return r.find(func(user models.User) bool {
+		return user.Account == account && !user.DeletedAt.Valid
+	})
+}
+
+// GetByAccountWithDeleted 通过账号查询用户，包括已软删除的用户
+func (r *userRepository) GetByAccountWithDeleted(ctx context.Context, account string) (*models.User, error) {
+	return r.find(func(user models.User) bool {
+		return user.Account == account
+	})
+}
+
+// GetByID 通过ID查询用户
+func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return r.find(func(user models.User) bool {
+		return user.ID == id && !user.DeletedAt.Valid
+	})
+}
+
+// Update 按读取时的版本号条件更新指定的列并递增版本号
+// 期间已被其他请求修改时不做任何更新，返回 ErrStaleVersion
+func (r *userRepository) Update(ctx context.Context, user *models.User, values map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.tables.users[user.ID]
+	if !ok || existing.DeletedAt.Valid || existing.Version != user.Version {
+		return repositories.ErrStaleVersion
+	}
+	if err := setColumns(&existing, values); err != nil {
+		return err
+	}
+	existing.Version++
+	existing.UpdatedAt = r.store.now()
+	r.store.tables.users[user.ID] = cloneUser(existing)
+	user.Version++
+	return nil
+}
+
+// CheckAccountExists 检查账号是否已存在，已注销但数据尚未清除的账号仍占用账号名
+func (r *userRepository) CheckAccountExists(ctx context.Context, account string) (bool, error) {
+	user, err := r.GetByAccountWithDeleted(ctx, account)
+	return user != nil, err
+}
+
+// Search 按条件分页查询用户，按注册时间倒序
+// 文本条件不区分大小写地模糊匹配，与 ILIKE 一致
+func (r *userRepository) Search(ctx context.Context, filter repositories.UserSearchFilter, offset, limit int) ([]models.User, int64, error) {
+	contains := func(value, query string) bool {
+		return strings.Contains(strings.ToLower(value), strings.ToLower(query))
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	users := filterUsers(r.store.tables.users, func(user models.User) bool {
+		if user.DeletedAt.Valid && !filter.IncludeDeleted {
+			return false
+		}
+		if filter.Query != "" && !contains(user.Account, filter.Query) && !contains(user.Name, filter.Query) && !contains(user.University, filter.Query) {
+			return false
+		}
+		if filter.Account != "" && !contains(user.Account, filter.Account) {
+			return false
+		}
+		if filter.Name != "" && !contains(user.Name, filter.Name) {
+			return false
+		}
+		if filter.University != "" && !contains(user.University, filter.University) {
+			return false
+		}
+		return filter.Status == "" || user.Status == filter.Status
+	})
+	sortBy(users, func(a, b models.User) bool {
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	return paginate(users, offset, limit), int64(len(users)), nil
+}
+
+// GetByIDWithDeleted 通过ID查询用户，包括已注销的
+func (r *userRepository) GetByIDWithDeleted(ctx context.Context, id string) (*models.User, error) {
+	return r.find(func(user models.User) bool {
+		return user.ID == id
+	})
+}
+
+// UpdateColumns 只更新指定的列，对已注销的用户同样生效
+func (r *userRepository) UpdateColumns(ctx context.Context, id string, values map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.tables.users[id]
+	if !ok {
+		return nil
+	}
+	if err := setColumns(&user, values); err != nil {
+		return err
+	}
+	if repositories.BumpsVersion(values) {
+		user.Version++
+	}
+	user.UpdatedAt = r.store.now()
+	r.store.tables.users[id] = cloneUser(user)
+	return nil
+}
+
+// SoftDelete 软删除用户
+func (r *userRepository) SoftDelete(ctx context.Context, id string) error {
+	return r.update(id, false, func(user *models.User, now time.Time) {
+		user.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	})
+}
+
+// Restore 恢复已软删除的用户，同时取消待执行的数据清除
+func (r *userRepository) Restore(ctx context.Context, id string) error {
+	return r.update(id, true, func(user *models.User, now time.Time) {
+		user.DeletedAt = gorm.DeletedAt{}
+		user.PurgeAfter = nil
+	})
+}
+
+// ScheduleDeletion 软删除用户并设置彻底清除数据的时间
+func (r *userRepository) ScheduleDeletion(ctx context.Context, id string, purgeAfter time.Time) error {
+	return r.update(id, false, func(user *models.User, now time.Time) {
+		user.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		user.PurgeAfter = &purgeAfter
+	})
+}
+
+// ReinstateExpired 将暂停已到期的用户恢复为正常状态，返回被恢复的用户ID
+func (r *userRepository) ReinstateExpired(ctx context.Context, now time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var ids []string
+	for id, user := range r.store.tables.users {
+		if user.Status != models.UserStatusSuspended || user.SuspendedUntil == nil || user.SuspendedUntil.After(now) {
+			continue
+		}
+		user.Status = models.UserStatusActive
+		user.StatusReason = ""
+		user.SuspendedUntil = nil
+		user.Version++
+		user.UpdatedAt = now
+		r.store.tables.users[id] = user
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// find 查询第一个满足条件的用户，没有时返回 nil
+func (r *userRepository) find(match func(models.User) bool) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.tables.users {
+		if match(user) {
+			user = cloneUser(user)
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+// update 修改一个用户，withDeleted 为 false 时跳过已软删除的用户
+func (r *userRepository) update(id string, withDeleted bool, apply func(user *models.User, now time.Time)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.tables.users[id]
+	if !ok || (user.DeletedAt.Valid && !withDeleted) {
+		return nil
+	}
+	now := r.store.now()
+	apply(&user, now)
+	user.UpdatedAt = now
+	r.store.tables.users[id] = user
+	return nil
+}
+
+// setColumns 按列名修改用户字段
+func setColumns(user *models.User, values map[string]interface{}) error {
+	dest := reflect.ValueOf(user).Elem()
+	for column, value := range values {
+		field := userSchema.LookUpField(column)
+		if field == nil {
+			return fmt.Errorf("users 表不存在列 %s", column)
+		}
+		if err := field.Set(context.Background(), dest, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterUsers 返回满足条件的用户副本
+func filterUsers(users map[string]models.User, keep func(models.User) bool) []models.User {
+	result := filter(users, keep)
+	for i := range result {
+		result[i] = cloneUser(result[i])
+	}
+	return result
+}
+
+// cloneUser 复制用户记录中的切片，避免与调用方共享底层数组
+func cloneUser(user models.User) models.User {
+	user.Photos = slices.Clone(user.Photos)
+	user.Interests = slices.Clone(user.Interests)
+	return user
+}
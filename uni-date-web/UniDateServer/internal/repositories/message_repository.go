@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// MessageRepository 消息仓库接口
+type MessageRepository interface {
+	Create(ctx context.Context, message *models.Message) error
+	CountByMatch(ctx context.Context, matchID string) (int64, error)
+	CountBySender(ctx context.Context, matchID, senderID string) (int64, error)
+	ListByMatch(ctx context.Context, matchID, excludeSenderID string, before *time.Time, limit int) ([]models.Message, error)
+	ListAllByUser(ctx context.Context, userID string) ([]models.Message, error)
+}
+
+// messageRepository 消息仓库实现
+type messageRepository struct {
+	db *gorm.DB
+}
+
+// NewMessageRepository 创建消息仓库实例
+func NewMessageRepository(db *gorm.DB) MessageRepository {
+	return &messageRepository{
+		db: db,
+	}
+}
+
+// Create 创建消息
+func (r *messageRepository) Create(ctx context.Context, message *models.Message) error {
+	return r.db.WithContext(ctx).Create(message).Error
+}
+
+// CountByMatch 统计匹配下的消息数量
+func (r *messageRepository) CountByMatch(ctx context.Context, matchID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Message{}).Where("match_id = ?", matchID).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountBySender 统计发送者在匹配下发送的消息数量
+func (r *messageRepository) CountBySender(ctx context.Context, matchID, senderID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Message{}).
+		Where("match_id = ? AND sender_id = ?", matchID, senderID).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListByMatch 查询匹配下早于 before 的消息，按时间倒序，excludeSenderID 不为空时排除该发送者的消息
+func (r *messageRepository) ListByMatch(ctx context.Context, matchID, excludeSenderID string, before *time.Time, limit int) ([]models.Message, error) {
+	query := r.db.WithContext(ctx).Where("match_id = ?", matchID)
+	if excludeSenderID != "" {
+		query = query.Where("sender_id <> ?", excludeSenderID)
+	}
+	if before != nil {
+		query = query.Where("created_at < ?", *before)
+	}
+
+	var messages []models.Message
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
+}
+
+// ListAllByUser 查询用户发送和收到的全部消息，按时间正序
+func (r *messageRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Message, error) {
+	var messages []models.Message
+	err := r.db.WithContext(ctx).Where("sender_id = ? OR receiver_id = ?", userID, userID).
+		Order("created_at ASC").
+		Find(&messages).Error
+	return messages, err
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// ModerationRepository 审核队列仓库接口
+type ModerationRepository interface {
+	Create(ctx context.Context, item *models.ModerationItem) error
+	Update(ctx context.Context, item *models.ModerationItem) error
+	GetByID(ctx context.Context, id string) (*models.ModerationItem, error)
+	List(ctx context.Context, status, source string, offset, limit int) ([]models.ModerationItem, int64, error)
+}
+
+// moderationRepository 审核队列仓库实现
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+// NewModerationRepository 创建审核队列仓库实例
+func NewModerationRepository(db *gorm.DB) ModerationRepository {
+	return &moderationRepository{
+		db: db,
+	}
+}
+
+// Create 加入审核队列，关联记录为空时不写入对应列
+func (r *moderationRepository) Create(ctx context.Context, item *models.ModerationItem) error {
+	query := r.db.WithContext(ctx)
+	if item.RefID == "" {
+		query = query.Omit("RefID")
+	}
+	return query.Create(item).Error
+}
+
+// Update 更新审核条目
+func (r *moderationRepository) Update(ctx context.Context, item *models.ModerationItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+// GetByID 通过ID查询审核条目
+func (r *moderationRepository) GetByID(ctx context.Context, id string) (*models.ModerationItem, error) {
+	var item models.ModerationItem
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// List 按状态和来源分页查询审核条目，按加入时间正序
+func (r *moderationRepository) List(ctx context.Context, status, source string, offset, limit int) ([]models.ModerationItem, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.ModerationItem{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []models.ModerationItem
+	err := query.Order("created_at").
+		Offset(offset).Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
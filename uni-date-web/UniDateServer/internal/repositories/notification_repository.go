@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository 通知仓库接口
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	ListByUser(ctx context.Context, userID string, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error)
+	ListAfter(ctx context.Context, userID, afterID string, limit int) ([]models.Notification, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	MarkRead(ctx context.Context, userID string, ids []string) (int64, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	Delete(ctx context.Context, userID, id string) (int64, error)
+	DeleteByRelatedID(ctx context.Context, relatedID string) error
+	ListAllByUser(ctx context.Context, userID string) ([]models.Notification, error)
+}
+
+// notificationRepository 通知仓库实现
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建通知仓库实例
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+// Create 创建通知
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	tx := r.db.WithContext(ctx)
+	// related_id 是 uuid 类型，空字符串无法写入
+	if notification.RelatedID == "" {
+		tx = tx.Omit("RelatedID")
+	}
+	return tx.Create(notification).Error
+}
+
+// ListByUser 分页查询用户的通知，按时间倒序
+// 所有查询都以 user_id 作为首个条件，以便命中 idx_notifications_user 索引
+func (r *notificationRepository) ListByUser(ctx context.Context, userID string, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []models.Notification
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// ListAfter 查询某条通知之后创建的通知，按 (创建时间, ID) 正序，用于断线重连补发
+// 创建时间相同的通知按 ID 排序，不会因为时间相同而漏发
+// afterID 不属于该用户或不存在时返回空列表
+func (r *notificationRepository) ListAfter(ctx context.Context, userID, afterID string, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.WithContext(ctx).Where("user_id = ? AND (created_at, id) > (?)", userID,
+		r.db.Model(&models.Notification{}).Select("created_at, id").Where("user_id = ? AND id = ?", userID, afterID),
+	).Order("created_at ASC, id ASC").Limit(limit).Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// CountUnread 统计用户未读通知数量
+func (r *notificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkRead 将用户指定的通知标记为已读，返回受影响的行数
+func (r *notificationRepository) MarkRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND id IN ? AND is_read = ?", userID, ids, false).
+		Update("is_read", true)
+	return result.RowsAffected, result.Error
+}
+
+// MarkAllRead 将用户的全部通知标记为已读，返回受影响的行数
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Update("is_read", true)
+	return result.RowsAffected, result.Error
+}
+
+// Delete 删除用户的一条通知，返回受影响的行数
+func (r *notificationRepository) Delete(ctx context.Context, userID, id string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).Delete(&models.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByRelatedID 删除关联到某个对象的全部通知
+func (r *notificationRepository) DeleteByRelatedID(ctx context.Context, relatedID string) error {
+	return r.db.WithContext(ctx).Where("related_id = ?", relatedID).Delete(&models.Notification{}).Error
+}
+
+// ListAllByUser 查询用户的全部通知，按时间正序
+func (r *notificationRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&notifications).Error
+	return notifications, err
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// hammingDistance 计算 hash 列与参数之间汉明距离的 SQL 表达式
+const hammingDistance = "length(replace((hash # ?)::bit(64)::text, '0', ''))"
+
+// PhotoRepository 照片及违规图片仓库接口
+type PhotoRepository interface {
+	Create(ctx context.Context, photo *models.Photo) error
+	GetByID(ctx context.Context, id string) (*models.Photo, error)
+	ListAllByUser(ctx context.Context, userID string) ([]models.Photo, error)
+	ListSimilar(ctx context.Context, hash int64, excludeUserID string, maxDistance, limit int) ([]SimilarPhoto, error)
+	CreateBlocklistEntry(ctx context.Context, entry *models.PhotoBlocklistEntry) error
+	DeleteBlocklistEntry(ctx context.Context, id string) (bool, error)
+	ListBlocklist(ctx context.Context, offset, limit int) ([]models.PhotoBlocklistEntry, int64, error)
+	FindBlocklisted(ctx context.Context, hash int64, maxDistance int) (*SimilarBlocklistEntry, error)
+}
+
+// SimilarPhoto 相似照片及汉明距离
+type SimilarPhoto struct {
+	models.Photo `gorm:"embedded"`
+	Distance     int
+}
+
+// SimilarBlocklistEntry 相似的违规图片及汉明距离
+type SimilarBlocklistEntry struct {
+	models.PhotoBlocklistEntry `gorm:"embedded"`
+	Distance                   int
+}
+
+// photoRepository 照片仓库实现
+type photoRepository struct {
+	db *gorm.DB
+}
+
+// NewPhotoRepository 创建照片仓库实例
+func NewPhotoRepository(db *gorm.DB) PhotoRepository {
+	return &photoRepository{
+		db: db,
+	}
+}
+
+// Create 保存照片记录
+func (r *photoRepository) Create(ctx context.Context, photo *models.Photo) error {
+	return r.db.WithContext(ctx).Create(photo).Error
+}
+
+// GetByID 通过ID查询照片
+func (r *photoRepository) GetByID(ctx context.Context, id string) (*models.Photo, error) {
+	var photo models.Photo
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&photo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// ListSimilar 查询汉明距离不超过 maxDistance 的照片，按距离从近到远
+// excludeUserID 不为空时排除该用户自己的照片
+func (r *photoRepository) ListSimilar(ctx context.Context, hash int64, excludeUserID string, maxDistance, limit int) ([]SimilarPhoto, error) {
+	query := r.db.WithContext(ctx).Table("photos").Select("photos.*, "+hammingDistance+" AS distance", hash)
+	if excludeUserID != "" {
+		query = query.Where("user_id <> ?", excludeUserID)
+	}
+
+	var photos []SimilarPhoto
+	err := r.db.WithContext(ctx).Table("(?) AS candidates", query).
+		Where("distance <= ?", maxDistance).
+		Order("distance, created_at").
+		Limit(limit).
+		Scan(&photos).Error
+	if err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// CreateBlocklistEntry 添加违规图片
+func (r *photoRepository) CreateBlocklistEntry(ctx context.Context, entry *models.PhotoBlocklistEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// DeleteBlocklistEntry 删除违规图片，不存在时返回 false
+func (r *photoRepository) DeleteBlocklistEntry(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.PhotoBlocklistEntry{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListBlocklist 分页查询违规图片，按添加时间倒序
+func (r *photoRepository) ListBlocklist(ctx context.Context, offset, limit int) ([]models.PhotoBlocklistEntry, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.PhotoBlocklistEntry{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.PhotoBlocklistEntry
+	err := r.db.WithContext(ctx).Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// FindBlocklisted 查询与哈希最相似且距离不超过 maxDistance 的违规图片
+func (r *photoRepository) FindBlocklisted(ctx context.Context, hash int64, maxDistance int) (*SimilarBlocklistEntry, error) {
+	query := r.db.WithContext(ctx).Table("photo_blocklist").Select("photo_blocklist.*, "+hammingDistance+" AS distance", hash)
+
+	var entries []SimilarBlocklistEntry
+	err := r.db.WithContext(ctx).Table("(?) AS candidates", query).
+		Where("distance <= ?", maxDistance).
+		Order("distance").
+		Limit(1).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[0], nil
+}
+
+// ListAllByUser 查询用户上传的全部照片，按上传时间正序
+func (r *photoRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Photo, error) {
+	var photos []models.Photo
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&photos).Error
+	return photos, err
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// PrivacyRepository 隐私设置仓库接口
+type PrivacyRepository interface {
+	Get(ctx context.Context, userID string) (*models.PrivacySettings, error)
+	ListByUsers(ctx context.Context, userIDs []string) ([]models.PrivacySettings, error)
+	Save(ctx context.Context, settings *models.PrivacySettings) error
+}
+
+// privacyRepository 隐私设置仓库实现
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository 创建隐私设置仓库实例
+func NewPrivacyRepository(db *gorm.DB) PrivacyRepository {
+	return &privacyRepository{
+		db: db,
+	}
+}
+
+// Get 查询用户隐私设置，没有记录时返回 nil
+func (r *privacyRepository) Get(ctx context.Context, userID string) (*models.PrivacySettings, error) {
+	var settings models.PrivacySettings
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// ListByUsers 批量查询隐私设置，没有记录的用户不在结果中
+func (r *privacyRepository) ListByUsers(ctx context.Context, userIDs []string) ([]models.PrivacySettings, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var settings []models.PrivacySettings
+	err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&settings).Error
+	return settings, err
+}
+
+// Save 保存用户隐私设置
+func (r *privacyRepository) Save(ctx context.Context, settings *models.PrivacySettings) error {
+	return r.db.WithContext(ctx).Save(settings).Error
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PushRepository 推送设备与推送偏好仓库接口
+type PushRepository interface {
+	UpsertDevice(ctx context.Context, device *models.Device) error
+	ListDevicesByUser(ctx context.Context, userID string) ([]models.Device, error)
+	DeleteDevice(ctx context.Context, userID, token string) (int64, error)
+	DeleteDeviceByToken(ctx context.Context, token string) error
+	GetPreference(ctx context.Context, userID string) (*models.PushPreference, error)
+	SavePreference(ctx context.Context, preference *models.PushPreference) error
+}
+
+// pushRepository 推送仓库实现
+type pushRepository struct {
+	db *gorm.DB
+}
+
+// NewPushRepository 创建推送仓库实例
+func NewPushRepository(db *gorm.DB) PushRepository {
+	return &pushRepository{
+		db: db,
+	}
+}
+
+// UpsertDevice 登记设备，令牌已存在时转移到当前用户（同一台设备换号登录）
+func (r *pushRepository) UpsertDevice(ctx context.Context, device *models.Device) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "updated_at"}),
+	}).Create(device).Error
+}
+
+// ListDevicesByUser 查询用户的全部设备
+func (r *pushRepository) ListDevicesByUser(ctx context.Context, userID string) ([]models.Device, error) {
+	var devices []models.Device
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// DeleteDevice 删除用户的一个设备，返回受影响的行数
+func (r *pushRepository) DeleteDevice(ctx context.Context, userID, token string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND token = ?", userID, token).Delete(&models.Device{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteDeviceByToken 删除失效的设备令牌
+func (r *pushRepository) DeleteDeviceByToken(ctx context.Context, token string) error {
+	return r.db.WithContext(ctx).Where("token = ?", token).Delete(&models.Device{}).Error
+}
+
+// GetPreference 查询用户推送偏好，没有记录时返回 nil
+func (r *pushRepository) GetPreference(ctx context.Context, userID string) (*models.PushPreference, error) {
+	var preference models.PushPreference
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&preference).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// SavePreference 保存用户推送偏好
+func (r *pushRepository) SavePreference(ctx context.Context, preference *models.PushPreference) error {
+	return r.db.WithContext(ctx).Save(preference).Error
+}
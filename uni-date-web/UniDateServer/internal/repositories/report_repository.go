@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReportRepository 举报仓库接口
+type ReportRepository interface {
+	Create(ctx context.Context, report *models.Report) error
+}
+
+// reportRepository 举报仓库实现
+type reportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository 创建举报仓库实例
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+// Create 创建举报
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
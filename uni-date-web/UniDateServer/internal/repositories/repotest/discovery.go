@@ -0,0 +1,224 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+)
+
+func testVisibility(t *testing.T, f *fixture) {
+	viewer := f.user("viewer")
+
+	tests := []struct {
+		name string
+		// setup 修改被查看的用户或创建相关记录
+		setup func(user *models.User)
+		// visible 为 IsDiscoverable 的结果，candidate 为是否出现在 ListCandidates 中
+		visible   bool
+		candidate bool
+	}{
+		{"普通用户", func(user *models.User) {}, true, true},
+		{"已暂停", func(user *models.User) {
+			f.update(user, map[string]interface{}{"status": models.UserStatusSuspended})
+		}, false, false},
+		{"已封禁", func(user *models.User) {
+			f.update(user, map[string]interface{}{"status": models.UserStatusBanned})
+		}, false, false},
+		{"限制曝光", func(user *models.User) {
+			f.update(user, map[string]interface{}{"risk_level": models.RiskLevelShadowLimited})
+		}, false, false},
+		{"待审核的风险不影响曝光", func(user *models.User) {
+			f.update(user, map[string]interface{}{"risk_level": models.RiskLevelReview})
+		}, true, true},
+		{"已注销", func(user *models.User) {
+			f.must(f.set.Users.SoftDelete(context.Background(), user.ID))
+		}, false, false},
+		{"查看者拉黑了对方", func(user *models.User) { f.block(viewer, user) }, false, false},
+		{"对方拉黑了查看者", func(user *models.User) { f.block(user, viewer) }, false, false},
+		{"关闭可被发现", func(user *models.User) {
+			f.privacy(user, func(settings *models.PrivacySettings) { settings.Discoverable = false })
+		}, false, false},
+		{"隐身的 VIP", func(user *models.User) {
+			f.update(user, map[string]interface{}{"is_vip": true})
+			f.privacy(user, func(settings *models.PrivacySettings) { settings.Incognito = true })
+		}, false, false},
+		{"隐身的 VIP 喜欢过查看者", func(user *models.User) {
+			f.update(user, map[string]interface{}{"is_vip": true})
+			f.privacy(user, func(settings *models.PrivacySettings) { settings.Incognito = true })
+			f.swipe(user, viewer, models.InteractionTypeLike)
+		}, true, true},
+		{"隐身的 VIP 跳过了查看者", func(user *models.User) {
+			f.update(user, map[string]interface{}{"is_vip": true})
+			f.privacy(user, func(settings *models.PrivacySettings) { settings.Incognito = true })
+			f.swipe(user, viewer, models.InteractionTypeDislike)
+		}, false, false},
+		{"非 VIP 的隐身设置不生效", func(user *models.User) {
+			f.privacy(user, func(settings *models.PrivacySettings) { settings.Incognito = true })
+		}, true, true},
+		{"查看者已喜欢", func(user *models.User) { f.swipe(viewer, user, models.InteractionTypeLike) }, true, false},
+		{"查看者已跳过", func(user *models.User) { f.swipe(viewer, user, models.InteractionTypeDislike) }, true, false},
+	}
+
+	users := make([]*models.User, len(tests))
+	for i, tt := range tests {
+		users[i] = f.user("user")
+		tt.setup(users[i])
+	}
+
+	candidates, err := f.set.Discovery.ListCandidates(context.Background(), viewer.ID, defaultUniversity, time.Now(), 100)
+	if err != nil {
+		t.Fatalf("查询推荐失败: %v", err)
+	}
+	inCandidates := make(map[string]bool)
+	for _, candidate := range candidates {
+		inCandidates[candidate.User.ID] = true
+	}
+	if inCandidates[viewer.ID] {
+		t.Errorf("推荐中不应包含查看者自己")
+	}
+	if visible, err := f.set.Discovery.IsDiscoverable(context.Background(), viewer.ID, viewer.ID); err != nil || visible {
+		t.Errorf("查看者对自己不可见: %v", err)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visible, err := f.set.Discovery.IsDiscoverable(context.Background(), viewer.ID, users[i].ID)
+			if err != nil {
+				t.Fatalf("IsDiscoverable 失败: %v", err)
+			}
+			if visible != tt.visible {
+				t.Errorf("IsDiscoverable 期望 %v，实际 %v", tt.visible, visible)
+			}
+			if inCandidates[users[i].ID] != tt.candidate {
+				t.Errorf("ListCandidates 期望包含 %v，实际 %v", tt.candidate, inCandidates[users[i].ID])
+			}
+		})
+	}
+}
+
+func testCandidateOrder(t *testing.T, f *fixture) {
+	viewer := f.user("viewer")
+
+	otherBoosted := f.user("other-boosted")
+	f.update(otherBoosted, map[string]interface{}{"university": "South University"})
+	f.boost(otherBoosted)
+	sameOld := f.user("same-old")
+	sameBoosted := f.user("same-boosted")
+	f.boost(sameBoosted)
+	sameNew := f.user("same-new")
+
+	// 同校优先，同一梯队内加速中的优先，其余按注册时间倒序
+	want := []string{sameBoosted.ID, sameNew.ID, sameOld.ID, otherBoosted.ID}
+
+	candidates, err := f.set.Discovery.ListCandidates(context.Background(), viewer.ID, defaultUniversity, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("查询推荐失败: %v", err)
+	}
+	got := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		got[i] = candidate.User.ID
+		boosted := candidate.User.ID == sameBoosted.ID || candidate.User.ID == otherBoosted.ID
+		if candidate.Boosted != boosted {
+			t.Errorf("%s 的加速状态期望 %v，实际 %v", candidate.User.Name, boosted, candidate.Boosted)
+		}
+	}
+	if !equalIDs(got, want) {
+		t.Fatalf("推荐顺序期望 %v，实际 %v", want, got)
+	}
+
+	limited, err := f.set.Discovery.ListCandidates(context.Background(), viewer.ID, defaultUniversity, time.Now(), 2)
+	if err != nil {
+		t.Fatalf("查询推荐失败: %v", err)
+	}
+	if len(limited) != 2 || limited[0].User.ID != sameBoosted.ID {
+		t.Fatalf("limit 没有生效")
+	}
+}
+
+func testPendingLikes(t *testing.T, f *fixture) {
+	owner := f.user("owner")
+
+	tests := []struct {
+		name    string
+		setup   func(liker *models.User)
+		pending bool
+	}{
+		{"喜欢", func(liker *models.User) { f.swipe(liker, owner, models.InteractionTypeLike) }, true},
+		{"暂停中的用户", func(liker *models.User) {
+			f.swipe(liker, owner, models.InteractionTypeLike)
+			f.update(liker, map[string]interface{}{"status": models.UserStatusSuspended})
+		}, true},
+		{"跳过", func(liker *models.User) { f.swipe(liker, owner, models.InteractionTypeDislike) }, false},
+		{"已喜欢回去", func(liker *models.User) {
+			f.swipe(liker, owner, models.InteractionTypeLike)
+			f.swipe(owner, liker, models.InteractionTypeLike)
+		}, false},
+		{"已跳过对方", func(liker *models.User) {
+			f.swipe(liker, owner, models.InteractionTypeLike)
+			f.swipe(owner, liker, models.InteractionTypeDislike)
+		}, false},
+		{"拉黑了对方", func(liker *models.User) {
+			f.swipe(liker, owner, models.InteractionTypeLike)
+			f.block(owner, liker)
+		}, false},
+		{"被对方拉黑", func(liker *models.User) {
+			f.swipe(liker, owner, models.InteractionTypeLike)
+			f.block(liker, owner)
+		}, false},
+		{"已封禁", func(liker *models.User) {
+			f.swipe(liker, owner, models.InteractionTypeLike)
+			f.update(liker, map[string]interface{}{"status": models.UserStatusBanned})
+		}, false},
+		{"限制曝光", func(liker *models.User) {
+			f.swipe(liker, owner, models.InteractionTypeLike)
+			f.update(liker, map[string]interface{}{"risk_level": models.RiskLevelShadowLimited})
+		}, false},
+		{"已注销", func(liker *models.User) {
+			f.swipe(liker, owner, models.InteractionTypeLike)
+			f.must(f.set.Users.SoftDelete(context.Background(), liker.ID))
+		}, false},
+	}
+
+	// 按喜欢时间倒序
+	var want []string
+	likers := make([]*models.User, len(tests))
+	for i, tt := range tests {
+		likers[i] = f.user("liker")
+		tt.setup(likers[i])
+		if tt.pending {
+			want = append([]string{likers[i].ID}, want...)
+		}
+	}
+
+	likes, total, err := f.set.Interactions.ListPendingLikes(context.Background(), owner.ID, 0, 100)
+	if err != nil {
+		t.Fatalf("查询收到的喜欢失败: %v", err)
+	}
+	got := make([]string, len(likes))
+	pending := make(map[string]bool)
+	for i, like := range likes {
+		got[i] = like.User.ID
+		pending[like.User.ID] = true
+		if like.LikedAt.IsZero() {
+			t.Errorf("缺少喜欢时间")
+		}
+	}
+	for i, tt := range tests {
+		if pending[likers[i].ID] != tt.pending {
+			t.Errorf("%s: 期望包含 %v，实际 %v", tt.name, tt.pending, pending[likers[i].ID])
+		}
+	}
+	if total != int64(len(want)) || !equalIDs(got, want) {
+		t.Fatalf("期望 %v (total %d)，实际 %v (total %d)", want, len(want), got, total)
+	}
+
+	page, total, err := f.set.Interactions.ListPendingLikes(context.Background(), owner.ID, 1, 1)
+	if err != nil {
+		t.Fatalf("分页查询失败: %v", err)
+	}
+	if len(page) != 1 || page[0].User.ID != want[1] || total != int64(len(want)) {
+		t.Fatalf("分页结果错误: %v (total %d)", page, total)
+	}
+}
@@ -0,0 +1,344 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+func testBlocks(t *testing.T, f *fixture) {
+	alice, bob, carol := f.user("alice"), f.user("bob"), f.user("carol")
+	f.block(alice, bob)
+
+	tests := []struct {
+		name    string
+		a, b    *models.User
+		blocked bool
+	}{
+		{"拉黑方", alice, bob, true},
+		{"被拉黑方", bob, alice, true},
+		{"无关用户", alice, carol, false},
+	}
+	for _, tt := range tests {
+		blocked, err := f.set.Blocks.IsBlockedEither(context.Background(), tt.a.ID, tt.b.ID)
+		if err != nil || blocked != tt.blocked {
+			t.Errorf("%s: 期望 %v，实际 %v %v", tt.name, tt.blocked, blocked, err)
+		}
+	}
+
+	// 重复拉黑不报错，也不会产生第二条记录
+	if err := f.set.Blocks.Create(context.Background(), &models.Block{BlockerID: alice.ID, BlockedID: bob.ID}); err != nil {
+		t.Errorf("重复拉黑不应报错: %v", err)
+	}
+
+	if deleted, err := f.set.Blocks.Delete(context.Background(), alice.ID, bob.ID); err != nil || deleted != 1 {
+		t.Fatalf("取消拉黑期望删除 1 条，实际 %d %v", deleted, err)
+	}
+	if deleted, err := f.set.Blocks.Delete(context.Background(), alice.ID, bob.ID); err != nil || deleted != 0 {
+		t.Fatalf("重复取消拉黑期望删除 0 条，实际 %d %v", deleted, err)
+	}
+	if blocked, _ := f.set.Blocks.IsBlockedEither(context.Background(), alice.ID, bob.ID); blocked {
+		t.Fatalf("取消拉黑后不应再有拉黑关系")
+	}
+}
+
+func testMatches(t *testing.T, f *fixture) {
+	alice, bob := f.user("alice"), f.user("bob")
+
+	match := &models.Match{User1ID: alice.ID, User2ID: bob.ID}
+	f.must(f.set.Matches.Create(context.Background(), match))
+	if match.ID == "" {
+		t.Fatalf("创建后应填充 ID")
+	}
+
+	for _, pair := range [][2]string{{alice.ID, bob.ID}, {bob.ID, alice.ID}} {
+		got, err := f.set.Matches.GetByUsers(context.Background(), pair[0], pair[1])
+		if err != nil || got == nil || got.ID != match.ID || !got.IsActive {
+			t.Fatalf("查询匹配不应区分用户顺序: %v %+v", err, got)
+		}
+	}
+
+	f.must(f.set.Matches.Deactivate(context.Background(), bob.ID, alice.ID))
+	if got, _ := f.set.Matches.GetByID(context.Background(), match.ID); got == nil || got.IsActive {
+		t.Fatalf("Deactivate 没有生效: %+v", got)
+	}
+
+	f.must(f.set.Matches.Delete(context.Background(), match.ID))
+	if got, err := f.set.Matches.GetByUsers(context.Background(), alice.ID, bob.ID); err != nil || got != nil {
+		t.Fatalf("删除后不应查到匹配: %v %+v", err, got)
+	}
+}
+
+func testNotifications(t *testing.T, f *fixture) {
+	notifications := f.set.Notifications
+	owner, other := f.user("owner"), f.user("other")
+
+	first := f.notify(owner, "first")
+	second := f.notify(owner, "second")
+	third := f.notify(owner, "third")
+	foreign := f.notify(other, "foreign")
+
+	page, total, err := notifications.ListByUser(context.Background(), owner.ID, false, 0, 2)
+	if err != nil || total != 3 || len(page) != 2 || page[0].ID != third.ID || page[1].ID != second.ID {
+		t.Fatalf("ListByUser 应按时间倒序分页: %v total %d", err, total)
+	}
+
+	if updated, err := notifications.MarkRead(context.Background(), owner.ID, []string{first.ID, foreign.ID}); err != nil || updated != 1 {
+		t.Fatalf("MarkRead 只能修改自己的通知，实际 %d %v", updated, err)
+	}
+	if unread, _ := notifications.CountUnread(context.Background(), owner.ID); unread != 2 {
+		t.Fatalf("期望 2 条未读，实际 %d", unread)
+	}
+	if _, total, _ := notifications.ListByUser(context.Background(), owner.ID, true, 0, 10); total != 2 {
+		t.Fatalf("只查未读时期望 2 条，实际 %d", total)
+	}
+	if updated, err := notifications.MarkAllRead(context.Background(), owner.ID); err != nil || updated != 2 {
+		t.Fatalf("MarkAllRead 期望修改 2 条，实际 %d %v", updated, err)
+	}
+
+	if deleted, err := notifications.Delete(context.Background(), other.ID, second.ID); err != nil || deleted != 0 {
+		t.Fatalf("不能删除别人的通知，实际 %d %v", deleted, err)
+	}
+	if deleted, err := notifications.Delete(context.Background(), owner.ID, second.ID); err != nil || deleted != 1 {
+		t.Fatalf("删除通知期望 1 条，实际 %d %v", deleted, err)
+	}
+
+	after, err := notifications.ListAfter(context.Background(), owner.ID, first.ID, 10)
+	if err != nil || len(after) != 1 || after[0].ID != third.ID {
+		t.Fatalf("ListAfter 期望只返回 third: %v %v", err, after)
+	}
+	if after, err := notifications.ListAfter(context.Background(), owner.ID, foreign.ID, 10); err != nil || len(after) != 0 {
+		t.Fatalf("别人的通知 ID 应返回空列表: %v %v", err, after)
+	}
+
+	testNotificationTies(t, f)
+
+	related := &models.Notification{UserID: owner.ID, Type: models.NotificationTypeLike, Content: "related", RelatedID: other.ID}
+	f.must(notifications.Create(context.Background(), related))
+	f.must(notifications.DeleteByRelatedID(context.Background(), other.ID))
+	all, err := notifications.ListAllByUser(context.Background(), owner.ID)
+	if err != nil || len(all) != 2 || all[0].ID != first.ID || all[1].ID != third.ID {
+		t.Fatalf("DeleteByRelatedID 后期望剩下 first 和 third: %v %v", err, all)
+	}
+}
+
+// testNotificationTies 创建时间相同的通知按 ID 排序，断线重连补发时既不漏发也不重复
+func testNotificationTies(t *testing.T, f *fixture) {
+	notifications := f.set.Notifications
+	user := f.user("ties")
+
+	createdAt := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+	var tied []string
+	for _, content := range []string{"a", "b", "c"} {
+		notification := &models.Notification{UserID: user.ID, Type: models.NotificationTypeSystem, Content: content, CreatedAt: createdAt}
+		f.must(notifications.Create(context.Background(), notification))
+		tied = append(tied, notification.ID)
+	}
+	later := f.notify(user, "later")
+	sort.Strings(tied)
+
+	tests := []struct {
+		name    string
+		afterID string
+		want    []string
+	}{
+		{"同一时间的第一条之后", tied[0], []string{tied[1], tied[2], later.ID}},
+		{"同一时间的中间一条之后", tied[1], []string{tied[2], later.ID}},
+		{"同一时间的最后一条之后", tied[2], []string{later.ID}},
+	}
+	for _, tt := range tests {
+		after, err := notifications.ListAfter(context.Background(), user.ID, tt.afterID, 10)
+		if err != nil {
+			t.Fatalf("%s: 查询失败: %v", tt.name, err)
+		}
+		got := make([]string, len(after))
+		for i, notification := range after {
+			got[i] = notification.ID
+		}
+		if !equalIDs(got, tt.want) {
+			t.Errorf("%s: 期望 %v，实际 %v", tt.name, tt.want, got)
+		}
+	}
+
+	if after, err := notifications.ListAfter(context.Background(), user.ID, tied[0], 2); err != nil || len(after) != 2 || after[1].ID != tied[2] {
+		t.Fatalf("limit 应在排序之后生效: %v %v", err, after)
+	}
+}
+
+func testUsage(t *testing.T, f *fixture) {
+	usage := f.set.Usage
+	user := f.user("user")
+	const day = "2024-03-01"
+
+	for i, want := range []struct {
+		used int
+		ok   bool
+	}{{1, true}, {2, true}, {2, false}} {
+		used, ok, err := usage.IncrementWithin(context.Background(), user.ID, "like", day, 2)
+		if err != nil || used != want.used || ok != want.ok {
+			t.Fatalf("第 %d 次消耗期望 (%d, %v)，实际 (%d, %v) %v", i+1, want.used, want.ok, used, ok, err)
+		}
+	}
+	if used, _ := usage.Get(context.Background(), user.ID, "like", "2024-03-02"); used != 0 {
+		t.Fatalf("不同日期的计数应当独立，实际 %d", used)
+	}
+
+	for i := 0; i < 3; i++ {
+		f.must(usage.Decrement(context.Background(), user.ID, "like", day))
+	}
+	if used, err := usage.Get(context.Background(), user.ID, "like", day); err != nil || used != 0 {
+		t.Fatalf("返还后计数不应小于 0，实际 %d %v", used, err)
+	}
+}
+
+func testAuditChain(t *testing.T, f *fixture) {
+	audit := f.set.Audit
+	admin, target := f.user("admin"), f.user("target")
+
+	events := []*models.AuditEvent{
+		{ActorID: admin.ID, Action: "user.ban", TargetID: target.ID, Metadata: map[string]string{"reason": "spam"}},
+		{Action: "system.purge", TargetID: target.ID},
+		{ActorID: admin.ID, Action: "user.reinstate", TargetID: target.ID, IP: "127.0.0.1"},
+	}
+	for _, event := range events {
+		f.must(audit.Append(context.Background(), event))
+	}
+
+	// 序号连续，每个事件的 PrevHash 等于上一个事件的 Hash，Hash 可以由字段重新计算
+	stored, err := audit.ListAfterSeq(context.Background(), 0, 10)
+	if err != nil || len(stored) != len(events) {
+		t.Fatalf("ListAfterSeq 期望 %d 条: %v %d", len(events), err, len(stored))
+	}
+	prevHash := ""
+	for i, event := range stored {
+		if event.Seq != int64(i+1) {
+			t.Errorf("第 %d 条事件序号为 %d", i+1, event.Seq)
+		}
+		if event.PrevHash != prevHash {
+			t.Errorf("第 %d 条事件没有接到上一条之后", i+1)
+		}
+		if event.Hash != event.ComputeHash() || event.Hash != events[i].Hash {
+			t.Errorf("第 %d 条事件的哈希与内容不一致", i+1)
+		}
+		prevHash = event.Hash
+	}
+
+	if after, err := audit.ListAfterSeq(context.Background(), 2, 10); err != nil || len(after) != 1 || after[0].Seq != 3 {
+		t.Fatalf("ListAfterSeq(2) 期望只返回第 3 条: %v %v", err, after)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name   string
+		filter repositories.AuditFilter
+		want   []int64
+	}{
+		{"全部", repositories.AuditFilter{}, []int64{3, 2, 1}},
+		{"按操作人", repositories.AuditFilter{ActorID: admin.ID}, []int64{3, 1}},
+		{"按操作", repositories.AuditFilter{Action: "system.purge"}, []int64{2}},
+		{"按对象", repositories.AuditFilter{TargetID: admin.ID}, nil},
+		{"时间范围", repositories.AuditFilter{From: ptr(now.Add(-time.Hour)), To: ptr(now.Add(time.Hour))}, []int64{3, 2, 1}},
+		{"时间范围之外", repositories.AuditFilter{From: ptr(now.Add(time.Hour))}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, total, err := audit.List(context.Background(), tt.filter, 0, 10)
+			if err != nil {
+				t.Fatalf("查询失败: %v", err)
+			}
+			got := make([]int64, len(result))
+			for i, event := range result {
+				got[i] = event.Seq
+			}
+			if total != int64(len(tt.want)) || len(got) != len(tt.want) {
+				t.Fatalf("期望 %v，实际 %v (total %d)", tt.want, got, total)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("期望 %v，实际 %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func testTx(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	alice, bob := f.user("alice"), f.user("bob")
+	errAbort := errors.New("abort")
+
+	err := f.set.Tx.WithinTx(ctx, func(tx repositories.Repos) error {
+		if err := tx.Interactions.Create(ctx, &models.Interaction{FromUserID: alice.ID, ToUserID: bob.ID, Type: models.InteractionTypeLike}); err != nil {
+			return err
+		}
+		if err := tx.Matches.Create(ctx, &models.Match{User1ID: alice.ID, User2ID: bob.ID}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatalf("期望返回事务中的错误，实际 %v", err)
+	}
+	if interaction, _ := f.set.Interactions.GetByUsers(ctx, alice.ID, bob.ID); interaction != nil {
+		t.Fatalf("回滚后不应保留交互")
+	}
+	if match, _ := f.set.Matches.GetByUsers(ctx, alice.ID, bob.ID); match != nil {
+		t.Fatalf("回滚后不应保留匹配")
+	}
+
+	err = f.set.Tx.WithinTx(ctx, func(tx repositories.Repos) error {
+		return tx.Interactions.Create(ctx, &models.Interaction{FromUserID: alice.ID, ToUserID: bob.ID, Type: models.InteractionTypeLike})
+	})
+	if err != nil {
+		t.Fatalf("事务执行失败: %v", err)
+	}
+	if interaction, _ := f.set.Interactions.GetByUsers(ctx, alice.ID, bob.ID); interaction == nil {
+		t.Fatalf("提交后应保留交互")
+	}
+}
+
+func testDataExports(t *testing.T, f *fixture) {
+	alice, bob := f.user("alice"), f.user("bob")
+
+	create := func(user *models.User) (*models.DataExport, bool) {
+		t.Helper()
+		export := &models.DataExport{UserID: user.ID, Status: models.DataExportStatusPending}
+		created, err := f.set.DataExports.CreateIfNoneActive(context.Background(), export)
+		if err != nil {
+			t.Fatalf("创建导出任务失败: %v", err)
+		}
+		return export, created
+	}
+
+	first, created := create(alice)
+	if !created {
+		t.Fatalf("没有进行中的任务时应创建")
+	}
+	if _, created := create(alice); created {
+		t.Fatalf("已有待处理的任务时不应重复创建")
+	}
+	if _, created := create(bob); !created {
+		t.Fatalf("其他用户的任务不影响创建")
+	}
+
+	first.Status = models.DataExportStatusProcessing
+	f.must(f.set.DataExports.Update(context.Background(), first))
+	if _, created := create(alice); created {
+		t.Fatalf("已有处理中的任务时不应重复创建")
+	}
+
+	first.Status = models.DataExportStatusFailed
+	f.must(f.set.DataExports.Update(context.Background(), first))
+	if _, created := create(alice); !created {
+		t.Fatalf("之前的任务结束后应可以再次创建")
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
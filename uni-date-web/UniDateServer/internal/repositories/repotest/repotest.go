@@ -0,0 +1,144 @@
+// Package repotest 存储库的契约测试
+// PostgreSQL 和内存实现在各自的测试中调用 Run，保证两者行为一致
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// 测试用户默认的学校
+const defaultUniversity = "North University"
+
+// Factory 为每个测试创建一组只包含空表的存储库
+type Factory func(t *testing.T) repositories.Set
+
+// Run 对 factory 创建的存储库执行全部契约测试，每个测试使用新的存储库
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, f *fixture)
+	}{
+		{"Users", testUsers},
+		{"UserSearch", testUserSearch},
+		{"Visibility", testVisibility},
+		{"CandidateOrder", testCandidateOrder},
+		{"PendingLikes", testPendingLikes},
+		{"Blocks", testBlocks},
+		{"Matches", testMatches},
+		{"Notifications", testNotifications},
+		{"Usage", testUsage},
+		{"DataExports", testDataExports},
+		{"AuditChain", testAuditChain},
+		{"Tx", testTx},
+		{"UserVersion", testUserVersion},
+		{"AccountPurge", testAccountPurge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, &fixture{t: t, set: factory(t)})
+		})
+	}
+}
+
+// fixture 准备测试数据的辅助方法，出错时直接结束测试
+type fixture struct {
+	t   *testing.T
+	set repositories.Set
+	seq int
+}
+
+// user 创建用户，账号和手机号按序号生成，保证唯一
+func (f *fixture) user(name string) *models.User {
+	f.t.Helper()
+	f.seq++
+	user := &models.User{
+		Name:       name,
+		Account:    fmt.Sprintf("%s-%d", name, f.seq),
+		Phone:      fmt.Sprintf("1380000%04d", f.seq),
+		Password:   "password",
+		Birthdate:  "2000-01-01",
+		Gender:     "female",
+		University: defaultUniversity,
+	}
+	f.must(f.set.Users.Create(context.Background(), user))
+	return user
+}
+
+// update 修改用户的列
+func (f *fixture) update(user *models.User, values map[string]interface{}) {
+	f.t.Helper()
+	f.must(f.set.Users.UpdateColumns(context.Background(), user.ID, values))
+}
+
+// swipe 创建 from 对 to 的交互
+func (f *fixture) swipe(from, to *models.User, interactionType string) *models.Interaction {
+	f.t.Helper()
+	interaction := &models.Interaction{FromUserID: from.ID, ToUserID: to.ID, Type: interactionType}
+	f.must(f.set.Interactions.Create(context.Background(), interaction))
+	return interaction
+}
+
+// block 创建 blocker 对 blocked 的拉黑
+func (f *fixture) block(blocker, blocked *models.User) {
+	f.t.Helper()
+	f.must(f.set.Blocks.Create(context.Background(), &models.Block{BlockerID: blocker.ID, BlockedID: blocked.ID}))
+}
+
+// privacy 保存用户的隐私设置，modify 在默认设置的基础上修改
+func (f *fixture) privacy(user *models.User, modify func(settings *models.PrivacySettings)) {
+	f.t.Helper()
+	settings := models.DefaultPrivacySettings(user.ID)
+	modify(settings)
+	f.must(f.set.Privacy.Save(context.Background(), settings))
+}
+
+// boost 为用户创建从现在开始生效的加速
+func (f *fixture) boost(user *models.User) {
+	f.t.Helper()
+	startsAt := time.Now().Add(-time.Minute)
+	endsAt := time.Now().Add(time.Hour)
+	f.must(f.set.Boosts.Create(context.Background(), &models.Boost{UserID: user.ID, Source: models.BoostSourceVIP, StartsAt: &startsAt, EndsAt: &endsAt}))
+}
+
+// notify 为用户创建通知
+func (f *fixture) notify(user *models.User, content string) *models.Notification {
+	f.t.Helper()
+	notification := &models.Notification{UserID: user.ID, Type: models.NotificationTypeSystem, Content: content}
+	f.must(f.set.Notifications.Create(context.Background(), notification))
+	return notification
+}
+
+func (f *fixture) must(err error) {
+	f.t.Helper()
+	if err != nil {
+		f.t.Fatalf("准备测试数据失败: %v", err)
+	}
+}
+
+// ids 返回用户 ID 列表，便于比较
+func ids(users []models.User) []string {
+	result := make([]string, len(users))
+	for i, user := range users {
+		result[i] = user.ID
+	}
+	return result
+}
+
+// equalIDs 按顺序比较两个 ID 列表
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+func testUsers(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	users := f.set.Users
+
+	alice := f.user("alice")
+	if alice.ID == "" || alice.Version != 1 || alice.Status != models.UserStatusActive || alice.Role != models.UserRoleUser {
+		t.Fatalf("创建后应填充 ID 和默认值，实际 %+v", alice)
+	}
+
+	got, err := users.GetByAccount(ctx, alice.Account)
+	if err != nil || got == nil || got.ID != alice.ID {
+		t.Fatalf("按账号查询失败: %v %+v", err, got)
+	}
+	if got, err := users.GetByID(ctx, alice.ID); err != nil || got == nil || got.Account != alice.Account {
+		t.Fatalf("按 ID 查询失败: %v %+v", err, got)
+	}
+	if exists, err := users.CheckAccountExists(ctx, alice.Account); err != nil || !exists {
+		t.Fatalf("账号应当存在: %v", err)
+	}
+	if got, err := users.GetByAccount(ctx, "nobody"); err != nil || got != nil {
+		t.Fatalf("不存在的账号应返回 nil, nil，实际 %v %+v", err, got)
+	}
+
+	duplicate := &models.User{Name: "dup", Account: alice.Account, Phone: "13900000000", Password: "password", Birthdate: "2000-01-01", University: defaultUniversity}
+	if err := users.Create(ctx, duplicate); err == nil {
+		t.Fatalf("重复的账号应当创建失败")
+	}
+
+	f.update(alice, map[string]interface{}{"is_verified": true, "status_reason": "ok"})
+	if got, _ := users.GetByID(ctx, alice.ID); got == nil || !got.IsVerified || got.StatusReason != "ok" {
+		t.Fatalf("UpdateColumns 没有生效: %+v", got)
+	}
+
+	f.must(users.SoftDelete(ctx, alice.ID))
+	if got, err := users.GetByID(ctx, alice.ID); err != nil || got != nil {
+		t.Fatalf("软删除后不应查到用户: %v %+v", err, got)
+	}
+	if got, err := users.GetByIDWithDeleted(ctx, alice.ID); err != nil || got == nil {
+		t.Fatalf("GetByIDWithDeleted 应查到已删除的用户: %v", err)
+	}
+	if got, err := users.GetByAccountWithDeleted(ctx, alice.Account); err != nil || got == nil {
+		t.Fatalf("GetByAccountWithDeleted 应查到已删除的用户: %v", err)
+	}
+	f.must(users.Restore(ctx, alice.ID))
+	if got, err := users.GetByID(ctx, alice.ID); err != nil || got == nil {
+		t.Fatalf("恢复后应查到用户: %v", err)
+	}
+
+	// 暂停到期的用户恢复正常，未到期的不变
+	now := time.Now()
+	expired := f.user("expired")
+	f.update(expired, map[string]interface{}{"status": models.UserStatusSuspended, "suspended_until": now.Add(-time.Minute)})
+	pending := f.user("pending")
+	f.update(pending, map[string]interface{}{"status": models.UserStatusSuspended, "suspended_until": now.Add(time.Hour)})
+
+	reinstated, err := users.ReinstateExpired(ctx, now)
+	if err != nil {
+		t.Fatalf("ReinstateExpired 失败: %v", err)
+	}
+	if !equalIDs(reinstated, []string{expired.ID}) {
+		t.Fatalf("期望只恢复 %s，实际 %v", expired.ID, reinstated)
+	}
+	if got, _ := users.GetByID(ctx, expired.ID); got.Status != models.UserStatusActive || got.SuspendedUntil != nil {
+		t.Fatalf("到期用户没有恢复: %+v", got)
+	}
+	if got, _ := users.GetByID(ctx, pending.ID); got.Status != models.UserStatusSuspended {
+		t.Fatalf("未到期用户不应恢复: %+v", got)
+	}
+}
+
+func testUserSearch(t *testing.T, f *fixture) {
+	ctx := context.Background()
+
+	alice := f.user("alice")
+	alicia := f.user("alicia")
+	f.update(alicia, map[string]interface{}{"university": "South University"})
+	bob := f.user("bob")
+	deleted := f.user("alina")
+	f.must(f.set.Users.SoftDelete(ctx, deleted.ID))
+
+	tests := []struct {
+		name   string
+		filter repositories.UserSearchFilter
+		want   []string
+	}{
+		{"关键字不区分大小写", repositories.UserSearchFilter{Query: "ALI"}, []string{alice.ID, alicia.ID}},
+		{"关键字匹配学校", repositories.UserSearchFilter{Query: "south"}, []string{alicia.ID}},
+		{"按姓名", repositories.UserSearchFilter{Name: "bo"}, []string{bob.ID}},
+		{"包含已删除", repositories.UserSearchFilter{Query: "ali", IncludeDeleted: true}, []string{alice.ID, alicia.ID, deleted.ID}},
+		{"按状态", repositories.UserSearchFilter{Status: models.UserStatusBanned}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, total, err := f.set.Users.Search(ctx, tt.filter, 0, 10)
+			if err != nil {
+				t.Fatalf("查询失败: %v", err)
+			}
+			if total != int64(len(tt.want)) || !sameIDs(ids(result), tt.want) {
+				t.Fatalf("期望 %v，实际 %v (total %d)", tt.want, ids(result), total)
+			}
+
+			// 先统计总数再分页，总数不受分页影响
+			if len(tt.want) > 1 {
+				page, total, err := f.set.Users.Search(ctx, tt.filter, 1, 1)
+				if err != nil {
+					t.Fatalf("分页查询失败: %v", err)
+				}
+				if len(page) != 1 || total != int64(len(tt.want)) {
+					t.Fatalf("分页结果错误: %v (total %d)", ids(page), total)
+				}
+			}
+		})
+	}
+}
+
+func testUserVersion(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	alice := f.user("alice")
+
+	version := func() int {
+		t.Helper()
+		user, err := f.set.Users.GetByIDWithDeleted(ctx, alice.ID)
+		if err != nil || user == nil {
+			t.Fatalf("查询用户失败: %v", err)
+		}
+		return user.Version
+	}
+	expect := func(name string, change func(), bumped bool) {
+		t.Helper()
+		before := version()
+		change()
+		if after := version(); (after > before) != bumped {
+			t.Errorf("%s: 期望递增版本号 %v，实际 %d -> %d", name, bumped, before, after)
+		}
+	}
+
+	expect("认证状态", func() { f.update(alice, map[string]interface{}{"is_verified": true}) }, true)
+	expect("暂停", func() {
+		f.update(alice, map[string]interface{}{"status": models.UserStatusSuspended, "suspended_until": time.Now().Add(-time.Minute)})
+	}, true)
+	expect("修改密码", func() { f.update(alice, map[string]interface{}{"password": "changed"}) }, true)
+	expect("风控字段", func() { f.update(alice, map[string]interface{}{"risk_score": 10}) }, false)
+	expect("暂停到期", func() {
+		_, err := f.set.Users.ReinstateExpired(ctx, time.Now())
+		f.must(err)
+	}, true)
+
+	expect("VIP 未变化", func() { f.must(f.set.Subscriptions.SyncUserVIP(ctx, alice.ID)) }, false)
+	f.must(f.set.Subscriptions.Create(ctx, &models.Subscription{
+		UserID: alice.ID, Plan: models.SubscriptionPlanComp, Status: models.SubscriptionStatusActive,
+		StartDate: time.Now(), ProviderRef: "comp-" + alice.ID,
+	}))
+	expect("开通 VIP", func() { f.must(f.set.Subscriptions.SyncUserVIP(ctx, alice.ID)) }, true)
+}
+
+func testAccountPurge(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	alice, bob := f.user("alice"), f.user("bob")
+	now := time.Now()
+	f.update(alice, map[string]interface{}{
+		"timezone":            "UTC",
+		"timezone_changed_at": now,
+		"role":                models.UserRoleAdmin,
+		"status":              models.UserStatusSuspended,
+		"suspended_until":     now.Add(time.Hour),
+		"risk_cleared_at":     now,
+	})
+	f.must(f.set.Reports.Create(ctx, &models.Report{ReporterID: &alice.ID, ReportedID: bob.ID, Reason: "spam"}))
+	f.must(f.set.Reports.Create(ctx, &models.Report{ReporterID: &bob.ID, ReportedID: alice.ID, Reason: "spam"}))
+	item := &models.ModerationItem{Source: models.ModerationSourceReport, SubjectUserID: alice.ID, Content: "evidence", Status: models.ModerationStatusPending}
+	f.must(f.set.Moderation.Create(ctx, item))
+	f.must(f.set.Users.SoftDelete(ctx, alice.ID))
+
+	if err := f.set.AccountPurge.Purge(ctx, alice.ID); err != nil {
+		t.Fatalf("清除数据失败: %v", err)
+	}
+
+	purged, err := f.set.Users.GetByIDWithDeleted(ctx, alice.ID)
+	if err != nil || purged == nil {
+		t.Fatalf("清除后应保留占位账号: %v", err)
+	}
+	if purged.Name != models.DeletedUserName || purged.Phone != "" || purged.Password != "" {
+		t.Errorf("个人信息没有清除: %+v", purged)
+	}
+	if purged.Timezone != "Asia/Shanghai" || purged.TimezoneChangedAt != nil {
+		t.Errorf("时区应恢复默认值，实际 %q %v", purged.Timezone, purged.TimezoneChangedAt)
+	}
+	if purged.Role != models.UserRoleUser || purged.Status != models.UserStatusActive || purged.SuspendedUntil != nil || purged.RiskClearedAt != nil {
+		t.Errorf("角色、状态和风控记录应恢复默认值: %+v", purged)
+	}
+
+	// 审核证据保留
+	if kept, err := f.set.Moderation.GetByID(ctx, item.ID); err != nil || kept == nil {
+		t.Errorf("审核队列记录不应删除: %v", err)
+	}
+}
+
+// sameIDs 不考虑顺序比较两个 ID 列表
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int)
+	for _, id := range a {
+		seen[id]++
+	}
+	for _, id := range b {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+	return true
+}
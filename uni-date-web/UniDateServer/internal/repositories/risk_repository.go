@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// RiskRepository 风险评分事件仓库接口
+type RiskRepository interface {
+	ListRegistrationTimes(ctx context.Context, ip string, since time.Time) ([]time.Time, error)
+	ListFirstMessages(ctx context.Context, senderID string, since time.Time) ([]FirstMessage, error)
+	ListSwipeTimes(ctx context.Context, userID string, since time.Time) ([]time.Time, error)
+	ListDuplicatePhotos(ctx context.Context, userID string, photos []string, maxDistance int) ([]string, error)
+}
+
+// FirstMessage 用户在某个匹配中发送的第一条消息
+type FirstMessage struct {
+	MatchID   string
+	Content   string
+	CreatedAt time.Time
+}
+
+// riskRepository 风险评分事件仓库实现
+type riskRepository struct {
+	db *gorm.DB
+}
+
+// NewRiskRepository 创建风险评分事件仓库实例
+func NewRiskRepository(db *gorm.DB) RiskRepository {
+	return &riskRepository{
+		db: db,
+	}
+}
+
+// ListRegistrationTimes 查询 since 之后使用该 IP 注册的账号的注册时间，包含已注销账号
+func (r *riskRepository) ListRegistrationTimes(ctx context.Context, ip string, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.User{}).
+		Where("registration_ip = ? AND created_at >= ?", ip, since).
+		Pluck("created_at", &times).Error
+	if err != nil {
+		return nil, err
+	}
+	return times, nil
+}
+
+// ListFirstMessages 查询用户在各匹配中发送的第一条文本消息，只返回 since 之后发送的
+func (r *riskRepository) ListFirstMessages(ctx context.Context, senderID string, since time.Time) ([]FirstMessage, error) {
+	var messages []FirstMessage
+	err := r.db.WithContext(ctx).Raw(`SELECT * FROM (
+			SELECT DISTINCT ON (match_id) match_id, content, created_at
+			FROM messages
+			WHERE sender_id = ? AND content_type = 'text'
+			ORDER BY match_id, created_at
+		) first_messages WHERE created_at >= ?`, senderID, since).
+		Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// ListSwipeTimes 查询 since 之后用户的滑动时间
+func (r *riskRepository) ListSwipeTimes(ctx context.Context, userID string, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := r.db.WithContext(ctx).Model(&models.Interaction{}).
+		Where("from_user_id = ? AND created_at >= ?", userID, since).
+		Pluck("created_at", &times).Error
+	if err != nil {
+		return nil, err
+	}
+	return times, nil
+}
+
+// ListDuplicatePhotos 查询 photos 中与其他未注销账号上传的照片相似的照片
+// 照片按上传时计算的感知哈希比较，汉明距离不超过 maxDistance 视为相似
+func (r *riskRepository) ListDuplicatePhotos(ctx context.Context, userID string, photos []string, maxDistance int) ([]string, error) {
+	if len(photos) == 0 {
+		return nil, nil
+	}
+	var duplicates []string
+	err := r.db.WithContext(ctx).Raw(`SELECT DISTINCT mine.url FROM photos mine
+		JOIN photos other ON other.user_id <> mine.user_id
+			AND length(replace((mine.hash # other.hash)::bit(64)::text, '0', '')) <= ?
+		JOIN users ON users.id = other.user_id AND users.deleted_at IS NULL
+		WHERE mine.user_id = ? AND mine.url IN ?`, maxDistance, userID, photos).
+		Scan(&duplicates).Error
+	if err != nil {
+		return nil, err
+	}
+	return duplicates, nil
+}
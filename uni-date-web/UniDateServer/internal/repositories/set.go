@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+)
+
+// Set 应用用到的全部存储库，由配置的存储后端创建
+type Set struct {
+	Users         UserRepository
+	Notifications NotificationRepository
+	Push          PushRepository
+	Subscriptions SubscriptionRepository
+	Usage         UsageRepository
+	Interactions  InteractionRepository
+	Matches       MatchRepository
+	Blocks        BlockRepository
+	Messages      MessageRepository
+	Boosts        BoostRepository
+	Discovery     DiscoveryRepository
+	Audit         AuditRepository
+	Reports       ReportRepository
+	Appeals       AppealRepository
+	Moderation    ModerationRepository
+	Risk          RiskRepository
+	Photos        PhotoRepository
+	DataExports   DataExportRepository
+	AccountPurge  AccountPurgeRepository
+	Privacy       PrivacyRepository
+	Tx            TxManager
+}
+
+// NewSet 创建基于 PostgreSQL 的存储库集合
+func NewSet(db *gorm.DB, txMaxRetries int) Set {
+	return Set{
+		Users:         NewUserRepository(db),
+		Notifications: NewNotificationRepository(db),
+		Push:          NewPushRepository(db),
+		Subscriptions: NewSubscriptionRepository(db),
+		Usage:         NewUsageRepository(db),
+		Interactions:  NewInteractionRepository(db),
+		Matches:       NewMatchRepository(db),
+		Blocks:        NewBlockRepository(db),
+		Messages:      NewMessageRepository(db),
+		Boosts:        NewBoostRepository(db),
+		Discovery:     NewDiscoveryRepository(db),
+		Audit:         NewAuditRepository(db),
+		Reports:       NewReportRepository(db),
+		Appeals:       NewAppealRepository(db),
+		Moderation:    NewModerationRepository(db),
+		Risk:          NewRiskRepository(db),
+		Photos:        NewPhotoRepository(db),
+		DataExports:   NewDataExportRepository(db),
+		AccountPurge:  NewAccountPurgeRepository(db),
+		Privacy:       NewPrivacyRepository(db),
+		Tx:            NewTxManager(db, txMaxRetries),
+	}
+}
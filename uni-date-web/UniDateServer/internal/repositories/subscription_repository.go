@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// 有效订阅的判断条件，与 models.Subscription.IsValid 保持一致
+const validSubscriptionCondition = "subscriptions.status = 'active' AND (subscriptions.end_date IS NULL OR subscriptions.end_date > ?)"
+
+// SubscriptionRepository 订阅仓库接口
+type SubscriptionRepository interface {
+	Create(ctx context.Context, subscription *models.Subscription) error
+	Update(ctx context.Context, subscription *models.Subscription) error
+	GetByProviderRef(ctx context.Context, providerRef string) (*models.Subscription, error)
+	GetCurrentByUser(ctx context.Context, userID string) (*models.Subscription, error)
+	ExpireDue(ctx context.Context, now time.Time) (int64, error)
+	SyncUserVIP(ctx context.Context, userID string) error
+	SyncAllVIP(ctx context.Context) (int64, error)
+	EndByPlan(ctx context.Context, userID, plan string, now time.Time) (int64, error)
+}
+
+// subscriptionRepository 订阅仓库实现
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepository 创建订阅仓库实例
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{
+		db: db,
+	}
+}
+
+// Create 创建订阅
+func (r *subscriptionRepository) Create(ctx context.Context, subscription *models.Subscription) error {
+	return r.db.WithContext(ctx).Create(subscription).Error
+}
+
+// Update 更新订阅
+func (r *subscriptionRepository) Update(ctx context.Context, subscription *models.Subscription) error {
+	return r.db.WithContext(ctx).Save(subscription).Error
+}
+
+// GetByProviderRef 通过支付平台 ID 查询订阅
+func (r *subscriptionRepository) GetByProviderRef(ctx context.Context, providerRef string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	if err := r.db.WithContext(ctx).Where("provider_ref = ?", providerRef).First(&subscription).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// GetCurrentByUser 查询用户当前有效的订阅，多条时优先返回到期最晚的
+func (r *subscriptionRepository) GetCurrentByUser(ctx context.Context, userID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Where(validSubscriptionCondition, time.Now()).
+		Order("end_date DESC NULLS FIRST").
+		First(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// ExpireDue 将已到期的订阅标记为过期，返回受影响的行数
+func (r *subscriptionRepository) ExpireDue(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Subscription{}).
+		Where("status = ? AND end_date IS NOT NULL AND end_date <= ?", models.SubscriptionStatusActive, now).
+		Update("status", models.SubscriptionStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
+// SyncUserVIP 根据是否存在有效订阅刷新用户的 VIP 标记，标记变化时递增用户的版本号
+func (r *subscriptionRepository) SyncUserVIP(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Exec(
+		"UPDATE users SET is_vip = NOT is_vip, version = version + 1, updated_at = ? WHERE id = ? AND is_vip <> EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.user_id = users.id AND "+validSubscriptionCondition+")",
+		time.Now(), userID, time.Now(),
+	).Error
+}
+
+// SyncAllVIP 撤销所有已无有效订阅用户的 VIP 标记，返回受影响的行数
+func (r *subscriptionRepository) SyncAllVIP(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(
+		"UPDATE users SET is_vip = FALSE, version = version + 1, updated_at = ? WHERE is_vip = TRUE AND NOT EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.user_id = users.id AND "+validSubscriptionCondition+")",
+		time.Now(), time.Now(),
+	)
+	return result.RowsAffected, result.Error
+}
+
+// EndByPlan 立即终止用户某个套餐下所有有效的订阅，返回受影响的行数
+func (r *subscriptionRepository) EndByPlan(ctx context.Context, userID, plan string, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Subscription{}).
+		Where("user_id = ? AND plan = ?", userID, plan).
+		Where(validSubscriptionCondition, now).
+		Updates(map[string]interface{}{
+			"status":     models.SubscriptionStatusCanceled,
+			"end_date":   now,
+			"updated_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 可重试的 PostgreSQL 错误码：序列化失败和死锁
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// txRetryBackoff 每次重试前等待的基础时长，第 n 次重试等待 n 倍
+const txRetryBackoff = 20 * time.Millisecond
+
+// Repos 绑定到同一个事务的存储库集合，只能在 WithinTx 的回调中使用
+type Repos struct {
+	Users         UserRepository
+	Interactions  InteractionRepository
+	Matches       MatchRepository
+	Messages      MessageRepository
+	Notifications NotificationRepository
+	Blocks        BlockRepository
+	Boosts        BoostRepository
+}
+
+// newRepos 用同一个数据库会话创建存储库集合
+func newRepos(db *gorm.DB) Repos {
+	return Repos{
+		Users:         NewUserRepository(db),
+		Interactions:  NewInteractionRepository(db),
+		Matches:       NewMatchRepository(db),
+		Messages:      NewMessageRepository(db),
+		Notifications: NewNotificationRepository(db),
+		Blocks:        NewBlockRepository(db),
+		Boosts:        NewBoostRepository(db),
+	}
+}
+
+// TxManager 事务管理接口，供服务组合跨多个存储库的原子操作
+type TxManager interface {
+	// WithinTx 在可串行化事务中执行 fn，fn 返回错误时回滚
+	// 遇到序列化失败或死锁时整体重试，fn 可能被执行多次，不能在其中产生事务外的副作用
+	WithinTx(ctx context.Context, fn func(tx Repos) error) error
+}
+
+// txManager 事务管理实现
+type txManager struct {
+	db         *gorm.DB
+	maxRetries int
+}
+
+// NewTxManager 创建事务管理实例，maxRetries 为序列化失败后的最大重试次数
+func NewTxManager(db *gorm.DB, maxRetries int) TxManager {
+	return &txManager{
+		db:         db,
+		maxRetries: maxRetries,
+	}
+}
+
+// WithinTx 在可串行化事务中执行 fn，序列化失败时按递增的间隔重试
+func (m *txManager) WithinTx(ctx context.Context, fn func(tx Repos) error) error {
+	for attempt := 0; ; attempt++ {
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(newRepos(tx))
+		}, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		if err == nil || attempt >= m.maxRetries || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt+1) * txRetryBackoff):
+		}
+	}
+}
+
+// isRetryable 判断错误是否为可以重试的事务冲突
+func isRetryable(err error) bool {
+	var sqlErr interface{ SQLState() string }
+	if !errors.As(err, &sqlErr) {
+		return false
+	}
+	switch sqlErr.SQLState() {
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return true
+	}
+	return false
+}
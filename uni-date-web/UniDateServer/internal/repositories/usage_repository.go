@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// UsageRepository 每日使用次数仓库接口
+type UsageRepository interface {
+	Get(ctx context.Context, userID, feature, day string) (int, error)
+	IncrementWithin(ctx context.Context, userID, feature, day string, limit int) (int, bool, error)
+	Decrement(ctx context.Context, userID, feature, day string) error
+}
+
+// usageRepository 每日使用次数仓库实现
+type usageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository 创建使用次数仓库实例
+func NewUsageRepository(db *gorm.DB) UsageRepository {
+	return &usageRepository{
+		db: db,
+	}
+}
+
+// Get 查询某天的使用次数，没有记录时为 0
+func (r *usageRepository) Get(ctx context.Context, userID, feature, day string) (int, error) {
+	var counts []int
+	err := r.db.WithContext(ctx).Model(&models.UsageCounter{}).
+		Where("user_id = ? AND feature = ? AND day = ?", userID, feature, day).
+		Pluck("count", &counts).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+	return counts[0], nil
+}
+
+// IncrementWithin 在不超过 limit 的前提下原子地加一
+// 返回加一后的次数；已达上限时返回 false 且不修改计数
+func (r *usageRepository) IncrementWithin(ctx context.Context, userID, feature, day string, limit int) (int, bool, error) {
+	var counts []int
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO usage_counters (user_id, feature, day, count, updated_at)
+		VALUES (?, ?, ?, 1, ?)
+		ON CONFLICT (user_id, feature, day)
+		DO UPDATE SET count = usage_counters.count + 1, updated_at = EXCLUDED.updated_at
+		WHERE usage_counters.count < ?
+		RETURNING count`,
+		userID, feature, day, time.Now(), limit,
+	).Scan(&counts).Error
+	if err != nil {
+		return 0, false, err
+	}
+	if len(counts) == 0 {
+		return limit, false, nil
+	}
+	return counts[0], true, nil
+}
+
+// Decrement 返还一次使用次数，不会低于 0
+func (r *usageRepository) Decrement(ctx context.Context, userID, feature, day string) error {
+	return r.db.WithContext(ctx).Model(&models.UsageCounter{}).
+		Where("user_id = ? AND feature = ? AND day = ? AND count > 0", userID, feature, day).
+		Update("count", gorm.Expr("count - 1")).Error
+}
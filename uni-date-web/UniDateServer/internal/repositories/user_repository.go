@@ -1,21 +1,64 @@
 package repositories
 
 import (
+	"context"
 	"errors"
-	"fmt"
+	"time"
 
 	"github.com/ShijieLu222/uni-date-server/internal/models"
-	"github.com/ShijieLu222/uni-date-server/internal/repositories/db"
 	"gorm.io/gorm"
 )
 
-// UserRepository 用户仓库接口
+// ErrStaleVersion 条件更新时记录的版本号已经变化
+var ErrStaleVersion = errors.New("记录已被修改")
+
+// UserRepository 用户仓库接口，所有查询都在 ctx 取消或超时后中止
 type UserRepository interface {
-	Create(user *models.User) error
-	GetByAccount(account string) (*models.User, error)
-	GetByID(id string) (*models.User, error)
-	Update(user *models.User) error
-	CheckAccountExists(account string) (bool, error)
+	Create(ctx context.Context, user *models.User) error
+	GetByAccount(ctx context.Context, account string) (*models.User, error)
+	GetByAccountWithDeleted(ctx context.Context, account string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	Update(ctx context.Context, user *models.User, values map[string]interface{}) error
+	CheckAccountExists(ctx context.Context, account string) (bool, error)
+	Search(ctx context.Context, filter UserSearchFilter, offset, limit int) ([]models.User, int64, error)
+	GetByIDWithDeleted(ctx context.Context, id string) (*models.User, error)
+	UpdateColumns(ctx context.Context, id string, values map[string]interface{}) error
+	SoftDelete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	ReinstateExpired(ctx context.Context, now time.Time) ([]string, error)
+	ScheduleDeletion(ctx context.Context, id string, purgeAfter time.Time) error
+}
+
+// versionedColumns 体现在用户资料中的列，修改后递增版本号，使客户端持有的资料版本失效
+// 密码不在资料中返回，修改后同样要求客户端重新获取资料
+var versionedColumns = map[string]bool{
+	"name": true, "phone": true, "account": true, "password": true, "avatar": true,
+	"birthdate": true, "gender": true, "university": true, "major": true,
+	"photos": true, "interests": true, "is_verified": true, "is_vip": true, "timezone": true,
+	"role": true, "status": true, "status_reason": true, "suspended_until": true,
+}
+
+// BumpsVersion 判断按列更新时是否需要递增用户的版本号，已显式设置版本号时不再递增
+func BumpsVersion(values map[string]interface{}) bool {
+	if _, ok := values["version"]; ok {
+		return false
+	}
+	for column := range values {
+		if versionedColumns[column] {
+			return true
+		}
+	}
+	return false
+}
+
+// UserSearchFilter 管理后台的用户查询条件，字段为空时不过滤
+type UserSearchFilter struct {
+	Query          string // 同时匹配账号、姓名和学校
+	Account        string
+	Name           string
+	University     string
+	Status         string
+	IncludeDeleted bool
 }
 
 // userRepository 用户仓库实现
@@ -24,14 +67,14 @@ type userRepository struct {
 }
 
 // NewUserRepository 创建用户仓库实例
-func NewUserRepository() UserRepository {
+func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{
-		db: db.DB,
+		db: db,
 	}
 }
 
 // Create 创建新用户
-func (r *userRepository) Create(user *models.User) error {
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 	// 暂时禁用密码加密，直接使用明文密码
 	// hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	// if err != nil {
@@ -39,16 +82,25 @@ func (r *userRepository) Create(user *models.User) error {
 	// }
 	// user.Password = string(hashedPassword)
 
-	// 直接使用明文密码
-	fmt.Println("正在创建用户，使用明文密码:", user.Password)
-
-	return r.db.Create(user).Error
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
 // GetByAccount 通过账号查询用户
-func (r *userRepository) GetByAccount(account string) (*models.User, error) {
+func (r *userRepository) GetByAccount(ctx context.Context, account string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("account = ?", account).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("account = ?", account).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByAccountWithDeleted 通过账号查询用户，包括已软删除的用户
+func (r *userRepository) GetByAccountWithDeleted(ctx context.Context, account string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Unscoped().Where("account = ?", account).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -58,9 +110,9 @@ func (r *userRepository) GetByAccount(account string) (*models.User, error) {
 }
 
 // GetByID 通过ID查询用户
-func (r *userRepository) GetByID(id string) (*models.User, error) {
+func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("id = ?", id).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -69,17 +121,127 @@ func (r *userRepository) GetByID(id string) (*models.User, error) {
 	return &user, nil
 }
 
-// Update 更新用户信息
-func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+// Update 按读取时的版本号条件更新指定的列并递增版本号
+// 期间已被其他请求修改时不做任何更新，返回 ErrStaleVersion
+func (r *userRepository) Update(ctx context.Context, user *models.User, values map[string]interface{}) error {
+	updates := make(map[string]interface{}, len(values)+1)
+	for column, value := range values {
+		updates[column] = value
+	}
+	updates["version"] = gorm.Expr("version + 1")
+
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND version = ?", user.ID, user.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	user.Version++
+	return nil
 }
 
-// CheckAccountExists 检查账号是否已存在
-func (r *userRepository) CheckAccountExists(account string) (bool, error) {
+// CheckAccountExists 检查账号是否已存在，已注销但数据尚未清除的账号仍占用账号名
+func (r *userRepository) CheckAccountExists(ctx context.Context, account string) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.User{}).Where("account = ?", account).Count(&count).Error
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.User{}).Where("account = ?", account).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+// Search 按条件分页查询用户，按注册时间倒序
+func (r *userRepository) Search(ctx context.Context, filter UserSearchFilter, offset, limit int) ([]models.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{})
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("account ILIKE ? OR name ILIKE ? OR university ILIKE ?", like, like, like)
+	}
+	if filter.Account != "" {
+		query = query.Where("account ILIKE ?", "%"+filter.Account+"%")
+	}
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.University != "" {
+		query = query.Where("university ILIKE ?", "%"+filter.University+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := query.Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// GetByIDWithDeleted 通过ID查询用户，包括已注销的
+func (r *userRepository) GetByIDWithDeleted(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ?", id).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateColumns 只更新指定的列，对已注销的用户同样生效
+// 修改资料中的列时递增版本号，不校验原版本
+func (r *userRepository) UpdateColumns(ctx context.Context, id string, values map[string]interface{}) error {
+	if BumpsVersion(values) {
+		updates := make(map[string]interface{}, len(values)+1)
+		for column, value := range values {
+			updates[column] = value
+		}
+		updates["version"] = gorm.Expr("version + 1")
+		values = updates
+	}
+	return r.db.WithContext(ctx).Unscoped().Model(&models.User{}).Where("id = ?", id).Updates(values).Error
+}
+
+// SoftDelete 软删除用户
+func (r *userRepository) SoftDelete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.User{}).Error
+}
+
+// Restore 恢复已软删除的用户，同时取消待执行的数据清除
+func (r *userRepository) Restore(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&models.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "purge_after": nil}).Error
+}
+
+// ScheduleDeletion 软删除用户并设置彻底清除数据的时间
+func (r *userRepository) ScheduleDeletion(ctx context.Context, id string, purgeAfter time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"deleted_at": time.Now(), "purge_after": purgeAfter}).Error
+}
+
+// ReinstateExpired 将暂停已到期的用户恢复为正常状态，返回被恢复的用户ID
+func (r *userRepository) ReinstateExpired(ctx context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE users SET status = ?, status_reason = '', suspended_until = NULL, version = version + 1, updated_at = ?
+		WHERE status = ? AND suspended_until <= ?
+		RETURNING id`,
+		models.UserStatusActive, now, models.UserStatusSuspended, now,
+	).Scan(&ids).Error
+	return ids, err
+}
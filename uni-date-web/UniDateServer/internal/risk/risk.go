@@ -0,0 +1,124 @@
+package risk
+
+import (
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+)
+
+// 风险等级，按严重程度递增
+const (
+	LevelNone          = models.RiskLevelNone
+	LevelReview        = models.RiskLevelReview
+	LevelShadowLimited = models.RiskLevelShadowLimited
+)
+
+// Message 用户在某个匹配中发送的第一条消息
+type Message struct {
+	MatchID string
+	Content string
+	At      time.Time
+}
+
+// Snapshot 评估时用户的行为事件，由调用方从存储中加载
+// 各规则只读取快照，不访问外部状态，可以直接用构造的事件验证
+type Snapshot struct {
+	Now             time.Time
+	Registrations   []time.Time // 与该用户同一注册 IP 的账号注册时间，包含本人
+	FirstMessages   []Message
+	Swipes          []time.Time
+	DuplicatePhotos []string // 同时出现在其他账号中的照片
+}
+
+// Signal 一条规则的命中结果
+type Signal struct {
+	Rule   string `json:"rule"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail"`
+}
+
+// Assessment 评估结果
+type Assessment struct {
+	Score   int      `json:"score"`
+	Level   string   `json:"level"`
+	Signals []Signal `json:"signals"`
+}
+
+// Rules 返回命中的规则名称
+func (a *Assessment) Rules() []string {
+	rules := make([]string, 0, len(a.Signals))
+	for _, signal := range a.Signals {
+		rules = append(rules, signal.Rule)
+	}
+	return rules
+}
+
+// Rule 风险规则，未命中时返回 nil
+type Rule interface {
+	Name() string
+	Evaluate(snapshot *Snapshot) *Signal
+}
+
+// Engine 风险评分引擎，累加各规则得分并换算为风险等级
+type Engine struct {
+	rules            []Rule
+	reviewScore      int
+	shadowLimitScore int
+}
+
+// NewEngine 创建风险评分引擎
+func NewEngine(reviewScore, shadowLimitScore int, rules ...Rule) *Engine {
+	return &Engine{
+		rules:            rules,
+		reviewScore:      reviewScore,
+		shadowLimitScore: shadowLimitScore,
+	}
+}
+
+// Assess 评估快照
+func (e *Engine) Assess(snapshot *Snapshot) *Assessment {
+	assessment := &Assessment{Signals: []Signal{}}
+	for _, rule := range e.rules {
+		if signal := rule.Evaluate(snapshot); signal != nil {
+			assessment.Score += signal.Score
+			assessment.Signals = append(assessment.Signals, *signal)
+		}
+	}
+
+	switch {
+	case assessment.Score >= e.shadowLimitScore:
+		assessment.Level = LevelShadowLimited
+	case assessment.Score >= e.reviewScore:
+		assessment.Level = LevelReview
+	default:
+		assessment.Level = LevelNone
+	}
+	return assessment
+}
+
+// Lookback 返回各规则中最长的时间窗口，加载快照时只需要这段时间内的事件
+func (e *Engine) Lookback() time.Duration {
+	var lookback time.Duration
+	for _, rule := range e.rules {
+		if windowed, ok := rule.(interface{ Window() time.Duration }); ok && windowed.Window() > lookback {
+			lookback = windowed.Window()
+		}
+	}
+	return lookback
+}
+
+// Escalates 判断风险等级 next 是否比 current 更严重
+func Escalates(current, next string) bool {
+	return severity(next) > severity(current)
+}
+
+func severity(level string) int {
+	switch level {
+	case LevelShadowLimited:
+		return 2
+	case LevelReview:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,183 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+)
+
+// 规则名称
+const (
+	RuleRegistrationsPerIP     = "registrations_per_ip"
+	RuleDuplicateFirstMessages = "duplicate_first_messages"
+	RuleSwipeVelocity          = "swipe_velocity"
+	RuleDuplicatePhotos        = "duplicate_photos"
+)
+
+// NewFromConfig 根据配置创建风险评分引擎，Score 为 0 的规则不启用
+func NewFromConfig(cfg config.RiskConfig) *Engine {
+	var rules []Rule
+	if cfg.RegistrationsPerIP.Score > 0 {
+		rules = append(rules, NewRegistrationsPerIPRule(cfg.RegistrationsPerIP))
+	}
+	if cfg.DuplicateFirstMessages.Score > 0 {
+		rules = append(rules, NewDuplicateFirstMessagesRule(cfg.DuplicateFirstMessages))
+	}
+	if cfg.SwipeVelocity.Score > 0 {
+		rules = append(rules, NewSwipeVelocityRule(cfg.SwipeVelocity))
+	}
+	if cfg.DuplicatePhotos.Score > 0 {
+		rules = append(rules, NewDuplicatePhotosRule(cfg.DuplicatePhotos))
+	}
+	return NewEngine(cfg.ReviewScore, cfg.ShadowLimitScore, rules...)
+}
+
+// registrationsPerIP 同一 IP 在时间窗口内注册的账号数
+type registrationsPerIP struct {
+	config.RiskRuleConfig
+}
+
+// NewRegistrationsPerIPRule 创建同一 IP 注册数规则
+func NewRegistrationsPerIPRule(cfg config.RiskRuleConfig) Rule {
+	return &registrationsPerIP{cfg}
+}
+
+func (r *registrationsPerIP) Name() string          { return RuleRegistrationsPerIP }
+func (r *registrationsPerIP) Window() time.Duration { return r.RiskRuleConfig.Window }
+
+func (r *registrationsPerIP) Evaluate(snapshot *Snapshot) *Signal {
+	count := countSince(snapshot.Registrations, snapshot.Now.Add(-r.RiskRuleConfig.Window))
+	if count < r.Threshold {
+		return nil
+	}
+	return &Signal{
+		Rule:   RuleRegistrationsPerIP,
+		Score:  r.Score,
+		Detail: fmt.Sprintf("同一 IP 在 %s 内注册了 %d 个账号", r.RiskRuleConfig.Window, count),
+	}
+}
+
+// duplicateFirstMessages 在时间窗口内向不同匹配发送相同的首条消息
+// 比较前忽略大小写和空白差异
+type duplicateFirstMessages struct {
+	config.RiskRuleConfig
+}
+
+// NewDuplicateFirstMessagesRule 创建重复首条消息规则
+func NewDuplicateFirstMessagesRule(cfg config.RiskRuleConfig) Rule {
+	return &duplicateFirstMessages{cfg}
+}
+
+func (r *duplicateFirstMessages) Name() string          { return RuleDuplicateFirstMessages }
+func (r *duplicateFirstMessages) Window() time.Duration { return r.RiskRuleConfig.Window }
+
+func (r *duplicateFirstMessages) Evaluate(snapshot *Snapshot) *Signal {
+	since := snapshot.Now.Add(-r.RiskRuleConfig.Window)
+	matches := make(map[string]map[string]bool)
+	most := 0
+	for _, message := range snapshot.FirstMessages {
+		if message.At.Before(since) {
+			continue
+		}
+		key := normalize(message.Content)
+		if key == "" {
+			continue
+		}
+		if matches[key] == nil {
+			matches[key] = make(map[string]bool)
+		}
+		matches[key][message.MatchID] = true
+		if len(matches[key]) > most {
+			most = len(matches[key])
+		}
+	}
+	if most < r.Threshold {
+		return nil
+	}
+	return &Signal{
+		Rule:   RuleDuplicateFirstMessages,
+		Score:  r.Score,
+		Detail: fmt.Sprintf("向 %d 个匹配发送了相同的首条消息", most),
+	}
+}
+
+// swipeVelocity 任意长度为 Window 的时间段内的滑动次数
+type swipeVelocity struct {
+	config.RiskRuleConfig
+}
+
+// NewSwipeVelocityRule 创建滑动速度规则
+func NewSwipeVelocityRule(cfg config.RiskRuleConfig) Rule {
+	return &swipeVelocity{cfg}
+}
+
+func (r *swipeVelocity) Name() string          { return RuleSwipeVelocity }
+func (r *swipeVelocity) Window() time.Duration { return r.RiskRuleConfig.Window }
+
+func (r *swipeVelocity) Evaluate(snapshot *Snapshot) *Signal {
+	swipes := make([]time.Time, len(snapshot.Swipes))
+	copy(swipes, snapshot.Swipes)
+	sort.Slice(swipes, func(i, j int) bool { return swipes[i].Before(swipes[j]) })
+
+	// 滑动窗口求最大事件数
+	peak, start := 0, 0
+	for end := range swipes {
+		for start < end && swipes[end].Sub(swipes[start]) >= r.RiskRuleConfig.Window {
+			start++
+		}
+		if end-start+1 > peak {
+			peak = end - start + 1
+		}
+	}
+	if peak < r.Threshold {
+		return nil
+	}
+	return &Signal{
+		Rule:   RuleSwipeVelocity,
+		Score:  r.Score,
+		Detail: fmt.Sprintf("%s 内滑动 %d 次", r.RiskRuleConfig.Window, peak),
+	}
+}
+
+// duplicatePhotos 与其他账号重复的照片数，不限时间
+type duplicatePhotos struct {
+	config.RiskRuleConfig
+}
+
+// NewDuplicatePhotosRule 创建重复照片规则
+func NewDuplicatePhotosRule(cfg config.RiskRuleConfig) Rule {
+	return &duplicatePhotos{cfg}
+}
+
+func (r *duplicatePhotos) Name() string { return RuleDuplicatePhotos }
+
+func (r *duplicatePhotos) Evaluate(snapshot *Snapshot) *Signal {
+	count := len(snapshot.DuplicatePhotos)
+	if count == 0 || count < r.Threshold {
+		return nil
+	}
+	return &Signal{
+		Rule:   RuleDuplicatePhotos,
+		Score:  r.Score,
+		Detail: fmt.Sprintf("%d 张照片与其他账号重复", count),
+	}
+}
+
+// countSince 统计 since 之后（含）的事件数
+func countSince(events []time.Time, since time.Time) int {
+	count := 0
+	for _, at := range events {
+		if !at.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// normalize 统一大小写并合并空白
+func normalize(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
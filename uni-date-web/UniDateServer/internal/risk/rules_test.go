@@ -0,0 +1,260 @@
+package risk
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+)
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// ago 返回 now 之前 d 的时间
+func ago(d time.Duration) time.Time {
+	return now.Add(-d)
+}
+
+// times 返回 now 之前各偏移量的时间
+func times(offsets ...time.Duration) []time.Time {
+	result := make([]time.Time, len(offsets))
+	for i, offset := range offsets {
+		result[i] = ago(offset)
+	}
+	return result
+}
+
+// ruleCase 单条规则的测试用例
+type ruleCase struct {
+	name     string
+	snapshot Snapshot
+	hit      bool
+}
+
+func runRuleCases(t *testing.T, rule Rule, score int, cases []ruleCase) {
+	t.Helper()
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := tt.snapshot
+			snapshot.Now = now
+			signal := rule.Evaluate(&snapshot)
+			if (signal != nil) != tt.hit {
+				t.Fatalf("期望命中 %v，实际 %+v", tt.hit, signal)
+			}
+			if signal != nil && (signal.Rule != rule.Name() || signal.Score != score || signal.Detail == "") {
+				t.Fatalf("命中结果不完整: %+v", signal)
+			}
+		})
+	}
+}
+
+func TestRegistrationsPerIP(t *testing.T) {
+	rule := NewRegistrationsPerIPRule(config.RiskRuleConfig{Window: time.Hour, Threshold: 3, Score: 40})
+
+	runRuleCases(t, rule, 40, []ruleCase{
+		{"没有注册记录", Snapshot{}, false},
+		{"低于阈值", Snapshot{Registrations: times(0, time.Minute)}, false},
+		{"达到阈值", Snapshot{Registrations: times(0, time.Minute, 30*time.Minute)}, true},
+		{"窗口起点计入", Snapshot{Registrations: times(0, time.Minute, time.Hour)}, true},
+		{"窗口之外不计入", Snapshot{Registrations: times(0, time.Minute, time.Hour+time.Nanosecond)}, false},
+		{"较早的注册不计入", Snapshot{Registrations: times(0, 2*time.Hour, 3*time.Hour, 4*time.Hour)}, false},
+	})
+}
+
+func TestDuplicateFirstMessages(t *testing.T) {
+	rule := NewDuplicateFirstMessagesRule(config.RiskRuleConfig{Window: 24 * time.Hour, Threshold: 3, Score: 30})
+
+	message := func(matchID, content string, at time.Duration) Message {
+		return Message{MatchID: matchID, Content: content, At: ago(at)}
+	}
+
+	runRuleCases(t, rule, 30, []ruleCase{
+		{"低于阈值", Snapshot{FirstMessages: []Message{
+			message("m1", "hi there", 0),
+			message("m2", "hi there", time.Hour),
+		}}, false},
+		{"达到阈值", Snapshot{FirstMessages: []Message{
+			message("m1", "hi there", 0),
+			message("m2", "hi there", time.Hour),
+			message("m3", "hi there", 2*time.Hour),
+		}}, true},
+		{"忽略大小写和空白", Snapshot{FirstMessages: []Message{
+			message("m1", "Hi there", 0),
+			message("m2", "  hi   THERE ", time.Hour),
+			message("m3", "hi\tthere\n", 2*time.Hour),
+		}}, true},
+		{"同一匹配只计一次", Snapshot{FirstMessages: []Message{
+			message("m1", "hi there", 0),
+			message("m1", "hi there", time.Hour),
+			message("m2", "hi there", 2*time.Hour),
+		}}, false},
+		{"内容不同", Snapshot{FirstMessages: []Message{
+			message("m1", "hi there", 0),
+			message("m2", "hello there", time.Hour),
+			message("m3", "hey there", 2*time.Hour),
+		}}, false},
+		{"空白消息不计入", Snapshot{FirstMessages: []Message{
+			message("m1", " ", 0),
+			message("m2", "", time.Hour),
+			message("m3", "\n", 2*time.Hour),
+		}}, false},
+		{"窗口起点计入", Snapshot{FirstMessages: []Message{
+			message("m1", "hi there", 0),
+			message("m2", "hi there", time.Hour),
+			message("m3", "hi there", 24*time.Hour),
+		}}, true},
+		{"窗口之外不计入", Snapshot{FirstMessages: []Message{
+			message("m1", "hi there", 0),
+			message("m2", "hi there", time.Hour),
+			message("m3", "hi there", 24*time.Hour+time.Nanosecond),
+		}}, false},
+	})
+}
+
+func TestSwipeVelocity(t *testing.T) {
+	rule := NewSwipeVelocityRule(config.RiskRuleConfig{Window: time.Minute, Threshold: 5, Score: 20})
+
+	// burst 返回从 start 开始每隔 step 一次、共 n 次的滑动时间
+	burst := func(start time.Duration, step time.Duration, n int) []time.Time {
+		result := make([]time.Time, n)
+		for i := range result {
+			result[i] = ago(start - time.Duration(i)*step)
+		}
+		return result
+	}
+
+	runRuleCases(t, rule, 20, []ruleCase{
+		{"没有滑动", Snapshot{}, false},
+		{"低于阈值", Snapshot{Swipes: burst(time.Minute, 10*time.Second, 4)}, false},
+		{"窗口内达到阈值", Snapshot{Swipes: burst(time.Minute, 14*time.Second, 5)}, true},
+		// 首尾间隔正好等于窗口长度时不在同一个窗口内
+		{"首尾间隔等于窗口", Snapshot{Swipes: burst(time.Minute, 15*time.Second, 5)}, false},
+		{"乱序输入", Snapshot{Swipes: []time.Time{ago(10 * time.Second), ago(50 * time.Second), ago(0), ago(30 * time.Second), ago(20 * time.Second)}}, true},
+		{"历史上的高峰也会命中", Snapshot{Swipes: append(burst(3*time.Hour, time.Second, 5), ago(0))}, true},
+		{"分散在多个窗口", Snapshot{Swipes: append(burst(5*time.Minute, time.Second, 3), burst(time.Minute, time.Second, 3)...)}, false},
+	})
+}
+
+func TestDuplicatePhotos(t *testing.T) {
+	rule := NewDuplicatePhotosRule(config.RiskRuleConfig{Threshold: 2, Score: 50})
+	runRuleCases(t, rule, 50, []ruleCase{
+		{"没有重复", Snapshot{}, false},
+		{"低于阈值", Snapshot{DuplicatePhotos: []string{"a.jpg"}}, false},
+		{"达到阈值", Snapshot{DuplicatePhotos: []string{"a.jpg", "b.jpg"}}, true},
+	})
+
+	// 阈值为 0 时有一张重复即命中，没有重复不命中
+	rule = NewDuplicatePhotosRule(config.RiskRuleConfig{Threshold: 0, Score: 50})
+	runRuleCases(t, rule, 50, []ruleCase{
+		{"阈值为 0 没有重复", Snapshot{}, false},
+		{"阈值为 0 一张重复", Snapshot{DuplicatePhotos: []string{"a.jpg"}}, true},
+	})
+}
+
+// fixedRule 固定得分的规则，score 为 0 时不命中
+type fixedRule struct {
+	name   string
+	score  int
+	window time.Duration
+}
+
+func (r fixedRule) Name() string          { return r.name }
+func (r fixedRule) Window() time.Duration { return r.window }
+
+func (r fixedRule) Evaluate(*Snapshot) *Signal {
+	if r.score == 0 {
+		return nil
+	}
+	return &Signal{Rule: r.name, Score: r.score}
+}
+
+func TestEngineAssess(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []int
+		score  int
+		level  string
+		rules  []string
+	}{
+		{"没有规则命中", []int{0, 0}, 0, LevelNone, []string{}},
+		{"低于复核分数", []int{29, 0}, 29, LevelNone, []string{"r0"}},
+		{"等于复核分数", []int{10, 20}, 30, LevelReview, []string{"r0", "r1"}},
+		{"低于限制曝光分数", []int{59}, 59, LevelReview, []string{"r0"}},
+		{"等于限制曝光分数", []int{30, 30}, 60, LevelShadowLimited, []string{"r0", "r1"}},
+		{"高于限制曝光分数", []int{50, 0, 50}, 100, LevelShadowLimited, []string{"r0", "r2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []Rule
+			for i, score := range tt.scores {
+				rules = append(rules, fixedRule{name: fmt.Sprintf("r%d", i), score: score})
+			}
+			assessment := NewEngine(30, 60, rules...).Assess(&Snapshot{Now: now})
+			if assessment.Score != tt.score || assessment.Level != tt.level {
+				t.Fatalf("期望得分 %d 等级 %q，实际 %d %q", tt.score, tt.level, assessment.Score, assessment.Level)
+			}
+			if !equalStrings(assessment.Rules(), tt.rules) {
+				t.Fatalf("期望命中 %v，实际 %v", tt.rules, assessment.Rules())
+			}
+		})
+	}
+}
+
+func TestEngineLookback(t *testing.T) {
+	engine := NewEngine(30, 60,
+		fixedRule{name: "short", window: time.Minute},
+		fixedRule{name: "long", window: 24 * time.Hour},
+		NewDuplicatePhotosRule(config.RiskRuleConfig{Threshold: 1, Score: 10}),
+	)
+	if lookback := engine.Lookback(); lookback != 24*time.Hour {
+		t.Fatalf("期望 24h，实际 %s", lookback)
+	}
+}
+
+func TestNewFromConfigSkipsDisabledRules(t *testing.T) {
+	engine := NewFromConfig(config.RiskConfig{
+		ReviewScore:        30,
+		ShadowLimitScore:   60,
+		RegistrationsPerIP: config.RiskRuleConfig{Window: time.Hour, Threshold: 1, Score: 0},
+		DuplicatePhotos:    config.RiskRuleConfig{Threshold: 1, Score: 30},
+	})
+	assessment := engine.Assess(&Snapshot{
+		Now:             now,
+		Registrations:   times(0),
+		DuplicatePhotos: []string{"a.jpg"},
+	})
+	if !equalStrings(assessment.Rules(), []string{RuleDuplicatePhotos}) || assessment.Level != LevelReview {
+		t.Fatalf("Score 为 0 的规则不应启用，实际 %v %q", assessment.Rules(), assessment.Level)
+	}
+}
+
+func TestEscalates(t *testing.T) {
+	tests := []struct {
+		current, next string
+		want          bool
+	}{
+		{LevelNone, LevelReview, true},
+		{LevelReview, LevelShadowLimited, true},
+		{LevelNone, LevelShadowLimited, true},
+		{LevelReview, LevelReview, false},
+		{LevelShadowLimited, LevelReview, false},
+		{LevelReview, LevelNone, false},
+	}
+	for _, tt := range tests {
+		if got := Escalates(tt.current, tt.next); got != tt.want {
+			t.Errorf("Escalates(%q, %q) 期望 %v，实际 %v", tt.current, tt.next, tt.want, got)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
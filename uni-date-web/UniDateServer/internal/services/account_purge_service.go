@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/storage"
+)
+
+// 每次清除的账号数量上限，剩余的留给下一次执行
+const purgeBatchSize = 50
+
+// AccountPurgeService 注销账号数据清除服务接口
+type AccountPurgeService interface {
+	PurgeDue(ctx context.Context) error
+}
+
+// accountPurgeService 注销账号数据清除服务实现
+type accountPurgeService struct {
+	purgeRepo    repositories.AccountPurgeRepository
+	photoRepo    repositories.PhotoRepository
+	auditService AuditService
+	storage      storage.Storage
+}
+
+// NewAccountPurgeService 创建注销账号数据清除服务实例
+func NewAccountPurgeService(
+	purgeRepo repositories.AccountPurgeRepository,
+	photoRepo repositories.PhotoRepository,
+	auditService AuditService,
+	storage storage.Storage,
+) AccountPurgeService {
+	return &accountPurgeService{
+		purgeRepo:    purgeRepo,
+		photoRepo:    photoRepo,
+		auditService: auditService,
+		storage:      storage,
+	}
+}
+
+// PurgeDue 清除宽限期已过的注销账号，由定时任务调用
+// 单个账号失败只记录日志，下次执行时重试
+func (s *accountPurgeService) PurgeDue(ctx context.Context) error {
+	users, err := s.purgeRepo.ListDue(ctx, time.Now(), purgeBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err := s.purge(ctx, user.ID); err != nil {
+			log.Printf("清除账号 %s 的数据失败: %v", user.ID, err)
+		}
+	}
+	return nil
+}
+
+// purge 清除单个账号：先记下照片和导出文件，数据库清除成功后再删除文件
+func (s *accountPurgeService) purge(ctx context.Context, userID string) error {
+	photos, err := s.photoRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	exportFiles, err := s.purgeRepo.ListExportFiles(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.purgeRepo.Purge(ctx, userID); err != nil {
+		return err
+	}
+
+	for _, photo := range photos {
+		if err := s.storage.Delete(photo.URL); err != nil {
+			log.Printf("删除照片文件失败: %v", err)
+		}
+	}
+	for _, file := range exportFiles {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("删除导出文件失败: %v", err)
+		}
+	}
+
+	s.auditService.Record(ctx, RequestMeta{}, "", models.AuditActionAccountPurged, userID, map[string]string{
+		"photos": strconv.Itoa(len(photos)),
+	})
+	return nil
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+var (
+	ErrCannotModerateSelf    = errors.New("不能对自己执行该操作")
+	ErrInvalidSuspension     = errors.New("封禁截止时间必须晚于当前时间")
+	ErrUserAlreadyDeleted    = errors.New("用户已被删除")
+	ErrUserNotDeleted        = errors.New("用户未被删除")
+	ErrPaidSubscriptionExist = errors.New("用户有付费订阅，需要先取消")
+)
+
+const (
+	defaultAdminPageSize = 20
+	maxAdminPageSize     = 100
+	tempPasswordBytes    = 6
+)
+
+// AdminUserPage 管理后台用户分页结果
+type AdminUserPage struct {
+	Total    int64         `json:"total"`
+	Items    []models.User `json:"items"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"pageSize"`
+}
+
+// AdminUserDetail 管理后台的完整用户记录
+type AdminUserDetail struct {
+	*models.User
+	DeletedAt    *time.Time           `json:"deletedAt,omitempty"`
+	Subscription *models.Subscription `json:"subscription,omitempty"`
+	RiskScore    int                  `json:"riskScore"`
+	RiskLevel    string               `json:"riskLevel"`
+}
+
+// AdminService 管理后台服务接口
+// 所有修改操作都会写入审计记录，adminID 为执行操作的管理员，meta 为其请求信息
+type AdminService interface {
+	IsAdmin(ctx context.Context, userID string) (bool, error)
+	SearchUsers(ctx context.Context, filter repositories.UserSearchFilter, page, pageSize int) (*AdminUserPage, error)
+	GetUser(ctx context.Context, userID string) (*AdminUserDetail, error)
+	SetVerified(ctx context.Context, meta RequestMeta, adminID, userID string, verified bool) (*AdminUserDetail, error)
+	SetVIP(ctx context.Context, meta RequestMeta, adminID, userID string, vip bool) (*AdminUserDetail, error)
+	Suspend(ctx context.Context, meta RequestMeta, adminID, userID, reason string, until time.Time) (*AdminUserDetail, error)
+	Ban(ctx context.Context, meta RequestMeta, adminID, userID, reason string) (*AdminUserDetail, error)
+	Reinstate(ctx context.Context, meta RequestMeta, adminID, userID, reason string) (*AdminUserDetail, error)
+	DeleteUser(ctx context.Context, meta RequestMeta, adminID, userID, reason string) error
+	RestoreUser(ctx context.Context, meta RequestMeta, adminID, userID string) (*AdminUserDetail, error)
+	ResetPassword(ctx context.Context, meta RequestMeta, adminID, userID string) (string, error)
+}
+
+// adminService 管理后台服务实现
+type adminService struct {
+	userRepo         repositories.UserRepository
+	subscriptionRepo repositories.SubscriptionRepository
+	matchRepo        repositories.MatchRepository
+	auditService     AuditService
+}
+
+// NewAdminService 创建管理后台服务实例
+func NewAdminService(userRepo repositories.UserRepository, subscriptionRepo repositories.SubscriptionRepository, matchRepo repositories.MatchRepository, auditService AuditService) AdminService {
+	return &adminService{
+		userRepo:         userRepo,
+		subscriptionRepo: subscriptionRepo,
+		matchRepo:        matchRepo,
+		auditService:     auditService,
+	}
+}
+
+// IsAdmin 判断用户是否为管理员
+func (s *adminService) IsAdmin(ctx context.Context, userID string) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user != nil && user.Role == models.UserRoleAdmin, nil
+}
+
+// SearchUsers 按账号、姓名、学校等条件查询用户
+func (s *adminService) SearchUsers(ctx context.Context, filter repositories.UserSearchFilter, page, pageSize int) (*AdminUserPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxAdminPageSize {
+		pageSize = defaultAdminPageSize
+	}
+
+	users, total, err := s.userRepo.Search(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		users[i].Password = ""
+	}
+	return &AdminUserPage{
+		Total:    total,
+		Items:    users,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetUser 获取完整用户记录，包括已删除的用户和当前订阅
+func (s *adminService) GetUser(ctx context.Context, userID string) (*AdminUserDetail, error) {
+	user, err := s.userRepo.GetByIDWithDeleted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	user.Password = ""
+
+	detail := &AdminUserDetail{User: user, RiskScore: user.RiskScore, RiskLevel: user.RiskLevel}
+	if user.DeletedAt.Valid {
+		deletedAt := user.DeletedAt.Time
+		detail.DeletedAt = &deletedAt
+	}
+	detail.Subscription, err = s.subscriptionRepo.GetCurrentByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// SetVerified 设置用户的认证状态
+func (s *adminService) SetVerified(ctx context.Context, meta RequestMeta, adminID, userID string, verified bool) (*AdminUserDetail, error) {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateColumns(ctx, userID, map[string]interface{}{"is_verified": verified}); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminSetVerified, userID, map[string]string{
+		"value": strconv.FormatBool(verified),
+	})
+	return s.GetUser(ctx, userID)
+}
+
+// SetVIP 开通或撤销赠送的 VIP
+// VIP 由有效订阅推导，开通时创建一条不限期的赠送订阅；撤销只终止赠送订阅，付费订阅需用户自行取消
+func (s *adminService) SetVIP(ctx context.Context, meta RequestMeta, adminID, userID string, vip bool) (*AdminUserDetail, error) {
+	detail, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if vip {
+		if !detail.IsVIP {
+			ref, err := randomHex(16)
+			if err != nil {
+				return nil, err
+			}
+			subscription := &models.Subscription{
+				UserID:        userID,
+				Plan:          models.SubscriptionPlanComp,
+				Status:        models.SubscriptionStatusActive,
+				StartDate:     now,
+				PaymentMethod: "admin",
+				ProviderRef:   "admin:" + ref,
+			}
+			if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if _, err := s.subscriptionRepo.EndByPlan(ctx, userID, models.SubscriptionPlanComp, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.subscriptionRepo.SyncUserVIP(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	detail, err = s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminSetVIP, userID, map[string]string{
+		"value": strconv.FormatBool(vip),
+	})
+	if !vip && detail.IsVIP {
+		return detail, ErrPaidSubscriptionExist
+	}
+	return detail, nil
+}
+
+// Suspend 暂停用户账号到指定时间
+func (s *adminService) Suspend(ctx context.Context, meta RequestMeta, adminID, userID, reason string, until time.Time) (*AdminUserDetail, error) {
+	if !until.After(time.Now()) {
+		return nil, ErrInvalidSuspension
+	}
+	err := s.setStatus(ctx, adminID, userID, map[string]interface{}{
+		"status":          models.UserStatusSuspended,
+		"status_reason":   reason,
+		"suspended_until": until,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminSuspend, userID, map[string]string{
+		"reason": reason,
+		"until":  until.Format(time.RFC3339),
+	})
+	return s.GetUser(ctx, userID)
+}
+
+// Ban 永久封禁用户账号，同时解除其所有匹配
+func (s *adminService) Ban(ctx context.Context, meta RequestMeta, adminID, userID, reason string) (*AdminUserDetail, error) {
+	err := s.setStatus(ctx, adminID, userID, map[string]interface{}{
+		"status":          models.UserStatusBanned,
+		"status_reason":   reason,
+		"suspended_until": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.matchRepo.DeactivateAllForUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminBan, userID, map[string]string{
+		"reason": reason,
+	})
+	return s.GetUser(ctx, userID)
+}
+
+// Reinstate 解除暂停或封禁
+func (s *adminService) Reinstate(ctx context.Context, meta RequestMeta, adminID, userID, reason string) (*AdminUserDetail, error) {
+	err := s.setStatus(ctx, adminID, userID, map[string]interface{}{
+		"status":          models.UserStatusActive,
+		"status_reason":   "",
+		"suspended_until": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminReinstate, userID, map[string]string{
+		"reason": reason,
+	})
+	return s.GetUser(ctx, userID)
+}
+
+// DeleteUser 软删除用户，可以通过 RestoreUser 恢复
+func (s *adminService) DeleteUser(ctx context.Context, meta RequestMeta, adminID, userID, reason string) error {
+	if adminID == userID {
+		return ErrCannotModerateSelf
+	}
+	detail, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if detail.DeletedAt != nil {
+		return ErrUserAlreadyDeleted
+	}
+	if err := s.userRepo.SoftDelete(ctx, userID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminDelete, userID, map[string]string{
+		"reason": reason,
+	})
+	return nil
+}
+
+// RestoreUser 恢复被软删除的用户
+func (s *adminService) RestoreUser(ctx context.Context, meta RequestMeta, adminID, userID string) (*AdminUserDetail, error) {
+	detail, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if detail.DeletedAt == nil {
+		return nil, ErrUserNotDeleted
+	}
+	if err := s.userRepo.Restore(ctx, userID); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminRestore, userID, nil)
+	return s.GetUser(ctx, userID)
+}
+
+// ResetPassword 将用户密码重置为随机临时密码并返回，临时密码不会写入审计记录
+func (s *adminService) ResetPassword(ctx context.Context, meta RequestMeta, adminID, userID string) (string, error) {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return "", err
+	}
+	password, err := randomHex(tempPasswordBytes)
+	if err != nil {
+		return "", err
+	}
+	if err := s.userRepo.UpdateColumns(ctx, userID, map[string]interface{}{"password": password}); err != nil {
+		return "", err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminResetPassword, userID, nil)
+	return password, nil
+}
+
+// setStatus 修改账号状态，不允许管理员修改自己的状态
+func (s *adminService) setStatus(ctx context.Context, adminID, userID string, values map[string]interface{}) error {
+	if adminID == userID {
+		return ErrCannotModerateSelf
+	}
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return err
+	}
+	return s.userRepo.UpdateColumns(ctx, userID, values)
+}
+
+// randomHex 生成 n 字节的随机十六进制字符串
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
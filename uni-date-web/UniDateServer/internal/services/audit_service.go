@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+	auditVerifyBatchSize = 500
+)
+
+// RequestMeta 发起请求的客户端信息，随审计事件一起记录
+type RequestMeta struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// AuditEventPage 审计事件分页结果
+type AuditEventPage struct {
+	Total    int64               `json:"total"`
+	Items    []models.AuditEvent `json:"items"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+}
+
+// AuditChainReport 哈希链校验结果，BrokenAt 为第一条校验失败的事件序号
+type AuditChainReport struct {
+	Valid    bool  `json:"valid"`
+	Checked  int64 `json:"checked"`
+	BrokenAt int64 `json:"brokenAt,omitempty"`
+}
+
+// AuditService 审计服务接口
+type AuditService interface {
+	Record(ctx context.Context, meta RequestMeta, actorID, action, targetID string, metadata map[string]string)
+	List(ctx context.Context, filter repositories.AuditFilter, page, pageSize int) (*AuditEventPage, error)
+	VerifyChain(ctx context.Context) (*AuditChainReport, error)
+}
+
+// auditService 审计服务实现
+type auditService struct {
+	auditRepo repositories.AuditRepository
+}
+
+// NewAuditService 创建审计服务实例
+func NewAuditService(auditRepo repositories.AuditRepository) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record 记录一条审计事件，失败只记录日志，不影响业务操作
+func (s *auditService) Record(ctx context.Context, meta RequestMeta, actorID, action, targetID string, metadata map[string]string) {
+	event := &models.AuditEvent{
+		ActorID:   actorID,
+		Action:    action,
+		TargetID:  targetID,
+		IP:        meta.IP,
+		UserAgent: meta.UserAgent,
+		RequestID: meta.RequestID,
+		Metadata:  metadata,
+	}
+	if err := s.auditRepo.Append(ctx, event); err != nil {
+		log.Printf("记录审计事件失败: %s %v", action, err)
+	}
+}
+
+// List 按条件分页查询审计事件
+func (s *auditService) List(ctx context.Context, filter repositories.AuditFilter, page, pageSize int) (*AuditEventPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxAuditPageSize {
+		pageSize = defaultAuditPageSize
+	}
+
+	events, total, err := s.auditRepo.List(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditEventPage{
+		Total:    total,
+		Items:    events,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// VerifyChain 从头校验哈希链，发现序号不连续、前序哈希不匹配或内容被修改时停止
+func (s *auditService) VerifyChain(ctx context.Context) (*AuditChainReport, error) {
+	report := &AuditChainReport{Valid: true}
+	var lastSeq int64
+	var lastHash string
+	for {
+		events, err := s.auditRepo.ListAfterSeq(ctx, lastSeq, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for i := range events {
+			event := &events[i]
+			if event.Seq != lastSeq+1 || event.PrevHash != lastHash || event.ComputeHash() != event.Hash {
+				report.Valid = false
+				report.BrokenAt = lastSeq + 1
+				return report, nil
+			}
+			lastSeq = event.Seq
+			lastHash = event.Hash
+			report.Checked++
+		}
+		if len(events) < auditVerifyBatchSize {
+			return report, nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+)
+
+// fixedAuditRepository 返回固定的事件列表，用于构造被篡改的哈希链
+type fixedAuditRepository struct {
+	repositories.AuditRepository
+	events []models.AuditEvent
+}
+
+func (r *fixedAuditRepository) ListAfterSeq(ctx context.Context, seq int64, limit int) ([]models.AuditEvent, error) {
+	var events []models.AuditEvent
+	for _, event := range r.events {
+		if event.Seq > seq && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// auditChain 通过内存存储追加 n 条事件，返回完整的哈希链
+func auditChain(t *testing.T, n int) []models.AuditEvent {
+	t.Helper()
+	repo := memory.NewSet(memory.NewStore()).Audit
+	for i := 0; i < n; i++ {
+		event := &models.AuditEvent{ActorID: "admin", Action: models.AuditActionLoginSuccess, TargetID: fmt.Sprintf("user-%d", i)}
+		if err := repo.Append(context.Background(), event); err != nil {
+			t.Fatalf("追加审计事件失败: %v", err)
+		}
+	}
+	events, err := repo.ListAfterSeq(context.Background(), 0, n+1)
+	if err != nil || len(events) != n {
+		t.Fatalf("读取审计事件失败: %d %v", len(events), err)
+	}
+	return events
+}
+
+func TestVerifyChainDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		tamper   func(events []models.AuditEvent) []models.AuditEvent
+		valid    bool
+		brokenAt int64
+	}{
+		{"空链", 0, nil, true, 0},
+		{"未修改", 5, nil, true, 0},
+		{"跨多个批次", auditVerifyBatchSize + 3, nil, true, 0},
+		{"修改内容", 5, func(events []models.AuditEvent) []models.AuditEvent {
+			events[2].TargetID = "someone-else"
+			return events
+		}, false, 3},
+		{"修改内容并重算哈希", 5, func(events []models.AuditEvent) []models.AuditEvent {
+			events[2].TargetID = "someone-else"
+			events[2].Hash = events[2].ComputeHash()
+			return events
+		}, false, 4},
+		{"删除中间的事件", 5, func(events []models.AuditEvent) []models.AuditEvent {
+			return append(events[:2], events[3:]...)
+		}, false, 3},
+		{"删除第一条事件", 5, func(events []models.AuditEvent) []models.AuditEvent {
+			return events[1:]
+		}, false, 1},
+		{"修改前序哈希", 5, func(events []models.AuditEvent) []models.AuditEvent {
+			events[1].PrevHash = events[0].PrevHash
+			return events
+		}, false, 2},
+		{"第二个批次中被修改", auditVerifyBatchSize + 3, func(events []models.AuditEvent) []models.AuditEvent {
+			events[auditVerifyBatchSize+1].Action = models.AuditActionProfileUpdate
+			return events
+		}, false, auditVerifyBatchSize + 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := auditChain(t, tt.size)
+			if tt.tamper != nil {
+				events = tt.tamper(events)
+			}
+			report, err := NewAuditService(&fixedAuditRepository{events: events}).VerifyChain(context.Background())
+			if err != nil {
+				t.Fatalf("校验失败: %v", err)
+			}
+			if report.Valid != tt.valid || report.BrokenAt != tt.brokenAt {
+				t.Fatalf("期望 valid=%v brokenAt=%d，实际 %+v", tt.valid, tt.brokenAt, report)
+			}
+			if tt.valid && report.Checked != int64(tt.size) {
+				t.Fatalf("期望校验 %d 条，实际 %d", tt.size, report.Checked)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+var (
+	ErrCannotBlockSelf = errors.New("不能拉黑自己")
+	ErrBlockNotFound   = errors.New("未拉黑该用户")
+)
+
+// BlockService 拉黑服务接口
+type BlockService interface {
+	Block(ctx context.Context, meta RequestMeta, blockerID, blockedID string) error
+	Unblock(ctx context.Context, meta RequestMeta, blockerID, blockedID string) error
+}
+
+// blockService 拉黑服务实现
+type blockService struct {
+	blockRepo    repositories.BlockRepository
+	matchRepo    repositories.MatchRepository
+	userRepo     repositories.UserRepository
+	auditService AuditService
+}
+
+// NewBlockService 创建拉黑服务实例
+func NewBlockService(blockRepo repositories.BlockRepository, matchRepo repositories.MatchRepository, userRepo repositories.UserRepository, auditService AuditService) BlockService {
+	return &blockService{
+		blockRepo:    blockRepo,
+		matchRepo:    matchRepo,
+		userRepo:     userRepo,
+		auditService: auditService,
+	}
+}
+
+// Block 拉黑用户，同时解除双方的匹配
+func (s *blockService) Block(ctx context.Context, meta RequestMeta, blockerID, blockedID string) error {
+	if blockerID == blockedID {
+		return ErrCannotBlockSelf
+	}
+
+	target, err := s.userRepo.GetByID(ctx, blockedID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return ErrUserNotFound
+	}
+
+	block := &models.Block{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+	}
+	if err := s.blockRepo.Create(ctx, block); err != nil {
+		return err
+	}
+	if err := s.matchRepo.Deactivate(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, meta, blockerID, models.AuditActionBlock, blockedID, nil)
+	return nil
+}
+
+// Unblock 取消拉黑，已解除的匹配不会恢复
+func (s *blockService) Unblock(ctx context.Context, meta RequestMeta, blockerID, blockedID string) error {
+	affected, err := s.blockRepo.Delete(ctx, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrBlockNotFound
+	}
+	s.auditService.Record(ctx, meta, blockerID, models.AuditActionUnblock, blockedID, nil)
+	return nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/payment"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+var (
+	ErrBoostActive = errors.New("已有生效中的加速")
+)
+
+const (
+	defaultBoostsPageSize = 20
+	maxBoostsPageSize     = 50
+)
+
+// BoostStatus 当前加速状态
+type BoostStatus struct {
+	Active       *models.Boost `json:"active,omitempty"`
+	Credits      int64         `json:"credits"`      // 已购买未使用的次数
+	VIPRemaining int           `json:"vipRemaining"` // 今日剩余的会员赠送次数，-1 表示不限
+}
+
+// BoostHistoryPage 加速记录分页结果，包含每次加速的曝光和喜欢统计
+type BoostHistoryPage struct {
+	Total    int64          `json:"total"`
+	Items    []models.Boost `json:"items"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+}
+
+// BoostService 资料加速服务接口
+type BoostService interface {
+	Activate(ctx context.Context, userID string) (*models.Boost, error)
+	GetStatus(ctx context.Context, userID string) (*BoostStatus, error)
+	ListHistory(ctx context.Context, userID string, page, pageSize int) (*BoostHistoryPage, error)
+	Checkout(ctx context.Context, userID string) (*payment.CheckoutSession, error)
+	Credit(ctx context.Context, event *payment.WebhookEvent) error
+	RecordImpressions(ctx context.Context, userIDs []string)
+	RecordLike(ctx context.Context, userID string)
+}
+
+// boostService 资料加速服务实现
+type boostService struct {
+	boostRepo           repositories.BoostRepository
+	txManager           repositories.TxManager
+	entitlementService  EntitlementService
+	notificationService NotificationService
+	paymentProvider     payment.PaymentProvider
+	config              *config.Config
+}
+
+// NewBoostService 创建资料加速服务实例
+func NewBoostService(
+	boostRepo repositories.BoostRepository,
+	txManager repositories.TxManager,
+	entitlementService EntitlementService,
+	notificationService NotificationService,
+	paymentProvider payment.PaymentProvider,
+	config *config.Config,
+) BoostService {
+	return &boostService{
+		boostRepo:           boostRepo,
+		txManager:           txManager,
+		entitlementService:  entitlementService,
+		notificationService: notificationService,
+		paymentProvider:     paymentProvider,
+		config:              config,
+	}
+}
+
+// Activate 激活一次加速
+// 优先使用当天的会员赠送次数（不用会过期），用完后再使用购买的次数
+// 两者都没有时返回 *QuotaExceededError，前端据此引导购买或升级
+// 检查生效中的加速和激活在同一个可串行化事务中完成，同时发起的多次激活只有一次成功，其余返回 ErrBoostActive
+func (s *boostService) Activate(ctx context.Context, userID string) (*models.Boost, error) {
+	// 先在事务外检查一次，已有生效中的加速时不消耗次数
+	active, err := s.boostRepo.GetActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, ErrBoostActive
+	}
+
+	// 配额计数不在事务中，事务重试时不能重复消耗，因此在事务之前确定使用哪种次数
+	var quotaErr *QuotaExceededError
+	_, err = s.entitlementService.Consume(ctx, userID, FeatureBoost)
+	if err != nil && !errors.As(err, &quotaErr) {
+		return nil, err
+	}
+	fromVIP := err == nil
+
+	var boost *models.Boost
+	err = s.txManager.WithinTx(ctx, func(tx repositories.Repos) error {
+		boost = nil
+
+		now := time.Now()
+		active, err := tx.Boosts.GetActiveByUser(ctx, userID, now)
+		if err != nil {
+			return err
+		}
+		if active != nil {
+			return ErrBoostActive
+		}
+
+		startsAt := now
+		endsAt := now.Add(s.config.Boost.Duration)
+		if fromVIP {
+			boost = &models.Boost{
+				UserID:   userID,
+				Source:   models.BoostSourceVIP,
+				StartsAt: &startsAt,
+				EndsAt:   &endsAt,
+			}
+			return tx.Boosts.Create(ctx, boost)
+		}
+
+		boost, err = tx.Boosts.ClaimUnused(ctx, userID, startsAt, endsAt)
+		if err != nil {
+			return err
+		}
+		if boost == nil {
+			return quotaErr
+		}
+		return nil
+	})
+	if err != nil {
+		if fromVIP {
+			s.entitlementService.Refund(context.WithoutCancel(ctx), userID, FeatureBoost)
+		}
+		return nil, err
+	}
+	return boost, nil
+}
+
+// GetStatus 获取当前加速状态和可用次数
+func (s *boostService) GetStatus(ctx context.Context, userID string) (*BoostStatus, error) {
+	active, err := s.boostRepo.GetActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	credits, err := s.boostRepo.CountUnused(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	vipRemaining, err := s.entitlementService.Remaining(ctx, userID, FeatureBoost)
+	if err != nil {
+		return nil, err
+	}
+	return &BoostStatus{
+		Active:       active,
+		Credits:      credits,
+		VIPRemaining: vipRemaining,
+	}, nil
+}
+
+// ListHistory 分页获取加速记录及期间的曝光和喜欢数
+func (s *boostService) ListHistory(ctx context.Context, userID string, page, pageSize int) (*BoostHistoryPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxBoostsPageSize {
+		pageSize = defaultBoostsPageSize
+	}
+
+	boosts, total, err := s.boostRepo.ListByUser(ctx, userID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &BoostHistoryPage{
+		Total:    total,
+		Items:    boosts,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// Checkout 创建购买单次加速的支付会话
+func (s *boostService) Checkout(ctx context.Context, userID string) (*payment.CheckoutSession, error) {
+	return s.paymentProvider.CreateCheckout(userID, models.BoostProduct)
+}
+
+// Credit 支付成功后发放一次未使用的加速
+// 以支付会话 ID 去重，支付平台重复投递回调时不会重复发放
+func (s *boostService) Credit(ctx context.Context, event *payment.WebhookEvent) error {
+	if event.UserID == "" || event.ProviderRef == "" {
+		return ErrInvalidPlan
+	}
+
+	providerRef := event.ProviderRef
+	boost := &models.Boost{
+		UserID:      event.UserID,
+		Source:      models.BoostSourcePurchase,
+		ProviderRef: &providerRef,
+	}
+	if err := s.boostRepo.CreateIfAbsent(ctx, boost); err != nil {
+		return err
+	}
+	// 重复回调时不会插入新记录，ID 为空
+	if boost.ID == "" {
+		return nil
+	}
+
+	if _, err := s.notificationService.Notify(ctx, event.UserID, models.NotificationTypeSystem, "资料加速购买成功，随时可以使用", ""); err != nil {
+		log.Printf("发送加速购买通知失败: %v", err)
+	}
+	return nil
+}
+
+// RecordImpressions 记录加速中的用户在推荐列表中被展示，失败只记录日志
+func (s *boostService) RecordImpressions(ctx context.Context, userIDs []string) {
+	if err := s.boostRepo.IncrementImpressions(ctx, userIDs, time.Now()); err != nil {
+		log.Printf("记录加速曝光失败: %v", err)
+	}
+}
+
+// RecordLike 记录加速中的用户收到喜欢，失败只记录日志
+func (s *boostService) RecordLike(ctx context.Context, userID string) {
+	if err := s.boostRepo.IncrementLikes(ctx, userID, time.Now()); err != nil {
+		log.Printf("记录加速喜欢失败: %v", err)
+	}
+}
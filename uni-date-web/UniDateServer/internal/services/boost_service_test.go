@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+)
+
+func TestActivateConcurrentRequestsActivateOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		vip     bool
+		credits int
+	}{
+		{"会员赠送次数", true, 0},
+		{"购买的次数", false, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Entitlement: config.EntitlementConfig{
+					DefaultTimezone: "UTC",
+					VIP:             config.TierLimits{DailyBoosts: 5},
+				},
+				Boost: config.BoostConfig{Duration: 30 * time.Minute},
+			}
+			repos := memory.NewSet(memory.NewStore())
+			entitlements := NewEntitlementService(repos.Users, repos.Usage, cfg)
+			boosts := NewBoostService(repos.Boosts, repos.Tx, entitlements, nil, nil, cfg)
+
+			user := &models.User{Name: "user", Account: "user", Password: "password", University: "North University", Timezone: "UTC", IsVIP: tt.vip}
+			if err := repos.Users.Create(context.Background(), user); err != nil {
+				t.Fatalf("创建用户失败: %v", err)
+			}
+			for i := 0; i < tt.credits; i++ {
+				ref := fmt.Sprintf("checkout-%d", i)
+				if err := repos.Boosts.Create(context.Background(), &models.Boost{UserID: user.ID, Source: models.BoostSourcePurchase, ProviderRef: &ref}); err != nil {
+					t.Fatalf("发放加速失败: %v", err)
+				}
+			}
+
+			const requests = 5
+			errs := make([]error, requests)
+			var wg sync.WaitGroup
+			for i := range errs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = boosts.Activate(context.Background(), user.ID)
+				}()
+			}
+			wg.Wait()
+
+			activated := 0
+			for _, err := range errs {
+				switch err {
+				case nil:
+					activated++
+				case ErrBoostActive:
+				default:
+					t.Fatalf("意外的错误: %v", err)
+				}
+			}
+			if activated != 1 {
+				t.Fatalf("期望只激活一次，实际 %d 次", activated)
+			}
+
+			status, err := boosts.GetStatus(context.Background(), user.ID)
+			if err != nil {
+				t.Fatalf("查询加速状态失败: %v", err)
+			}
+			if status.Active == nil {
+				t.Fatalf("应有生效中的加速")
+			}
+			wantVIP, wantCredits := 0, int64(tt.credits-1)
+			if tt.vip {
+				wantVIP, wantCredits = 4, 0
+			}
+			if status.VIPRemaining != wantVIP || status.Credits != wantCredits {
+				t.Fatalf("期望剩余赠送 %d 次、购买 %d 次，实际 %d、%d", wantVIP, wantCredits, status.VIPRemaining, status.Credits)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/dto"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/storage"
+)
+
+var (
+	ErrExportNotFound    = errors.New("导出记录不存在")
+	ErrExportLinkInvalid = errors.New("下载链接无效或已过期")
+)
+
+// DataExportStatus 导出任务状态，就绪时附带有时效的下载链接
+type DataExportStatus struct {
+	*models.DataExport
+	DownloadURL string `json:"downloadUrl,omitempty"`
+}
+
+// DataExportService 个人数据导出服务接口
+type DataExportService interface {
+	Request(ctx context.Context, userID string) (*DataExportStatus, error)
+	ProcessPending(ctx context.Context) error
+	Cleanup(ctx context.Context) error
+	OpenDownload(ctx context.Context, exportID string, expires int64, signature string) (string, error)
+}
+
+// dataExportService 个人数据导出服务实现
+type dataExportService struct {
+	exportRepo          repositories.DataExportRepository
+	userRepo            repositories.UserRepository
+	photoRepo           repositories.PhotoRepository
+	interactionRepo     repositories.InteractionRepository
+	matchRepo           repositories.MatchRepository
+	messageRepo         repositories.MessageRepository
+	notificationRepo    repositories.NotificationRepository
+	notificationService NotificationService
+	storage             storage.Storage
+	config              *config.Config
+	linkKey             []byte
+}
+
+// NewDataExportService 创建个人数据导出服务实例
+func NewDataExportService(
+	exportRepo repositories.DataExportRepository,
+	userRepo repositories.UserRepository,
+	photoRepo repositories.PhotoRepository,
+	interactionRepo repositories.InteractionRepository,
+	matchRepo repositories.MatchRepository,
+	messageRepo repositories.MessageRepository,
+	notificationRepo repositories.NotificationRepository,
+	notificationService NotificationService,
+	storage storage.Storage,
+	config *config.Config,
+) DataExportService {
+	return &dataExportService{
+		exportRepo:          exportRepo,
+		userRepo:            userRepo,
+		photoRepo:           photoRepo,
+		interactionRepo:     interactionRepo,
+		matchRepo:           matchRepo,
+		messageRepo:         messageRepo,
+		notificationRepo:    notificationRepo,
+		notificationService: notificationService,
+		storage:             storage,
+		config:              config,
+		linkKey:             exportLinkKey(config.JWT.Secret),
+	}
+}
+
+// Request 申请导出个人数据
+// 已有处理中或未过期的导出时直接返回，否则创建新任务并在后台处理
+func (s *dataExportService) Request(ctx context.Context, userID string) (*DataExportStatus, error) {
+	latest, err := s.exportRepo.GetLatestByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && s.isActive(latest, time.Now()) {
+		return s.status(latest), nil
+	}
+
+	export := &models.DataExport{
+		UserID: userID,
+		Status: models.DataExportStatusPending,
+	}
+	created, err := s.exportRepo.CreateIfNoneActive(ctx, export)
+	if err != nil {
+		return nil, err
+	}
+	// 并发的申请已经创建了任务，返回该任务
+	if !created {
+		latest, err := s.exportRepo.GetLatestByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil {
+			return nil, ErrExportNotFound
+		}
+		return s.status(latest), nil
+	}
+
+	// 立即在后台处理，不随请求结束而取消，失败时由定时任务重试
+	go func() {
+		if err := s.ProcessPending(context.WithoutCancel(ctx)); err != nil {
+			log.Printf("处理数据导出失败: %v", err)
+		}
+	}()
+	return s.status(export), nil
+}
+
+// ProcessPending 依次处理所有待导出任务
+func (s *dataExportService) ProcessPending(ctx context.Context) error {
+	for {
+		export, err := s.exportRepo.ClaimPending(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if export == nil {
+			return nil
+		}
+
+		if err := s.build(ctx, export); err != nil {
+			log.Printf("生成数据导出 %s 失败: %v", export.ID, err)
+			export.Status = models.DataExportStatusFailed
+			export.Error = err.Error()
+			if err := s.exportRepo.Update(ctx, export); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := s.notificationService.Notify(ctx, export.UserID, models.NotificationTypeSystem, "你的个人数据导出已完成，请在有效期内下载", ""); err != nil {
+			log.Printf("发送通知失败: %v", err)
+		}
+	}
+}
+
+// Cleanup 删除下载链接已过期的文件，并将超时未完成的任务标记为失败
+func (s *dataExportService) Cleanup(ctx context.Context) error {
+	now := time.Now()
+	expired, err := s.exportRepo.ListExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+	for i := range expired {
+		export := &expired[i]
+		if err := os.Remove(export.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("删除导出文件失败: %v", err)
+			continue
+		}
+		export.Status = models.DataExportStatusExpired
+		export.FilePath = ""
+		if err := s.exportRepo.Update(ctx, export); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.exportRepo.FailStale(ctx, now.Add(-s.config.Export.Timeout))
+	return err
+}
+
+// OpenDownload 校验下载链接并返回导出文件路径
+func (s *dataExportService) OpenDownload(ctx context.Context, exportID string, expires int64, signature string) (string, error) {
+	expected := s.sign(exportID, expires)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 || time.Now().Unix() >= expires {
+		return "", ErrExportLinkInvalid
+	}
+
+	export, err := s.exportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		return "", err
+	}
+	if export == nil {
+		return "", ErrExportNotFound
+	}
+	if export.Status != models.DataExportStatusReady {
+		return "", ErrExportLinkInvalid
+	}
+	return export.FilePath, nil
+}
+
+// build 收集用户数据并写入 ZIP，完成后设置下载有效期
+func (s *dataExportService) build(ctx context.Context, export *models.DataExport) error {
+	if err := os.MkdirAll(s.config.Export.Dir, 0o700); err != nil {
+		return err
+	}
+	filePath := filepath.Join(s.config.Export.Dir, export.ID+".zip")
+	if err := s.writeArchive(ctx, export.UserID, filePath); err != nil {
+		os.Remove(filePath)
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.config.Export.LinkTTL)
+	export.Status = models.DataExportStatusReady
+	export.FilePath = filePath
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	return s.exportRepo.Update(ctx, export)
+}
+
+// exportedUser 导出的账号信息，包含接口中不返回的个人数据
+type exportedUser struct {
+	*dto.Profile
+	RegistrationIP string `json:"registrationIp,omitempty"`
+}
+
+// writeArchive 写入导出文件：各类数据为 JSON，照片原图放在 photos 目录下
+func (s *dataExportService) writeArchive(ctx context.Context, userID, filePath string) error {
+	user, err := s.userRepo.GetByID(context.Background(), userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	photos, err := s.photoRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	interactions, err := s.interactionRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	matches, err := s.matchRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	messages, err := s.messageRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	notifications, err := s.notificationRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	entries := []struct {
+		name string
+		data interface{}
+	}{
+		{"user.json", exportedUser{Profile: dto.NewProfile(user), RegistrationIP: user.RegistrationIP}},
+		{"photos.json", photos},
+		{"interactions.json", interactions},
+		{"matches.json", matches},
+		{"messages.json", messages},
+		{"notifications.json", notifications},
+	}
+	for _, entry := range entries {
+		if err := writeJSONEntry(archive, entry.name, entry.data); err != nil {
+			return err
+		}
+	}
+
+	for _, photo := range photos {
+		data, err := s.storage.Read(photo.URL)
+		if err != nil {
+			// 照片文件缺失不影响其他数据导出，photos.json 中仍有记录
+			log.Printf("读取照片 %s 失败: %v", photo.ID, err)
+			continue
+		}
+		w, err := archive.Create("photos/" + path.Base(photo.URL))
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writeJSONEntry 将数据以格式化的 JSON 写入压缩包
+func writeJSONEntry(archive *zip.Writer, name string, data interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// isActive 判断导出任务是否仍在处理或可以下载，此时不需要重新导出
+func (s *dataExportService) isActive(export *models.DataExport, now time.Time) bool {
+	switch export.Status {
+	case models.DataExportStatusPending, models.DataExportStatusProcessing:
+		return true
+	case models.DataExportStatusReady:
+		return export.ExpiresAt != nil && now.Before(*export.ExpiresAt)
+	default:
+		return false
+	}
+}
+
+// status 返回任务状态，就绪时生成与文件同时过期的签名下载链接
+func (s *dataExportService) status(export *models.DataExport) *DataExportStatus {
+	result := &DataExportStatus{DataExport: export}
+	if export.Status == models.DataExportStatusReady && export.ExpiresAt != nil {
+		expires := export.ExpiresAt.Unix()
+		result.DownloadURL = fmt.Sprintf("/api/exports/%s/download?expires=%d&sig=%s", export.ID, expires, s.sign(export.ID, expires))
+	}
+	return result
+}
+
+// exportLinkKey 由服务端密钥派生下载链接的签名密钥，与 JWT 签名使用不同的密钥
+func exportLinkKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("export-link"))
+	return mac.Sum(nil)
+}
+
+// sign 对导出 ID 和过期时间签名
+func (s *dataExportService) sign(exportID string, expires int64) string {
+	mac := hmac.New(sha256.New, s.linkKey)
+	fmt.Fprintf(mac, "%s:%d", exportID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/dto"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+const (
+	defaultFeedSize = 20
+	maxFeedSize     = 50
+)
+
+// FeedItem 推荐列表中的一个用户
+type FeedItem struct {
+	User    *dto.PublicProfile `json:"user"`
+	Boosted bool               `json:"boosted"`
+}
+
+// DiscoveryService 推荐服务接口
+type DiscoveryService interface {
+	Feed(ctx context.Context, userID string, limit int) ([]FeedItem, error)
+}
+
+// discoveryService 推荐服务实现
+type discoveryService struct {
+	discoveryRepo  repositories.DiscoveryRepository
+	userRepo       repositories.UserRepository
+	boostService   BoostService
+	privacyService PrivacyService
+}
+
+// NewDiscoveryService 创建推荐服务实例
+func NewDiscoveryService(discoveryRepo repositories.DiscoveryRepository, userRepo repositories.UserRepository, boostService BoostService, privacyService PrivacyService) DiscoveryService {
+	return &discoveryService{
+		discoveryRepo:  discoveryRepo,
+		userRepo:       userRepo,
+		boostService:   boostService,
+		privacyService: privacyService,
+	}
+}
+
+// Feed 获取推荐列表，同校用户优先，加速中的用户排在同一梯队前面
+// 返回的加速用户计入其本次加速的曝光，候选人信息按其隐私设置隐藏
+func (s *discoveryService) Feed(ctx context.Context, userID string, limit int) ([]FeedItem, error) {
+	if limit < 1 || limit > maxFeedSize {
+		limit = defaultFeedSize
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	candidates, err := s.discoveryRepo.ListCandidates(ctx, userID, user.University, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*models.User, 0, len(candidates))
+	var boosted []string
+	for i := range candidates {
+		users = append(users, &candidates[i].User)
+		if candidates[i].Boosted {
+			boosted = append(boosted, candidates[i].User.ID)
+		}
+	}
+
+	profiles, err := s.privacyService.Present(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]FeedItem, 0, len(candidates))
+	for i := range candidates {
+		items = append(items, FeedItem{User: profiles[i], Boosted: candidates[i].Boosted})
+	}
+
+	if len(boosted) > 0 {
+		s.boostService.RecordImpressions(ctx, boosted)
+	}
+	return items, nil
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// 会员等级，与前端 UserRole 保持一致
+const (
+	TierFree = "FREE"
+	TierVIP  = "VIP"
+)
+
+// 有每日配额的功能
+const (
+	FeatureLike   = "like"
+	FeatureRewind = "rewind"
+	FeatureBoost  = "boost"
+)
+
+// 按等级开放的能力
+const (
+	CapabilitySeeWhoLikedMe = "see_who_liked_me"
+	CapabilityIncognito     = "incognito"
+)
+
+// 表示不限次数
+const unlimited = -1
+
+var ErrUnknownFeature = errors.New("未知的功能")
+
+// QuotaExceededError 每日配额用尽，前端据此引导升级 VIP
+type QuotaExceededError struct {
+	Feature string
+	Tier    string
+	Limit   int
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("今日%s次数已用完", e.Feature)
+}
+
+// CapabilityRequiredError 当前等级没有该能力
+type CapabilityRequiredError struct {
+	Capability string
+	Tier       string
+}
+
+func (e *CapabilityRequiredError) Error() string {
+	return fmt.Sprintf("当前会员等级无法使用%s", e.Capability)
+}
+
+// FeatureQuota 单个功能的配额情况，Limit 为 -1 表示不限
+type FeatureQuota struct {
+	Limit     int `json:"limit"`
+	Used      int `json:"used"`
+	Remaining int `json:"remaining"`
+}
+
+// Entitlements 用户当前的权益
+type Entitlements struct {
+	Tier         string                  `json:"tier"`
+	Quotas       map[string]FeatureQuota `json:"quotas"`
+	Capabilities map[string]bool         `json:"capabilities"`
+	ResetAt      time.Time               `json:"resetAt"`
+}
+
+// EntitlementService 会员权益服务接口
+// 所有与 FREE/VIP 差异相关的判断都应通过该服务完成
+type EntitlementService interface {
+	GetEntitlements(ctx context.Context, userID string) (*Entitlements, error)
+	Remaining(ctx context.Context, userID, feature string) (int, error)
+	Consume(ctx context.Context, userID, feature string) (int, error)
+	Refund(ctx context.Context, userID, feature string) error
+	Can(ctx context.Context, userID, capability string) (bool, error)
+	Require(ctx context.Context, userID, capability string) error
+	Tier(ctx context.Context, userID string) (string, error)
+}
+
+// entitlementService 会员权益服务实现
+type entitlementService struct {
+	userRepo  repositories.UserRepository
+	usageRepo repositories.UsageRepository
+	config    *config.Config
+}
+
+// NewEntitlementService 创建会员权益服务实例
+func NewEntitlementService(userRepo repositories.UserRepository, usageRepo repositories.UsageRepository, config *config.Config) EntitlementService {
+	return &entitlementService{
+		userRepo:  userRepo,
+		usageRepo: usageRepo,
+		config:    config,
+	}
+}
+
+// GetEntitlements 获取用户的等级、各功能剩余次数和能力
+func (s *entitlementService) GetEntitlements(ctx context.Context, userID string) (*Entitlements, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tier, limits := s.tierLimits(user)
+	day, resetAt := s.localDay(user)
+
+	quotas := make(map[string]FeatureQuota)
+	for _, feature := range []string{FeatureLike, FeatureRewind, FeatureBoost} {
+		limit := featureLimit(limits, feature)
+		used, err := s.usageRepo.Get(ctx, userID, feature, day)
+		if err != nil {
+			return nil, err
+		}
+		quotas[feature] = FeatureQuota{
+			Limit:     limit,
+			Used:      used,
+			Remaining: remaining(limit, used),
+		}
+	}
+
+	return &Entitlements{
+		Tier:   tier,
+		Quotas: quotas,
+		Capabilities: map[string]bool{
+			CapabilitySeeWhoLikedMe: limits.SeeWhoLikedMe,
+			CapabilityIncognito:     limits.Incognito,
+		},
+		ResetAt: resetAt,
+	}, nil
+}
+
+// Remaining 查询功能今日剩余次数，不限时返回 -1
+func (s *entitlementService) Remaining(ctx context.Context, userID, feature string) (int, error) {
+	if !isQuotaFeature(feature) {
+		return 0, ErrUnknownFeature
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	_, limits := s.tierLimits(user)
+	limit := featureLimit(limits, feature)
+
+	day, _ := s.localDay(user)
+	used, err := s.usageRepo.Get(ctx, userID, feature, day)
+	if err != nil {
+		return 0, err
+	}
+	return remaining(limit, used), nil
+}
+
+// Consume 消耗一次功能配额，返回剩余次数
+// 配额用尽时返回 *QuotaExceededError
+func (s *entitlementService) Consume(ctx context.Context, userID, feature string) (int, error) {
+	if !isQuotaFeature(feature) {
+		return 0, ErrUnknownFeature
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	tier, limits := s.tierLimits(user)
+	day, resetAt := s.localDay(user)
+	limit := featureLimit(limits, feature)
+
+	exceeded := &QuotaExceededError{Feature: feature, Tier: tier, Limit: limit, ResetAt: resetAt}
+	if limit == 0 {
+		return 0, exceeded
+	}
+
+	// 不限次数时依然计数，便于统计
+	ceiling := limit
+	if limit == unlimited {
+		ceiling = math.MaxInt32
+	}
+	used, ok, err := s.usageRepo.IncrementWithin(ctx, userID, feature, day, ceiling)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, exceeded
+	}
+	return remaining(limit, used), nil
+}
+
+// Refund 返还一次功能配额，用于操作失败或被撤销的情况
+func (s *entitlementService) Refund(ctx context.Context, userID, feature string) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	day, _ := s.localDay(user)
+	return s.usageRepo.Decrement(ctx, userID, feature, day)
+}
+
+// Can 判断用户是否拥有某项能力
+func (s *entitlementService) Can(ctx context.Context, userID, capability string) (bool, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	_, limits := s.tierLimits(user)
+	switch capability {
+	case CapabilitySeeWhoLikedMe:
+		return limits.SeeWhoLikedMe, nil
+	case CapabilityIncognito:
+		return limits.Incognito, nil
+	}
+	return false, ErrUnknownFeature
+}
+
+// Require 要求用户拥有某项能力，否则返回 *CapabilityRequiredError
+func (s *entitlementService) Require(ctx context.Context, userID, capability string) error {
+	ok, err := s.Can(ctx, userID, capability)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		tier, err := s.Tier(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return &CapabilityRequiredError{Capability: capability, Tier: tier}
+	}
+	return nil
+}
+
+// Tier 获取用户的会员等级
+func (s *entitlementService) Tier(ctx context.Context, userID string) (string, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	tier, _ := s.tierLimits(user)
+	return tier, nil
+}
+
+// getUser 查询用户，不存在时返回 ErrUserNotFound
+func (s *entitlementService) getUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// tierLimits 根据 VIP 状态返回等级和对应配额
+func (s *entitlementService) tierLimits(user *models.User) (string, config.TierLimits) {
+	if user.IsVIP {
+		return TierVIP, s.config.Entitlement.VIP
+	}
+	return TierFree, s.config.Entitlement.Free
+}
+
+// localDay 返回用户时区下的当天日期和下一次重置时间（本地零点）
+// 用户可以修改时区，修改频率由 UpdateProfile 限制
+func (s *entitlementService) localDay(user *models.User) (string, time.Time) {
+	loc, err := time.LoadLocation(user.Timezone)
+	if user.Timezone == "" || err != nil {
+		loc, err = time.LoadLocation(s.config.Entitlement.DefaultTimezone)
+		if err != nil {
+			loc = time.UTC
+		}
+	}
+
+	now := time.Now().In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	return now.Format("2006-01-02"), midnight.AddDate(0, 0, 1)
+}
+
+// featureLimit 返回功能在该等级下的每日上限
+func featureLimit(limits config.TierLimits, feature string) int {
+	switch feature {
+	case FeatureLike:
+		return limits.DailyLikes
+	case FeatureRewind:
+		return limits.DailyRewinds
+	case FeatureBoost:
+		return limits.DailyBoosts
+	}
+	return 0
+}
+
+// isQuotaFeature 检查功能是否有每日配额
+func isQuotaFeature(feature string) bool {
+	return feature == FeatureLike || feature == FeatureRewind || feature == FeatureBoost
+}
+
+// remaining 计算剩余次数
+func remaining(limit, used int) int {
+	if limit == unlimited {
+		return unlimited
+	}
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
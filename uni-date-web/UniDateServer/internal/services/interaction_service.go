@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/dto"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/risk"
+)
+
+var (
+	ErrInvalidInteractionType = errors.New("无效的交互类型")
+	ErrCannotSwipeSelf        = errors.New("不能对自己操作")
+	ErrAlreadySwiped          = errors.New("已经操作过该用户")
+	ErrNothingToUndo          = errors.New("没有可撤回的操作")
+	ErrUndoWindowExpired      = errors.New("已超过可撤回的时间")
+	ErrMatchHasMessages       = errors.New("已经开始聊天，无法撤回")
+	ErrMatchedByOther         = errors.New("对方已经喜欢了你，无法撤回")
+)
+
+const (
+	defaultLikesPageSize = 20
+	maxLikesPageSize     = 50
+)
+
+// SwipeResult 滑动结果，双方互相喜欢时 Match 不为空
+type SwipeResult struct {
+	Interaction    *models.Interaction `json:"interaction"`
+	Match          *models.Match       `json:"match,omitempty"`
+	LikesRemaining int                 `json:"likesRemaining"`
+}
+
+// UndoResult 撤回结果
+type UndoResult struct {
+	Interaction      *models.Interaction `json:"interaction"`
+	MatchRemoved     bool                `json:"matchRemoved"`
+	RewindsRemaining int                 `json:"rewindsRemaining"`
+}
+
+// ReceivedLikesPage 收到的喜欢分页结果
+// VIP 可以看到完整资料；免费用户只能看到总数和脱敏预览
+type ReceivedLikesPage struct {
+	Total    int64          `json:"total"`
+	Redacted bool           `json:"redacted"`
+	Items    []ReceivedLike `json:"items"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+}
+
+// ReceivedLike 一条收到的喜欢，User 与 Preview 二选一
+type ReceivedLike struct {
+	User    *dto.PublicProfile `json:"user,omitempty"`
+	Preview *LikePreview       `json:"preview,omitempty"`
+	LikedAt time.Time          `json:"likedAt"`
+}
+
+// LikePreview 脱敏预览，不包含任何可以定位到具体用户的信息
+type LikePreview struct {
+	Initial string `json:"initial"`
+	Blurred bool   `json:"blurred"`
+}
+
+// InteractionService 用户交互（滑动）服务接口
+type InteractionService interface {
+	Swipe(ctx context.Context, fromUserID, toUserID, interactionType string) (*SwipeResult, error)
+	Undo(ctx context.Context, userID string) (*UndoResult, error)
+	ListReceivedLikes(ctx context.Context, userID string, page, pageSize int) (*ReceivedLikesPage, error)
+}
+
+// interactionService 用户交互服务实现
+type interactionService struct {
+	interactionRepo     repositories.InteractionRepository
+	txManager           repositories.TxManager
+	userRepo            repositories.UserRepository
+	blockRepo           repositories.BlockRepository
+	entitlementService  EntitlementService
+	notificationService NotificationService
+	boostService        BoostService
+	riskService         RiskService
+	privacyService      PrivacyService
+	config              *config.Config
+}
+
+// NewInteractionService 创建用户交互服务实例
+func NewInteractionService(
+	interactionRepo repositories.InteractionRepository,
+	txManager repositories.TxManager,
+	userRepo repositories.UserRepository,
+	blockRepo repositories.BlockRepository,
+	entitlementService EntitlementService,
+	notificationService NotificationService,
+	boostService BoostService,
+	riskService RiskService,
+	privacyService PrivacyService,
+	config *config.Config,
+) InteractionService {
+	return &interactionService{
+		interactionRepo:     interactionRepo,
+		txManager:           txManager,
+		userRepo:            userRepo,
+		blockRepo:           blockRepo,
+		entitlementService:  entitlementService,
+		notificationService: notificationService,
+		boostService:        boostService,
+		riskService:         riskService,
+		privacyService:      privacyService,
+		config:              config,
+	}
+}
+
+// Swipe 喜欢或跳过一个用户
+// 喜欢会消耗每日配额，双方互相喜欢时创建匹配并通知双方
+func (s *interactionService) Swipe(ctx context.Context, fromUserID, toUserID, interactionType string) (*SwipeResult, error) {
+	if interactionType != models.InteractionTypeLike && interactionType != models.InteractionTypeDislike {
+		return nil, ErrInvalidInteractionType
+	}
+	if fromUserID == toUserID {
+		return nil, ErrCannotSwipeSelf
+	}
+
+	target, err := s.userRepo.GetByID(ctx, toUserID)
+	if err != nil {
+		return nil, err
+	}
+	// 被封禁的用户对外表现为不存在
+	if target == nil || target.Status == models.UserStatusBanned {
+		return nil, ErrUserNotFound
+	}
+
+	// 存在拉黑关系时对外表现为用户不存在
+	blocked, err := s.blockRepo.IsBlockedEither(ctx, fromUserID, toUserID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ErrUserNotFound
+	}
+
+	result := &SwipeResult{LikesRemaining: unlimited}
+	if interactionType == models.InteractionTypeLike {
+		result.LikesRemaining, err = s.entitlementService.Consume(ctx, fromUserID, FeatureLike)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 写入滑动、创建匹配和匹配通知在同一个事务中完成，双方同时喜欢时由可串行化事务保证只产生一个匹配
+	var notifications []*models.Notification
+	err = s.txManager.WithinTx(ctx, func(tx repositories.Repos) error {
+		result.Interaction, result.Match, notifications = nil, nil, nil
+
+		existing, err := tx.Interactions.GetByUsers(ctx, fromUserID, toUserID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrAlreadySwiped
+		}
+
+		interaction := &models.Interaction{
+			FromUserID: fromUserID,
+			ToUserID:   toUserID,
+			Type:       interactionType,
+		}
+		if err := tx.Interactions.Create(ctx, interaction); err != nil {
+			return err
+		}
+		result.Interaction = interaction
+
+		if interactionType != models.InteractionTypeLike {
+			return nil
+		}
+		result.Match, notifications, err = matchIfMutual(ctx, tx, fromUserID, toUserID)
+		return err
+	})
+	if err != nil {
+		if interactionType == models.InteractionTypeLike {
+			// 请求被取消导致事务失败时同样需要返还配额
+			s.entitlementService.Refund(context.WithoutCancel(ctx), fromUserID, FeatureLike)
+		}
+		return nil, err
+	}
+	for _, notification := range notifications {
+		s.notificationService.Publish(ctx, notification)
+	}
+	riskLevel := s.riskService.Evaluate(ctx, fromUserID, RiskTriggerSwipe)
+
+	if interactionType != models.InteractionTypeLike {
+		return result, nil
+	}
+	s.boostService.RecordLike(ctx, toUserID)
+
+	// 被限制曝光的用户的喜欢不通知对方
+	if result.Match == nil && riskLevel != risk.LevelShadowLimited {
+		s.notify(ctx, toUserID, models.NotificationTypeLike, "有人喜欢了你", result.Interaction.ID)
+	}
+	return result, nil
+}
+
+// Undo 撤回最近一次滑动
+// 匹配归属于促成匹配的那次喜欢，即后喜欢的一方（匹配的 User2ID）：
+// 撤回的喜欢促成了匹配时匹配一并撤销；匹配由对方后来的喜欢促成时说明对方已经回应，不允许撤回。
+// 双方已经聊过天同样不允许撤回
+// 最近一次滑动在事务中重新读取并删除，同时发起的多次撤回只有一次能删除同一条滑动，其余返回 ErrNothingToUndo
+func (s *interactionService) Undo(ctx context.Context, userID string) (*UndoResult, error) {
+	// 先在事务外检查一次，没有可撤回的操作时不消耗撤回次数
+	latest, err := s.interactionRepo.GetLatestByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, ErrNothingToUndo
+	}
+	if time.Since(latest.CreatedAt) > s.config.Interaction.UndoWindow {
+		return nil, ErrUndoWindowExpired
+	}
+
+	rewindsRemaining, err := s.entitlementService.Consume(ctx, userID, FeatureRewind)
+	if err != nil {
+		return nil, err
+	}
+
+	// 撤销匹配、删除滑动和撤回相关通知在同一个事务中完成
+	var undone *models.Interaction
+	var match *models.Match
+	err = s.txManager.WithinTx(ctx, func(tx repositories.Repos) error {
+		undone, match = nil, nil
+
+		latest, err := tx.Interactions.GetLatestByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			return ErrNothingToUndo
+		}
+		if time.Since(latest.CreatedAt) > s.config.Interaction.UndoWindow {
+			return ErrUndoWindowExpired
+		}
+
+		if latest.Type == models.InteractionTypeLike {
+			match, err = tx.Matches.GetByUsers(ctx, userID, latest.ToUserID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if match != nil {
+			if match.User2ID != userID {
+				return ErrMatchedByOther
+			}
+			count, err := tx.Messages.CountByMatch(ctx, match.ID)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrMatchHasMessages
+			}
+			if err := tx.Matches.Delete(ctx, match.ID); err != nil {
+				return err
+			}
+			// 已推送到客户端或移动设备的通知无法收回
+			if err := tx.Notifications.DeleteByRelatedID(ctx, match.ID); err != nil {
+				return err
+			}
+		}
+
+		// 并发的撤回已经删除了这条滑动
+		deleted, err := tx.Interactions.Delete(ctx, latest.ID)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return ErrNothingToUndo
+		}
+		if err := tx.Notifications.DeleteByRelatedID(ctx, latest.ID); err != nil {
+			return err
+		}
+		undone = latest
+		return nil
+	})
+	if err != nil {
+		s.entitlementService.Refund(context.WithoutCancel(ctx), userID, FeatureRewind)
+		return nil, err
+	}
+
+	if undone.Type == models.InteractionTypeLike {
+		// 撤回的喜欢返还每日配额
+		if err := s.entitlementService.Refund(context.WithoutCancel(ctx), userID, FeatureLike); err != nil {
+			log.Printf("返还喜欢次数失败: %v", err)
+		}
+	}
+
+	return &UndoResult{
+		Interaction:      undone,
+		MatchRemoved:     match != nil,
+		RewindsRemaining: rewindsRemaining,
+	}, nil
+}
+
+// ListReceivedLikes 分页获取喜欢了我、但我还没有操作过的人
+func (s *interactionService) ListReceivedLikes(ctx context.Context, userID string, page, pageSize int) (*ReceivedLikesPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxLikesPageSize {
+		pageSize = defaultLikesPageSize
+	}
+
+	canSee, err := s.entitlementService.Can(ctx, userID, CapabilitySeeWhoLikedMe)
+	if err != nil {
+		return nil, err
+	}
+
+	likes, total, err := s.interactionRepo.ListPendingLikes(ctx, userID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReceivedLikesPage{
+		Total:    total,
+		Redacted: !canSee,
+		Items:    make([]ReceivedLike, 0, len(likes)),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	if !canSee {
+		for i := range likes {
+			result.Items = append(result.Items, ReceivedLike{
+				Preview: &LikePreview{
+					Initial: nameInitial(likes[i].User.Name),
+					Blurred: true,
+				},
+				LikedAt: likes[i].LikedAt,
+			})
+		}
+		return result, nil
+	}
+
+	// 完整资料同样按对方的隐私设置隐藏
+	users := make([]*models.User, 0, len(likes))
+	for i := range likes {
+		users = append(users, &likes[i].User)
+	}
+	profiles, err := s.privacyService.Present(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+	for i := range likes {
+		result.Items = append(result.Items, ReceivedLike{User: profiles[i], LikedAt: likes[i].LikedAt})
+	}
+	return result, nil
+}
+
+// matchIfMutual 对方也喜欢自己时在事务中创建匹配，并为双方写入匹配通知
+// 返回的通知需要在事务提交后发布
+func matchIfMutual(ctx context.Context, tx repositories.Repos, fromUserID, toUserID string) (*models.Match, []*models.Notification, error) {
+	reverse, err := tx.Interactions.GetByUsers(ctx, toUserID, fromUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if reverse == nil || reverse.Type != models.InteractionTypeLike {
+		return nil, nil, nil
+	}
+
+	match, err := tx.Matches.GetByUsers(ctx, fromUserID, toUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if match != nil {
+		return match, nil, nil
+	}
+
+	// User2ID 为促成匹配的一方，撤回时据此判断匹配是否由这次喜欢创建
+	match = &models.Match{
+		User1ID:  toUserID,
+		User2ID:  fromUserID,
+		IsActive: true,
+	}
+	if err := tx.Matches.Create(ctx, match); err != nil {
+		return nil, nil, err
+	}
+
+	var notifications []*models.Notification
+	for _, userID := range []string{fromUserID, toUserID} {
+		notification := &models.Notification{
+			UserID:    userID,
+			Type:      models.NotificationTypeMatch,
+			Content:   "你们互相喜欢，开始聊天吧！",
+			RelatedID: match.ID,
+		}
+		if err := tx.Notifications.Create(ctx, notification); err != nil {
+			return nil, nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return match, notifications, nil
+}
+
+// notify 发送通知，失败只记录日志
+func (s *interactionService) notify(ctx context.Context, userID, notificationType, content, relatedID string) {
+	if _, err := s.notificationService.Notify(ctx, userID, notificationType, content, relatedID); err != nil {
+		log.Printf("发送通知失败: %v", err)
+	}
+}
+
+// nameInitial 取名字的第一个字符用于脱敏展示
+func nameInitial(name string) string {
+	r, _ := utf8.DecodeRuneInString(name)
+	if r == utf8.RuneError {
+		return ""
+	}
+	return string(r)
+}
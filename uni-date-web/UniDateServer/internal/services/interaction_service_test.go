@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+)
+
+// undoFixture 撤回只依赖配额、滑动和匹配，其余服务不参与
+type undoFixture struct {
+	t            *testing.T
+	repos        repositories.Set
+	entitlements EntitlementService
+	interactions InteractionService
+	seq          int
+}
+
+func newUndoFixture(t *testing.T) *undoFixture {
+	cfg := &config.Config{
+		Entitlement: config.EntitlementConfig{
+			DefaultTimezone: "UTC",
+			Free:            config.TierLimits{DailyLikes: 20, DailyRewinds: 5},
+		},
+		Interaction: config.InteractionConfig{UndoWindow: time.Minute},
+	}
+	repos := memory.NewSet(memory.NewStore())
+	entitlements := NewEntitlementService(repos.Users, repos.Usage, cfg)
+	return &undoFixture{
+		t:            t,
+		repos:        repos,
+		entitlements: entitlements,
+		interactions: NewInteractionService(repos.Interactions, repos.Tx, repos.Users, repos.Blocks, entitlements, nil, nil, nil, nil, cfg),
+	}
+}
+
+func (f *undoFixture) user() string {
+	f.t.Helper()
+	f.seq++
+	user := &models.User{Name: "user", Account: fmt.Sprintf("user-%d", f.seq), Password: "password", University: "North University", Timezone: "UTC"}
+	if err := f.repos.Users.Create(context.Background(), user); err != nil {
+		f.t.Fatalf("创建用户失败: %v", err)
+	}
+	return user.ID
+}
+
+// like 记录一次喜欢并消耗配额，与 Swipe 的效果一致
+func (f *undoFixture) like(from, to string) {
+	f.t.Helper()
+	if _, err := f.entitlements.Consume(context.Background(), from, FeatureLike); err != nil {
+		f.t.Fatalf("消耗喜欢次数失败: %v", err)
+	}
+	if err := f.repos.Interactions.Create(context.Background(), &models.Interaction{FromUserID: from, ToUserID: to, Type: models.InteractionTypeLike}); err != nil {
+		f.t.Fatalf("创建滑动失败: %v", err)
+	}
+}
+
+func (f *undoFixture) remaining(userID, feature string) int {
+	f.t.Helper()
+	remaining, err := f.entitlements.Remaining(context.Background(), userID, feature)
+	if err != nil {
+		f.t.Fatalf("查询剩余次数失败: %v", err)
+	}
+	return remaining
+}
+
+func TestUndoConcurrentRequestsUndoOnce(t *testing.T) {
+	f := newUndoFixture(t)
+	alice, bob := f.user(), f.user()
+	f.like(alice, bob)
+
+	const requests = 5
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = f.interactions.Undo(context.Background(), alice)
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch err {
+		case nil:
+			succeeded++
+		case ErrNothingToUndo:
+		default:
+			t.Fatalf("意外的错误: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("期望只有一次撤回成功，实际 %d 次", succeeded)
+	}
+	if remaining := f.remaining(alice, FeatureRewind); remaining != 4 {
+		t.Fatalf("只应消耗一次撤回次数，剩余 %d", remaining)
+	}
+	if remaining := f.remaining(alice, FeatureLike); remaining != 20 {
+		t.Fatalf("喜欢次数只应返还一次，剩余 %d", remaining)
+	}
+}
+
+func TestUndoMatchOwnership(t *testing.T) {
+	f := newUndoFixture(t)
+	alice, bob := f.user(), f.user()
+
+	// bob 后喜欢，匹配由 bob 的喜欢促成
+	f.like(alice, bob)
+	f.like(bob, alice)
+	match := &models.Match{User1ID: alice, User2ID: bob, IsActive: true}
+	if err := f.repos.Matches.Create(context.Background(), match); err != nil {
+		t.Fatalf("创建匹配失败: %v", err)
+	}
+
+	if _, err := f.interactions.Undo(context.Background(), alice); err != ErrMatchedByOther {
+		t.Fatalf("对方促成的匹配不能撤回，实际 %v", err)
+	}
+	if existing, _ := f.repos.Matches.GetByID(context.Background(), match.ID); existing == nil {
+		t.Fatalf("撤回失败时不应删除匹配")
+	}
+	if interaction, _ := f.repos.Interactions.GetByUsers(context.Background(), alice, bob); interaction == nil {
+		t.Fatalf("撤回失败时不应删除滑动")
+	}
+	if remaining := f.remaining(alice, FeatureRewind); remaining != 5 {
+		t.Fatalf("撤回失败时应返还撤回次数，剩余 %d", remaining)
+	}
+
+	result, err := f.interactions.Undo(context.Background(), bob)
+	if err != nil {
+		t.Fatalf("撤回失败: %v", err)
+	}
+	if !result.MatchRemoved {
+		t.Fatalf("撤回促成匹配的喜欢应撤销匹配")
+	}
+	if existing, _ := f.repos.Matches.GetByID(context.Background(), match.ID); existing != nil {
+		t.Fatalf("匹配没有被删除")
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/contentfilter"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/risk"
+)
+
+var (
+	ErrMatchNotFound      = errors.New("匹配不存在")
+	ErrMatchInactive      = errors.New("匹配已失效")
+	ErrInvalidContentType = errors.New("无效的消息类型")
+)
+
+// 消息类型
+const (
+	ContentTypeText  = "text"
+	ContentTypeImage = "image"
+	ContentTypeEmoji = "emoji"
+)
+
+const (
+	defaultMessagesPageSize = 30
+	maxMessagesPageSize     = 100
+)
+
+// MessageService 聊天消息服务接口
+type MessageService interface {
+	Send(ctx context.Context, senderID, matchID, content, contentType string) (*models.Message, error)
+	List(ctx context.Context, userID, matchID string, before *time.Time, limit int) ([]models.Message, error)
+}
+
+// messageService 聊天消息服务实现
+type messageService struct {
+	messageRepo         repositories.MessageRepository
+	matchRepo           repositories.MatchRepository
+	userRepo            repositories.UserRepository
+	moderationService   ModerationService
+	riskService         RiskService
+	notificationService NotificationService
+	config              *config.Config
+}
+
+// NewMessageService 创建聊天消息服务实例
+func NewMessageService(
+	messageRepo repositories.MessageRepository,
+	matchRepo repositories.MatchRepository,
+	userRepo repositories.UserRepository,
+	moderationService ModerationService,
+	riskService RiskService,
+	notificationService NotificationService,
+	config *config.Config,
+) MessageService {
+	return &messageService{
+		messageRepo:         messageRepo,
+		matchRepo:           matchRepo,
+		userRepo:            userRepo,
+		moderationService:   moderationService,
+		riskService:         riskService,
+		notificationService: notificationService,
+		config:              config,
+	}
+}
+
+// Send 在匹配中发送消息
+// 文本消息经过内容过滤：命中拒绝规则时返回 *ContentRejectedError，命中审核规则的消息照常发送并进入审核队列
+func (s *messageService) Send(ctx context.Context, senderID, matchID, content, contentType string) (*models.Message, error) {
+	if contentType != ContentTypeText && contentType != ContentTypeImage && contentType != ContentTypeEmoji {
+		return nil, ErrInvalidContentType
+	}
+
+	match, err := s.getMatch(ctx, senderID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if !match.IsActive {
+		return nil, ErrMatchInactive
+	}
+
+	sent, err := s.messageRepo.CountBySender(ctx, matchID, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var screened *contentfilter.Result
+	if contentType == ContentTypeText {
+		screened, err = s.moderationService.ScreenText(ctx, content, contentfilter.Scope{
+			Field:        contentfilter.FieldMessage,
+			EarlyMessage: sent < int64(s.config.ContentFilter.EarlyMessages),
+		})
+		if err != nil {
+			return nil, err
+		}
+		content = screened.Text
+	}
+
+	receiverID := match.User1ID
+	if receiverID == senderID {
+		receiverID = match.User2ID
+	}
+	message := &models.Message{
+		MatchID:     matchID,
+		SenderID:    senderID,
+		ReceiverID:  receiverID,
+		Content:     content,
+		ContentType: contentType,
+	}
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, err
+	}
+
+	if screened != nil && screened.Flagged {
+		s.moderationService.Flag(ctx, &models.ModerationItem{
+			Source:        models.ModerationSourceMessage,
+			SubjectUserID: senderID,
+			RefID:         message.ID,
+			Field:         contentfilter.FieldMessage,
+			Content:       message.Content,
+			Rules:         screened.Rules(),
+		})
+	}
+
+	// 每个匹配的首条消息参与风险评估，被限制曝光的用户发送的消息对方看不到，也不通知
+	var riskLevel string
+	if sent == 0 {
+		riskLevel = s.riskService.Evaluate(ctx, senderID, RiskTriggerMessage)
+	} else if sender, err := s.userRepo.GetByID(ctx, senderID); err == nil && sender != nil {
+		riskLevel = sender.RiskLevel
+	}
+	if riskLevel == risk.LevelShadowLimited {
+		return message, nil
+	}
+
+	if _, err := s.notificationService.Notify(ctx, receiverID, models.NotificationTypeMessage, "你收到了一条新消息", matchID); err != nil {
+		log.Printf("发送通知失败: %v", err)
+	}
+	return message, nil
+}
+
+// List 分页获取匹配中的消息，按时间倒序
+func (s *messageService) List(ctx context.Context, userID, matchID string, before *time.Time, limit int) ([]models.Message, error) {
+	if limit < 1 || limit > maxMessagesPageSize {
+		limit = defaultMessagesPageSize
+	}
+	match, err := s.getMatch(ctx, userID, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 对方被限制曝光时隐藏其消息，本人仍能看到自己发送的消息
+	otherID := match.User1ID
+	if otherID == userID {
+		otherID = match.User2ID
+	}
+	other, err := s.userRepo.GetByID(ctx, otherID)
+	if err != nil {
+		return nil, err
+	}
+	var hiddenSenderID string
+	if other != nil && other.RiskLevel == risk.LevelShadowLimited {
+		hiddenSenderID = otherID
+	}
+	return s.messageRepo.ListByMatch(ctx, matchID, hiddenSenderID, before, limit)
+}
+
+// getMatch 查询匹配，不是匹配双方时按不存在处理
+func (s *messageService) getMatch(ctx context.Context, userID, matchID string) (*models.Match, error) {
+	match, err := s.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil || (match.User1ID != userID && match.User2ID != userID) {
+		return nil, ErrMatchNotFound
+	}
+	return match, nil
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/contentfilter"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+var (
+	ErrAccountActive           = errors.New("账号状态正常，无需申诉")
+	ErrAppealPending           = errors.New("已有待处理的申诉")
+	ErrAppealNotFound          = errors.New("申诉不存在")
+	ErrAppealResolved          = errors.New("申诉已处理")
+	ErrModerationItemNotFound  = errors.New("审核条目不存在")
+	ErrModerationItemResolved  = errors.New("审核条目已处理")
+	ErrInvalidModerationStatus = errors.New("无效的审核结果")
+)
+
+// TokenScopeAppeal 被限制的账号登录时获得的令牌范围，只能用于提交申诉
+const TokenScopeAppeal = "appeal"
+
+// 申诉令牌有效期
+const appealTokenTTL = time.Hour
+
+// AccountRestrictedError 账号被暂停或封禁，可以直接序列化返回给客户端
+type AccountRestrictedError struct {
+	Status string     `json:"status"`
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+func (e *AccountRestrictedError) Error() string {
+	if e.Status == models.UserStatusBanned || e.Until == nil {
+		return "账号已被封禁"
+	}
+	return fmt.Sprintf("账号已被暂停使用至 %s", e.Until.Format(time.RFC3339))
+}
+
+// ContentRejectedError 内容命中拒绝规则，可以直接序列化返回给客户端
+type ContentRejectedError struct {
+	Field string   `json:"field"`
+	Rules []string `json:"rules"`
+}
+
+func (e *ContentRejectedError) Error() string {
+	return "内容包含不允许发布的信息"
+}
+
+// ModerationQueuePage 审核队列分页结果
+type ModerationQueuePage struct {
+	Total    int64                   `json:"total"`
+	Items    []models.ModerationItem `json:"items"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"pageSize"`
+}
+
+// AppealPage 申诉分页结果
+type AppealPage struct {
+	Total    int64           `json:"total"`
+	Items    []models.Appeal `json:"items"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"pageSize"`
+}
+
+// ModerationService 账号处置服务接口
+type ModerationService interface {
+	CheckAccountStatus(ctx context.Context, userID string) error
+	ReinstateExpired(ctx context.Context) error
+	SubmitAppeal(ctx context.Context, meta RequestMeta, userID, message string) (*models.Appeal, error)
+	ListAppeals(ctx context.Context, status string, page, pageSize int) (*AppealPage, error)
+	ResolveAppeal(ctx context.Context, meta RequestMeta, adminID, appealID string, approve bool, note string) (*models.Appeal, error)
+	ScreenText(ctx context.Context, text string, scope contentfilter.Scope) (*contentfilter.Result, error)
+	Flag(ctx context.Context, item *models.ModerationItem)
+	ListQueue(ctx context.Context, status, source string, page, pageSize int) (*ModerationQueuePage, error)
+	ResolveItem(ctx context.Context, meta RequestMeta, adminID, itemID, status, note string) (*models.ModerationItem, error)
+}
+
+// moderationService 账号处置服务实现
+type moderationService struct {
+	userRepo       repositories.UserRepository
+	appealRepo     repositories.AppealRepository
+	moderationRepo repositories.ModerationRepository
+	contentFilter  contentfilter.ContentFilter
+	auditService   AuditService
+}
+
+// NewModerationService 创建账号处置服务实例
+func NewModerationService(
+	userRepo repositories.UserRepository,
+	appealRepo repositories.AppealRepository,
+	moderationRepo repositories.ModerationRepository,
+	contentFilter contentfilter.ContentFilter,
+	auditService AuditService,
+) ModerationService {
+	return &moderationService{
+		userRepo:       userRepo,
+		appealRepo:     appealRepo,
+		moderationRepo: moderationRepo,
+		contentFilter:  contentFilter,
+		auditService:   auditService,
+	}
+}
+
+// CheckAccountStatus 检查账号是否可以正常使用
+// 被暂停或封禁时返回 *AccountRestrictedError；暂停已到期但定时任务尚未处理时视为正常
+func (s *moderationService) CheckAccountStatus(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	return accountStatusError(user, time.Now())
+}
+
+// ReinstateExpired 恢复暂停到期的账号，由定时任务调用
+func (s *moderationService) ReinstateExpired(ctx context.Context) error {
+	ids, err := s.userRepo.ReinstateExpired(context.Background(), time.Now())
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.auditService.Record(ctx, RequestMeta{}, "", models.AuditActionSuspensionExpired, id, nil)
+	}
+	return nil
+}
+
+// SubmitAppeal 被暂停或封禁的用户提交申诉，同一时间只能有一条待处理的申诉
+func (s *moderationService) SubmitAppeal(ctx context.Context, meta RequestMeta, userID, message string) (*models.Appeal, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if accountStatusError(user, time.Now()) == nil {
+		return nil, ErrAccountActive
+	}
+
+	pending, err := s.appealRepo.GetPendingByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, ErrAppealPending
+	}
+
+	appeal := &models.Appeal{
+		UserID:     userID,
+		UserStatus: user.Status,
+		Message:    message,
+		Status:     models.AppealStatusPending,
+	}
+	if err := s.appealRepo.Create(ctx, appeal); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, userID, models.AuditActionAppeal, userID, map[string]string{
+		"appealId": appeal.ID,
+	})
+	return appeal, nil
+}
+
+// ListAppeals 按状态分页查询申诉
+func (s *moderationService) ListAppeals(ctx context.Context, status string, page, pageSize int) (*AppealPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxAdminPageSize {
+		pageSize = defaultAdminPageSize
+	}
+
+	appeals, total, err := s.appealRepo.List(ctx, status, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &AppealPage{
+		Total:    total,
+		Items:    appeals,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// ResolveAppeal 管理员处理申诉，通过时恢复账号
+func (s *moderationService) ResolveAppeal(ctx context.Context, meta RequestMeta, adminID, appealID string, approve bool, note string) (*models.Appeal, error) {
+	appeal, err := s.appealRepo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if appeal.Status != models.AppealStatusPending {
+		return nil, ErrAppealResolved
+	}
+
+	if approve {
+		err := s.userRepo.UpdateColumns(ctx, appeal.UserID, map[string]interface{}{
+			"status":          models.UserStatusActive,
+			"status_reason":   "",
+			"suspended_until": nil,
+		})
+		if err != nil {
+			return nil, err
+		}
+		appeal.Status = models.AppealStatusApproved
+	} else {
+		appeal.Status = models.AppealStatusRejected
+	}
+
+	now := time.Now()
+	appeal.ReviewerID = &adminID
+	appeal.ReviewNote = note
+	appeal.ReviewedAt = &now
+	if err := s.appealRepo.Update(ctx, appeal); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminResolveAppeal, appeal.UserID, map[string]string{
+		"appealId": appeal.ID,
+		"result":   appeal.Status,
+	})
+	return appeal, nil
+}
+
+// ScreenText 按内容过滤规则检查文本，Result.Text 为处理后应保存的文本
+// 命中拒绝规则时返回 *ContentRejectedError；Result.Flagged 为 true 时调用方保存后应通过 Flag 加入审核队列
+func (s *moderationService) ScreenText(ctx context.Context, text string, scope contentfilter.Scope) (*contentfilter.Result, error) {
+	result := s.contentFilter.Check(text, scope)
+	if result.Blocked {
+		return nil, &ContentRejectedError{Field: scope.Field, Rules: result.Rules()}
+	}
+	return result, nil
+}
+
+// Flag 加入人工审核队列，失败只记录日志
+func (s *moderationService) Flag(ctx context.Context, item *models.ModerationItem) {
+	item.Status = models.ModerationStatusPending
+	if err := s.moderationRepo.Create(ctx, item); err != nil {
+		log.Printf("加入审核队列失败: %v", err)
+	}
+}
+
+// ListQueue 按状态和来源分页查询审核队列
+func (s *moderationService) ListQueue(ctx context.Context, status, source string, page, pageSize int) (*ModerationQueuePage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxAdminPageSize {
+		pageSize = defaultAdminPageSize
+	}
+
+	items, total, err := s.moderationRepo.List(ctx, status, source, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ModerationQueuePage{
+		Total:    total,
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// ResolveItem 管理员处理审核条目，对账号的进一步处置通过管理后台的暂停、封禁接口完成
+// 驳回风险评分条目时同时解除曝光限制
+func (s *moderationService) ResolveItem(ctx context.Context, meta RequestMeta, adminID, itemID, status, note string) (*models.ModerationItem, error) {
+	if status != models.ModerationStatusActioned && status != models.ModerationStatusDismissed {
+		return nil, ErrInvalidModerationStatus
+	}
+
+	item, err := s.moderationRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrModerationItemNotFound
+	}
+	if item.Status != models.ModerationStatusPending {
+		return nil, ErrModerationItemResolved
+	}
+
+	now := time.Now()
+	item.Status = status
+	item.ReviewerID = &adminID
+	item.ReviewNote = note
+	item.ReviewedAt = &now
+	if err := s.moderationRepo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+
+	// 风险评分误报时解除限制，之前的事件不再计分
+	if item.Source == models.ModerationSourceRisk && status == models.ModerationStatusDismissed {
+		if err := s.userRepo.UpdateColumns(ctx, item.SubjectUserID, map[string]interface{}{
+			"risk_level":      "",
+			"risk_cleared_at": now,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminResolveModeration, item.SubjectUserID, map[string]string{
+		"itemId": item.ID,
+		"result": status,
+	})
+	return item, nil
+}
+
+// accountStatusError 根据账号状态返回限制错误，可以正常使用时返回 nil
+func accountStatusError(user *models.User, now time.Time) error {
+	switch user.Status {
+	case models.UserStatusBanned:
+		return &AccountRestrictedError{Status: user.Status, Reason: user.StatusReason}
+	case models.UserStatusSuspended:
+		if user.SuspendedUntil == nil || user.SuspendedUntil.After(now) {
+			return &AccountRestrictedError{Status: user.Status, Reason: user.StatusReason, Until: user.SuspendedUntil}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/presence"
+	"github.com/ShijieLu222/uni-date-server/internal/pubsub"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+var (
+	ErrNotificationNotFound    = errors.New("通知不存在")
+	ErrInvalidNotificationType = errors.New("无效的通知类型")
+)
+
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
+// NotificationPage 通知分页结果
+type NotificationPage struct {
+	Items    []models.Notification `json:"items"`
+	Total    int64                 `json:"total"`
+	Unread   int64                 `json:"unread"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"pageSize"`
+}
+
+// NotificationService 通知服务接口
+// 其他服务（匹配、消息等）通过 Notify 发送通知
+type NotificationService interface {
+	Notify(ctx context.Context, userID, notificationType, content, relatedID string) (*models.Notification, error)
+	Publish(ctx context.Context, notification *models.Notification)
+	List(ctx context.Context, userID string, unreadOnly bool, page, pageSize int) (*NotificationPage, error)
+	ListSince(ctx context.Context, userID, lastEventID string) ([]models.Notification, bool, error)
+	Subscribe(ctx context.Context, userID string) (<-chan *models.Notification, func(), error)
+	MarkRead(ctx context.Context, userID string, ids []string) (int64, error)
+	Delete(ctx context.Context, userID, id string) error
+	Retract(ctx context.Context, relatedID string) error
+}
+
+// notificationService 通知服务实现
+type notificationService struct {
+	notificationRepo repositories.NotificationRepository
+	pushService      PushService
+	pubsub           pubsub.PubSub
+	presence         presence.Tracker
+	config           *config.Config
+}
+
+// NewNotificationService 创建通知服务实例，pushService 为空时不推送到移动设备
+func NewNotificationService(notificationRepo repositories.NotificationRepository, pushService PushService, ps pubsub.PubSub, tracker presence.Tracker, config *config.Config) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		pushService:      pushService,
+		pubsub:           ps,
+		presence:         tracker,
+		config:           config,
+	}
+}
+
+// Notify 为用户创建一条通知
+func (s *notificationService) Notify(ctx context.Context, userID, notificationType, content, relatedID string) (*models.Notification, error) {
+	if !isValidNotificationType(notificationType) {
+		return nil, ErrInvalidNotificationType
+	}
+
+	notification := &models.Notification{
+		UserID:    userID,
+		Type:      notificationType,
+		Content:   content,
+		RelatedID: relatedID,
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return nil, err
+	}
+	s.Publish(ctx, notification)
+	return notification, nil
+}
+
+// Publish 将已经落库的通知广播给在线的客户端，不在线时推送到移动设备
+// 在事务中写入的通知需要在提交后调用
+func (s *notificationService) Publish(ctx context.Context, notification *models.Notification) {
+	userID := notification.UserID
+
+	// 广播给在线的客户端，失败不影响通知落库
+	if payload, err := json.Marshal(notification); err == nil {
+		if err := s.pubsub.Publish(notificationChannel(userID), payload); err != nil {
+			log.Printf("发布通知失败: %v", err)
+		}
+	}
+
+	// 用户不在线时推送到移动设备，推送在后台进行，不随请求结束而取消
+	if s.pushService != nil && !s.presence.IsOnline(userID) {
+		go s.pushService.SendNotification(context.WithoutCancel(ctx), notification)
+	}
+}
+
+// List 分页获取用户通知
+func (s *notificationService) List(ctx context.Context, userID string, unreadOnly bool, page, pageSize int) (*NotificationPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultNotificationPageSize
+	}
+	if pageSize > maxNotificationPageSize {
+		pageSize = maxNotificationPageSize
+	}
+
+	items, total, err := s.notificationRepo.ListByUser(ctx, userID, unreadOnly, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	unread := total
+	if !unreadOnly {
+		unread, err = s.notificationRepo.CountUnread(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &NotificationPage{
+		Items:    items,
+		Total:    total,
+		Unread:   unread,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// ListSince 获取 lastEventID 之后的通知，用于 SSE 断线重连补发
+// 错过的通知超过 ReplayLimit 条时不补发，第二个返回值为 true，由客户端重新拉取通知列表
+func (s *notificationService) ListSince(ctx context.Context, userID, lastEventID string) ([]models.Notification, bool, error) {
+	if lastEventID == "" {
+		return nil, false, nil
+	}
+	limit := s.config.Notification.ReplayLimit
+	notifications, err := s.notificationRepo.ListAfter(ctx, userID, lastEventID, limit+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(notifications) > limit {
+		return nil, true, nil
+	}
+	return notifications, false, nil
+}
+
+// Subscribe 订阅用户的实时通知，返回通知通道和取消订阅函数
+// 订阅期间用户视为在线，不会再走移动端推送
+func (s *notificationService) Subscribe(ctx context.Context, userID string) (<-chan *models.Notification, func(), error) {
+	sub, err := s.pubsub.Subscribe(notificationChannel(userID))
+	if err != nil {
+		return nil, nil, err
+	}
+	release := s.presence.Connect(userID)
+
+	notifications := make(chan *models.Notification)
+	done := make(chan struct{})
+	go func() {
+		defer close(notifications)
+		for msg := range sub.Messages() {
+			var notification models.Notification
+			if err := json.Unmarshal(msg.Payload, &notification); err != nil {
+				log.Printf("解析通知消息失败: %v", err)
+				continue
+			}
+			select {
+			case notifications <- &notification:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(done)
+			sub.Close()
+			release()
+		})
+	}
+	return notifications, cancel, nil
+}
+
+// MarkRead 批量标记通知为已读，ids 为空时标记全部
+func (s *notificationService) MarkRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return s.notificationRepo.MarkAllRead(ctx, userID)
+	}
+	return s.notificationRepo.MarkRead(ctx, userID, ids)
+}
+
+// Delete 删除用户的一条通知
+func (s *notificationService) Delete(ctx context.Context, userID, id string) error {
+	affected, err := s.notificationRepo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// Retract 撤回关联到某个对象的通知，用于被撤销的操作（如撤回喜欢）
+// 已推送到客户端或移动设备的通知无法收回
+func (s *notificationService) Retract(ctx context.Context, relatedID string) error {
+	return s.notificationRepo.DeleteByRelatedID(ctx, relatedID)
+}
+
+// notificationChannel 用户通知的发布订阅频道
+func notificationChannel(userID string) string {
+	return "notifications:" + userID
+}
+
+// isValidNotificationType 检查通知类型是否合法
+func isValidNotificationType(notificationType string) bool {
+	switch notificationType {
+	case models.NotificationTypeMatch,
+		models.NotificationTypeMessage,
+		models.NotificationTypeLike,
+		models.NotificationTypeSystem:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/presence"
+	"github.com/ShijieLu222/uni-date-server/internal/pubsub"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+)
+
+func TestListSinceResyncsBeyondReplayLimit(t *testing.T) {
+	repos := memory.NewSet(memory.NewStore())
+	tracker := presence.NewMemoryTracker()
+	defer tracker.Connect("user")()
+	cfg := &config.Config{Notification: config.NotificationConfig{ReplayLimit: 3}}
+	notifications := NewNotificationService(repos.Notifications, nil, pubsub.NewMemoryPubSub(), tracker, cfg)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		notification, err := notifications.Notify(context.Background(), "user", models.NotificationTypeSystem, "hello", "")
+		if err != nil {
+			t.Fatalf("发送通知失败: %v", err)
+		}
+		ids = append(ids, notification.ID)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string
+		resync      bool
+	}{
+		{"首次连接", "", nil, false},
+		{"正好等于上限", ids[1], ids[2:], false},
+		{"超过上限", ids[0], nil, true},
+		{"已是最新", ids[4], nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missed, resync, err := notifications.ListSince(context.Background(), "user", tt.lastEventID)
+			if err != nil {
+				t.Fatalf("查询补发通知失败: %v", err)
+			}
+			if resync != tt.resync {
+				t.Fatalf("期望 resync=%v，实际 %v", tt.resync, resync)
+			}
+			if len(missed) != len(tt.want) {
+				t.Fatalf("期望补发 %d 条，实际 %d 条", len(tt.want), len(missed))
+			}
+			for i := range missed {
+				if missed[i].ID != tt.want[i] {
+					t.Fatalf("第 %d 条期望 %s，实际 %s", i, tt.want[i], missed[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyWithoutPushService(t *testing.T) {
+	repos := memory.NewSet(memory.NewStore())
+	notifications := NewNotificationService(repos.Notifications, nil, pubsub.NewMemoryPubSub(), presence.NewMemoryTracker(), &config.Config{})
+
+	// 用户不在线，没有推送服务时只落库
+	if _, err := notifications.Notify(context.Background(), "offline", models.NotificationTypeSystem, "hello", ""); err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	page, err := notifications.List(context.Background(), "offline", false, 1, 20)
+	if err != nil || page.Total != 1 {
+		t.Fatalf("通知应已落库: %v", err)
+	}
+}
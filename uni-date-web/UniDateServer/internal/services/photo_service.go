@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	_ "image/gif"  // 注册 GIF 解码
+	_ "image/jpeg" // 注册 JPEG 解码
+	_ "image/png"  // 注册 PNG 解码
+	"log"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/imagehash"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/storage"
+)
+
+var (
+	ErrPhotoTooLarge           = errors.New("照片大小超过限制")
+	ErrPhotoDimensionsTooLarge = errors.New("照片尺寸超过限制")
+	ErrUnsupportedImage        = errors.New("不支持的图片格式")
+	ErrPhotoNotFound           = errors.New("照片不存在")
+	ErrBlocklistEntryNotFound  = errors.New("违规图片不存在")
+)
+
+// 照片命中的规则
+const (
+	PhotoRuleDuplicate = "photo_duplicate"
+	PhotoRuleBlocklist = "photo_blocklist"
+)
+
+// 加入违规图片时回查已有照片的数量上限
+const blocklistRescanLimit = 100
+
+// PhotoBlocklistPage 违规图片分页结果
+type PhotoBlocklistPage struct {
+	Total    int64                        `json:"total"`
+	Items    []models.PhotoBlocklistEntry `json:"items"`
+	Page     int                          `json:"page"`
+	PageSize int                          `json:"pageSize"`
+}
+
+// PhotoService 照片服务接口
+type PhotoService interface {
+	Upload(ctx context.Context, userID string, data []byte) (*models.Photo, error)
+	ListBlocklist(ctx context.Context, page, pageSize int) (*PhotoBlocklistPage, error)
+	AddToBlocklist(ctx context.Context, meta RequestMeta, adminID, label string, data []byte, photoID string) (*models.PhotoBlocklistEntry, error)
+	RemoveFromBlocklist(ctx context.Context, meta RequestMeta, adminID, entryID string) error
+}
+
+// photoService 照片服务实现
+type photoService struct {
+	photoRepo         repositories.PhotoRepository
+	moderationService ModerationService
+	auditService      AuditService
+	storage           storage.Storage
+	config            *config.Config
+}
+
+// NewPhotoService 创建照片服务实例
+func NewPhotoService(
+	photoRepo repositories.PhotoRepository,
+	moderationService ModerationService,
+	auditService AuditService,
+	storage storage.Storage,
+	config *config.Config,
+) PhotoService {
+	return &photoService{
+		photoRepo:         photoRepo,
+		moderationService: moderationService,
+		auditService:      auditService,
+		storage:           storage,
+		config:            config,
+	}
+}
+
+// Upload 保存照片并计算感知哈希
+// 与违规图片或其他用户的照片相似时照常保存，同时带相似度进入审核队列
+func (s *photoService) Upload(ctx context.Context, userID string, data []byte) (*models.Photo, error) {
+	img, format, err := s.decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	url, err := s.storage.Save(name+"."+format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	photo := &models.Photo{
+		UserID: userID,
+		URL:    url,
+		Hash:   int64(imagehash.DHash(img)),
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}
+	if err := s.photoRepo.Create(ctx, photo); err != nil {
+		return nil, err
+	}
+
+	s.screen(ctx, photo)
+	return photo, nil
+}
+
+// decode 校验大小和尺寸后解码图片
+// 先只读取图片头中的尺寸，超过限制时不再解码，避免体积很小但声明了超大尺寸的图片耗尽内存
+func (s *photoService) decode(data []byte) (image.Image, string, error) {
+	if int64(len(data)) > s.config.Photo.MaxSize {
+		return nil, "", ErrPhotoTooLarge
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedImage
+	}
+	limits := s.config.Photo
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, "", ErrUnsupportedImage
+	}
+	if cfg.Width > limits.MaxDimension || cfg.Height > limits.MaxDimension || int64(cfg.Width)*int64(cfg.Height) > int64(limits.MaxPixels) {
+		return nil, "", ErrPhotoDimensionsTooLarge
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedImage
+	}
+	return img, format, nil
+}
+
+// screen 将照片与违规图片和其他用户的照片比较，命中时加入审核队列，失败只记录日志
+// 违规图片优先，同一张照片只提交一次
+func (s *photoService) screen(ctx context.Context, photo *models.Photo) {
+	entry, err := s.photoRepo.FindBlocklisted(ctx, photo.Hash, s.config.Photo.BlocklistDistance)
+	if err != nil {
+		log.Printf("比对违规图片失败: %v", err)
+	} else if entry != nil {
+		s.flag(ctx, photo, PhotoRuleBlocklist, entry.ID, entry.Distance)
+		return
+	}
+
+	similar, err := s.photoRepo.ListSimilar(ctx, photo.Hash, photo.UserID, s.config.Photo.MaxDistance, 1)
+	if err != nil {
+		log.Printf("比对重复照片失败: %v", err)
+		return
+	}
+	if len(similar) > 0 {
+		s.flag(ctx, photo, PhotoRuleDuplicate, similar[0].ID, similar[0].Distance)
+	}
+}
+
+// flag 照片加入审核队列，matchedRef 为相似的照片或违规图片
+func (s *photoService) flag(ctx context.Context, photo *models.Photo, rule, matchedRef string, distance int) {
+	similarity := imagehash.Similarity(distance)
+	s.moderationService.Flag(ctx, &models.ModerationItem{
+		Source:        models.ModerationSourcePhoto,
+		SubjectUserID: photo.UserID,
+		RefID:         photo.ID,
+		Field:         "photo",
+		Content:       photo.URL,
+		Rules:         []string{rule},
+		Similarity:    &similarity,
+		MatchedRef:    matchedRef,
+	})
+}
+
+// ListBlocklist 分页查询违规图片
+func (s *photoService) ListBlocklist(ctx context.Context, page, pageSize int) (*PhotoBlocklistPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	entries, total, err := s.photoRepo.ListBlocklist(ctx, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &PhotoBlocklistPage{
+		Total:    total,
+		Items:    entries,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// AddToBlocklist 添加违规图片，来源为上传的图片或已有照片 photoID
+// 添加后回查已上传的相似照片并加入审核队列
+func (s *photoService) AddToBlocklist(ctx context.Context, meta RequestMeta, adminID, label string, data []byte, photoID string) (*models.PhotoBlocklistEntry, error) {
+	var hash int64
+	if photoID != "" {
+		photo, err := s.photoRepo.GetByID(ctx, photoID)
+		if err != nil {
+			return nil, err
+		}
+		if photo == nil {
+			return nil, ErrPhotoNotFound
+		}
+		hash = photo.Hash
+	} else {
+		img, _, err := s.decode(data)
+		if err != nil {
+			return nil, err
+		}
+		hash = int64(imagehash.DHash(img))
+	}
+
+	entry := &models.PhotoBlocklistEntry{
+		Hash:    hash,
+		Label:   label,
+		AddedBy: adminID,
+	}
+	if err := s.photoRepo.CreateBlocklistEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminBlocklistPhoto, "", map[string]string{
+		"entryId": entry.ID,
+		"label":   label,
+		"photoId": photoID,
+	})
+
+	matches, err := s.photoRepo.ListSimilar(ctx, hash, "", s.config.Photo.BlocklistDistance, blocklistRescanLimit)
+	if err != nil {
+		log.Printf("回查违规图片失败: %v", err)
+		return entry, nil
+	}
+	for i := range matches {
+		s.flag(ctx, &matches[i].Photo, PhotoRuleBlocklist, entry.ID, matches[i].Distance)
+	}
+	if len(matches) > 0 {
+		log.Printf("违规图片 %s 命中 %d 张已上传照片", entry.ID, len(matches))
+	}
+	return entry, nil
+}
+
+// RemoveFromBlocklist 删除违规图片，已提交的审核条目不受影响
+func (s *photoService) RemoveFromBlocklist(ctx context.Context, meta RequestMeta, adminID, entryID string) error {
+	deleted, err := s.photoRepo.DeleteBlocklistEntry(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBlocklistEntryNotFound
+	}
+	s.auditService.Record(ctx, meta, adminID, models.AuditActionAdminUnblocklistPhoto, "", map[string]string{
+		"entryId": entryID,
+	})
+	return nil
+}
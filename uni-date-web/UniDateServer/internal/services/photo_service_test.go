@@ -0,0 +1,67 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+)
+
+// encodePNG 生成指定尺寸的 PNG
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("生成图片失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// forgePNG 修改 PNG 头中声明的尺寸，像素数据保持不变
+func forgePNG(t *testing.T, width, height uint32) []byte {
+	data := encodePNG(t, 1, 1)
+	// 8 字节签名之后是 IHDR：4 字节长度、4 字节类型、13 字节数据、4 字节校验
+	ihdr := data[12 : 12+4+13]
+	binary.BigEndian.PutUint32(ihdr[4:8], width)
+	binary.BigEndian.PutUint32(ihdr[8:12], height)
+	binary.BigEndian.PutUint32(data[12+4+13:], crc32.ChecksumIEEE(ihdr))
+	return data
+}
+
+func TestPhotoDecodeLimits(t *testing.T) {
+	s := &photoService{config: &config.Config{Photo: config.PhotoConfig{
+		MaxSize:      1 << 20,
+		MaxDimension: 1000,
+		MaxPixels:    500 * 500,
+	}}}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"正常图片", encodePNG(t, 100, 50), nil},
+		{"宽度正好等于上限", encodePNG(t, 1000, 10), nil},
+		{"宽度超过上限", encodePNG(t, 1001, 10), ErrPhotoDimensionsTooLarge},
+		{"高度超过上限", encodePNG(t, 10, 1001), ErrPhotoDimensionsTooLarge},
+		{"总像素超过上限", encodePNG(t, 600, 600), ErrPhotoDimensionsTooLarge},
+		{"小文件声明超大尺寸", forgePNG(t, 50000, 50000), ErrPhotoDimensionsTooLarge},
+		{"文件超过大小限制", make([]byte, 1<<20+1), ErrPhotoTooLarge},
+		{"不是图片", []byte("not an image"), ErrUnsupportedImage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, _, err := s.decode(tt.data)
+			if err != tt.err {
+				t.Fatalf("期望 %v，实际 %v", tt.err, err)
+			}
+			if err == nil && img == nil {
+				t.Fatalf("解码成功时应返回图片")
+			}
+		})
+	}
+}
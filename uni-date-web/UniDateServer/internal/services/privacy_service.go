@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/dto"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/presence"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// UpdatePrivacyRequest 修改隐私设置请求，未传的字段保持不变
+type UpdatePrivacyRequest struct {
+	HideAge        *bool `json:"hideAge"`
+	HideUniversity *bool `json:"hideUniversity"`
+	Incognito      *bool `json:"incognito"`
+	HideLastActive *bool `json:"hideLastActive"`
+	Discoverable   *bool `json:"discoverable"`
+}
+
+// PrivacyService 隐私设置服务接口
+type PrivacyService interface {
+	GetSettings(ctx context.Context, userID string) (*models.PrivacySettings, error)
+	UpdateSettings(ctx context.Context, userID string, req UpdatePrivacyRequest) (*models.PrivacySettings, error)
+	Present(ctx context.Context, users []*models.User) ([]*dto.PublicProfile, error)
+}
+
+// privacyService 隐私设置服务实现
+type privacyService struct {
+	privacyRepo        repositories.PrivacyRepository
+	userRepo           repositories.UserRepository
+	entitlementService EntitlementService
+	tracker            presence.Tracker
+}
+
+// NewPrivacyService 创建隐私设置服务实例
+func NewPrivacyService(privacyRepo repositories.PrivacyRepository, userRepo repositories.UserRepository, entitlementService EntitlementService, tracker presence.Tracker) PrivacyService {
+	return &privacyService{
+		privacyRepo:        privacyRepo,
+		userRepo:           userRepo,
+		entitlementService: entitlementService,
+		tracker:            tracker,
+	}
+}
+
+// GetSettings 获取用户隐私设置，从未修改过时返回默认设置
+func (s *privacyService) GetSettings(ctx context.Context, userID string) (*models.PrivacySettings, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	settings, err := s.privacyRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = models.DefaultPrivacySettings(userID)
+	}
+	return settings, nil
+}
+
+// UpdateSettings 修改隐私设置，开启隐身模式需要 VIP
+// VIP 到期后已开启的隐身模式保留但不再生效，续费后自动恢复
+func (s *privacyService) UpdateSettings(ctx context.Context, userID string, req UpdatePrivacyRequest) (*models.PrivacySettings, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Incognito != nil && *req.Incognito && !settings.Incognito {
+		if err := s.entitlementService.Require(ctx, userID, CapabilityIncognito); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.HideAge != nil {
+		settings.HideAge = *req.HideAge
+	}
+	if req.HideUniversity != nil {
+		settings.HideUniversity = *req.HideUniversity
+	}
+	if req.Incognito != nil {
+		settings.Incognito = *req.Incognito
+	}
+	if req.HideLastActive != nil {
+		settings.HideLastActive = *req.HideLastActive
+	}
+	if req.Discoverable != nil {
+		settings.Discoverable = *req.Discoverable
+	}
+
+	if err := s.privacyRepo.Save(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Present 按各自的隐私设置生成展示给其他用户的公开资料，顺序与 users 一致
+func (s *privacyService) Present(ctx context.Context, users []*models.User) ([]*dto.PublicProfile, error) {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+
+	list, err := s.privacyRepo.ListByUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	settingsByUser := make(map[string]*models.PrivacySettings, len(list))
+	for i := range list {
+		settingsByUser[list[i].UserID] = &list[i]
+	}
+
+	now := time.Now()
+	profiles := make([]*dto.PublicProfile, 0, len(users))
+	for _, user := range users {
+		settings, ok := settingsByUser[user.ID]
+		if !ok {
+			settings = models.DefaultPrivacySettings(user.ID)
+		}
+
+		var lastActive *time.Time
+		if !settings.HideLastActive {
+			if seen, ok := s.tracker.LastSeen(user.ID); ok {
+				lastActive = &seen
+			}
+		}
+		profiles = append(profiles, dto.NewPublicProfile(user, settings, lastActive, now))
+	}
+	return profiles, nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShijieLu222/uni-date-server/internal/dto"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+// ProfileService 公开资料服务接口
+type ProfileService interface {
+	GetPublicProfile(ctx context.Context, viewerID, userID string) (*dto.PublicProfile, error)
+}
+
+// profileService 公开资料服务实现
+type profileService struct {
+	userRepo       repositories.UserRepository
+	matchRepo      repositories.MatchRepository
+	discoveryRepo  repositories.DiscoveryRepository
+	privacyService PrivacyService
+}
+
+// NewProfileService 创建公开资料服务实例
+func NewProfileService(userRepo repositories.UserRepository, matchRepo repositories.MatchRepository, discoveryRepo repositories.DiscoveryRepository, privacyService PrivacyService) ProfileService {
+	return &profileService{
+		userRepo:       userRepo,
+		matchRepo:      matchRepo,
+		discoveryRepo:  discoveryRepo,
+		privacyService: privacyService,
+	}
+}
+
+// GetPublicProfile 查看其他用户的公开资料
+// 只有与对方匹配中，或对方会出现在查看者的推荐中时才可以查看，否则按用户不存在处理，不暴露账号是否存在
+func (s *profileService) GetPublicProfile(ctx context.Context, viewerID, userID string) (*dto.PublicProfile, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if viewerID != userID {
+		visible, err := s.canView(ctx, viewerID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			return nil, ErrUserNotFound
+		}
+	}
+
+	profiles, err := s.privacyService.Present(ctx, []*models.User{user})
+	if err != nil {
+		return nil, err
+	}
+	return profiles[0], nil
+}
+
+// canView 判断查看者能否看到对方的资料
+func (s *profileService) canView(ctx context.Context, viewerID, userID string) (bool, error) {
+	match, err := s.matchRepo.GetByUsers(ctx, viewerID, userID)
+	if err != nil {
+		return false, err
+	}
+	if match != nil && match.IsActive {
+		return true, nil
+	}
+	return s.discoveryRepo.IsDiscoverable(ctx, viewerID, userID)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/push"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+var (
+	ErrInvalidPlatform = errors.New("不支持的设备平台")
+	ErrDeviceNotFound  = errors.New("设备不存在")
+)
+
+// 各类通知的推送标题
+var pushTitles = map[string]string{
+	models.NotificationTypeMatch:   "新的匹配",
+	models.NotificationTypeMessage: "新消息",
+	models.NotificationTypeLike:    "有人喜欢了你",
+	models.NotificationTypeSystem:  "系统通知",
+}
+
+// PushService 移动端推送服务接口
+type PushService interface {
+	RegisterDevice(ctx context.Context, userID, token, platform string) (*models.Device, error)
+	UnregisterDevice(ctx context.Context, userID, token string) error
+	GetPreference(ctx context.Context, userID string) (*models.PushPreference, error)
+	UpdatePreference(ctx context.Context, preference *models.PushPreference) error
+	SendNotification(ctx context.Context, notification *models.Notification)
+}
+
+// pushService 推送服务实现
+type pushService struct {
+	pushRepo  repositories.PushRepository
+	providers map[string]push.PushProvider // 平台 -> 推送提供方
+}
+
+// NewPushService 创建推送服务实例，未配置提供方的平台不会推送
+func NewPushService(pushRepo repositories.PushRepository, providers map[string]push.PushProvider) PushService {
+	return &pushService{
+		pushRepo:  pushRepo,
+		providers: providers,
+	}
+}
+
+// RegisterDevice 登记推送设备
+func (s *pushService) RegisterDevice(ctx context.Context, userID, token, platform string) (*models.Device, error) {
+	if platform != push.PlatformIOS && platform != push.PlatformAndroid {
+		return nil, ErrInvalidPlatform
+	}
+
+	device := &models.Device{
+		UserID:   userID,
+		Token:    token,
+		Platform: platform,
+	}
+	if err := s.pushRepo.UpsertDevice(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// UnregisterDevice 注销推送设备
+func (s *pushService) UnregisterDevice(ctx context.Context, userID, token string) error {
+	affected, err := s.pushRepo.DeleteDevice(ctx, userID, token)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// GetPreference 获取推送偏好，没有记录时返回默认值（全部推送）
+func (s *pushService) GetPreference(ctx context.Context, userID string) (*models.PushPreference, error) {
+	preference, err := s.pushRepo.GetPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		preference = &models.PushPreference{UserID: userID}
+	}
+	return preference, nil
+}
+
+// UpdatePreference 更新推送偏好
+func (s *pushService) UpdatePreference(ctx context.Context, preference *models.PushPreference) error {
+	return s.pushRepo.SavePreference(ctx, preference)
+}
+
+// SendNotification 将通知推送到用户的所有设备
+// 推送失败只记录日志，失效的令牌会被自动清理
+func (s *pushService) SendNotification(ctx context.Context, notification *models.Notification) {
+	preference, err := s.GetPreference(ctx, notification.UserID)
+	if err != nil {
+		log.Printf("获取推送偏好失败: %v", err)
+		return
+	}
+	if preference.IsMuted(notification.Type) {
+		return
+	}
+
+	devices, err := s.pushRepo.ListDevicesByUser(ctx, notification.UserID)
+	if err != nil {
+		log.Printf("获取推送设备失败: %v", err)
+		return
+	}
+
+	message := &push.Message{
+		Title: pushTitles[notification.Type],
+		Body:  notification.Content,
+		Data: map[string]string{
+			"notificationId": notification.ID,
+			"type":           notification.Type,
+			"relatedId":      notification.RelatedID,
+		},
+	}
+
+	for _, device := range devices {
+		provider, ok := s.providers[device.Platform]
+		if !ok {
+			continue
+		}
+
+		err := provider.Send(device.Token, message)
+		if errors.Is(err, push.ErrInvalidToken) {
+			if err := s.pushRepo.DeleteDeviceByToken(ctx, device.Token); err != nil {
+				log.Printf("清理失效设备令牌失败: %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			log.Printf("推送失败 (%s): %v", device.Platform, err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/presence"
+	"github.com/ShijieLu222/uni-date-server/internal/pubsub"
+	"github.com/ShijieLu222/uni-date-server/internal/push"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+)
+
+// pushFixture 使用内存存储和记录型推送组装的推送环境
+type pushFixture struct {
+	t             *testing.T
+	repos         repositories.Set
+	ios, android  *push.RecordingProvider
+	tracker       presence.Tracker
+	pushService   PushService
+	notifications NotificationService
+}
+
+func newPushFixture(t *testing.T) *pushFixture {
+	repos := memory.NewSet(memory.NewStore())
+	ios, android := push.NewRecordingProvider(), push.NewRecordingProvider()
+	tracker := presence.NewMemoryTracker()
+	pushService := NewPushService(repos.Push, map[string]push.PushProvider{
+		push.PlatformIOS:     ios,
+		push.PlatformAndroid: android,
+	})
+	return &pushFixture{
+		t:             t,
+		repos:         repos,
+		ios:           ios,
+		android:       android,
+		tracker:       tracker,
+		pushService:   pushService,
+		notifications: NewNotificationService(repos.Notifications, pushService, pubsub.NewMemoryPubSub(), tracker, &config.Config{}),
+	}
+}
+
+// register 为用户登记一台设备
+func (f *pushFixture) register(userID, token, platform string) {
+	f.t.Helper()
+	if _, err := f.pushService.RegisterDevice(context.Background(), userID, token, platform); err != nil {
+		f.t.Fatalf("登记设备失败: %v", err)
+	}
+}
+
+// tokens 返回提供方已推送的令牌
+func tokens(provider *push.RecordingProvider) []string {
+	var result []string
+	for _, sent := range provider.Sent() {
+		result = append(result, sent.Token)
+	}
+	return result
+}
+
+// waitSent 等待提供方收到 n 条推送，离线推送在后台执行
+func waitSent(t *testing.T, provider *push.RecordingProvider, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(provider.Sent()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("期望 %d 条推送，实际 %v", n, tokens(provider))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNotifyPushesOfflineUserToAllDevices(t *testing.T) {
+	f := newPushFixture(t)
+	f.register("offline", "ios-1", push.PlatformIOS)
+	f.register("offline", "ios-2", push.PlatformIOS)
+	f.register("offline", "android-1", push.PlatformAndroid)
+	f.register("other", "ios-other", push.PlatformIOS)
+
+	notification, err := f.notifications.Notify(context.Background(), "offline", models.NotificationTypeMatch, "你有新的匹配", "match-1")
+	if err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	waitSent(t, f.ios, 2)
+	waitSent(t, f.android, 1)
+
+	if got := tokens(f.ios); len(got) != 2 || !sameTokens(got, []string{"ios-1", "ios-2"}) {
+		t.Fatalf("iOS 期望推送到 ios-1 和 ios-2，实际 %v", got)
+	}
+	sent := f.android.Sent()[0]
+	if sent.Token != "android-1" || sent.Message.Title != pushTitles[models.NotificationTypeMatch] || sent.Message.Body != "你有新的匹配" {
+		t.Fatalf("推送内容错误: %+v", sent)
+	}
+	if sent.Message.Data["notificationId"] != notification.ID || sent.Message.Data["relatedId"] != "match-1" {
+		t.Fatalf("推送数据错误: %v", sent.Message.Data)
+	}
+}
+
+func TestNotifySkipsPushForOnlineUser(t *testing.T) {
+	f := newPushFixture(t)
+	f.register("online", "ios-1", push.PlatformIOS)
+	disconnect := f.tracker.Connect("online")
+
+	if _, err := f.notifications.Notify(context.Background(), "online", models.NotificationTypeMessage, "hi", ""); err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	// 在线时不会启动推送，无需等待
+	if got := tokens(f.ios); len(got) != 0 {
+		t.Fatalf("在线用户不应推送，实际 %v", got)
+	}
+
+	disconnect()
+	if _, err := f.notifications.Notify(context.Background(), "online", models.NotificationTypeMessage, "hi", ""); err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	waitSent(t, f.ios, 1)
+}
+
+func TestSendNotificationRespectsMutedTypes(t *testing.T) {
+	f := newPushFixture(t)
+	f.register("user", "ios-1", push.PlatformIOS)
+	err := f.pushService.UpdatePreference(context.Background(), &models.PushPreference{UserID: "user", MuteLike: true, MuteSystem: true})
+	if err != nil {
+		t.Fatalf("更新推送偏好失败: %v", err)
+	}
+
+	tests := []struct {
+		notificationType string
+		pushed           bool
+	}{
+		{models.NotificationTypeMatch, true},
+		{models.NotificationTypeMessage, true},
+		{models.NotificationTypeLike, false},
+		{models.NotificationTypeSystem, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.notificationType, func(t *testing.T) {
+			f.ios.Reset()
+			f.pushService.SendNotification(context.Background(), &models.Notification{UserID: "user", Type: tt.notificationType, Content: "content"})
+			if pushed := len(f.ios.Sent()) == 1; pushed != tt.pushed {
+				t.Fatalf("期望推送 %v，实际 %v", tt.pushed, tokens(f.ios))
+			}
+		})
+	}
+}
+
+func TestSendNotificationPrunesInvalidTokens(t *testing.T) {
+	f := newPushFixture(t)
+	f.register("user", "ios-stale", push.PlatformIOS)
+	f.register("user", "ios-fresh", push.PlatformIOS)
+	f.register("user", "android-stale", push.PlatformAndroid)
+	f.ios.MarkInvalid("ios-stale")
+	f.android.MarkInvalid("android-stale")
+
+	f.pushService.SendNotification(context.Background(), &models.Notification{UserID: "user", Type: models.NotificationTypeMessage, Content: "hi"})
+
+	if got := tokens(f.ios); !sameTokens(got, []string{"ios-fresh"}) {
+		t.Fatalf("期望只推送到 ios-fresh，实际 %v", got)
+	}
+	devices, err := f.repos.Push.ListDevicesByUser(context.Background(), "user")
+	if err != nil {
+		t.Fatalf("查询设备失败: %v", err)
+	}
+	if len(devices) != 1 || devices[0].Token != "ios-fresh" {
+		t.Fatalf("失效的令牌应被清理，剩余 %v", devices)
+	}
+
+	// 清理后再次推送不会再尝试失效的令牌
+	f.ios.Reset()
+	f.pushService.SendNotification(context.Background(), &models.Notification{UserID: "user", Type: models.NotificationTypeMessage, Content: "hi"})
+	if got := tokens(f.ios); !sameTokens(got, []string{"ios-fresh"}) {
+		t.Fatalf("期望只推送到 ios-fresh，实际 %v", got)
+	}
+}
+
+// sameTokens 判断两组令牌是否相同，不区分顺序
+func sameTokens(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[string]int)
+	for _, token := range a {
+		count[token]++
+	}
+	for _, token := range b {
+		count[token]--
+		if count[token] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+var (
+	ErrCannotReportSelf = errors.New("不能举报自己")
+)
+
+// ReportService 举报服务接口
+type ReportService interface {
+	Report(ctx context.Context, meta RequestMeta, reporterID, reportedID, reason, detail string) (*models.Report, error)
+}
+
+// reportService 举报服务实现
+type reportService struct {
+	reportRepo        repositories.ReportRepository
+	userRepo          repositories.UserRepository
+	moderationService ModerationService
+	auditService      AuditService
+}
+
+// NewReportService 创建举报服务实例
+func NewReportService(reportRepo repositories.ReportRepository, userRepo repositories.UserRepository, moderationService ModerationService, auditService AuditService) ReportService {
+	return &reportService{
+		reportRepo:        reportRepo,
+		userRepo:          userRepo,
+		moderationService: moderationService,
+		auditService:      auditService,
+	}
+}
+
+// Report 举报用户，举报同时进入人工审核队列
+func (s *reportService) Report(ctx context.Context, meta RequestMeta, reporterID, reportedID, reason, detail string) (*models.Report, error) {
+	if reporterID == reportedID {
+		return nil, ErrCannotReportSelf
+	}
+
+	target, err := s.userRepo.GetByID(ctx, reportedID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrUserNotFound
+	}
+
+	report := &models.Report{
+		ReporterID: &reporterID,
+		ReportedID: reportedID,
+		Reason:     reason,
+		Detail:     detail,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, meta, reporterID, models.AuditActionReport, reportedID, map[string]string{
+		"reason":   reason,
+		"reportId": report.ID,
+	})
+	s.moderationService.Flag(ctx, &models.ModerationItem{
+		Source:        models.ModerationSourceReport,
+		SubjectUserID: reportedID,
+		RefID:         report.ID,
+		Content:       detail,
+		Rules:         []string{reason},
+	})
+	return report, nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+	"github.com/ShijieLu222/uni-date-server/internal/risk"
+)
+
+// 触发风险评估的事件
+const (
+	RiskTriggerRegistration = "registration"
+	RiskTriggerMessage      = "message"
+	RiskTriggerSwipe        = "swipe"
+	RiskTriggerProfile      = "profile"
+)
+
+// RiskService 风险评分服务接口
+type RiskService interface {
+	Assess(ctx context.Context, userID string) (*risk.Assessment, error)
+	Evaluate(ctx context.Context, userID, trigger string) string
+}
+
+// riskService 风险评分服务实现
+type riskService struct {
+	riskRepo          repositories.RiskRepository
+	userRepo          repositories.UserRepository
+	moderationService ModerationService
+	auditService      AuditService
+	engine            *risk.Engine
+	config            *config.Config
+}
+
+// NewRiskService 创建风险评分服务实例
+func NewRiskService(
+	riskRepo repositories.RiskRepository,
+	userRepo repositories.UserRepository,
+	moderationService ModerationService,
+	auditService AuditService,
+	engine *risk.Engine,
+	config *config.Config,
+) RiskService {
+	return &riskService{
+		riskRepo:          riskRepo,
+		userRepo:          userRepo,
+		moderationService: moderationService,
+		auditService:      auditService,
+		engine:            engine,
+		config:            config,
+	}
+}
+
+// Assess 评估用户当前的风险，不修改账号
+func (s *riskService) Assess(ctx context.Context, userID string) (*risk.Assessment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return s.assess(ctx, user, time.Now())
+}
+
+// Evaluate 在用户行为发生后评估风险，风险等级升高时自动处置：
+// 达到审核分数进入人工审核队列，达到限制分数同时限制曝光
+// 返回评估后账号的风险等级；评估失败只记录日志并返回原等级，不影响触发评估的操作
+func (s *riskService) Evaluate(ctx context.Context, userID, trigger string) string {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		log.Printf("风险评估失败: 查询用户 %s: %v", userID, err)
+		return risk.LevelNone
+	}
+
+	assessment, err := s.assess(ctx, user, time.Now())
+	if err != nil {
+		log.Printf("风险评估失败: %v", err)
+		return user.RiskLevel
+	}
+
+	columns := map[string]interface{}{"risk_score": assessment.Score}
+	escalated := risk.Escalates(user.RiskLevel, assessment.Level)
+	if escalated {
+		columns["risk_level"] = assessment.Level
+	}
+	if err := s.userRepo.UpdateColumns(ctx, user.ID, columns); err != nil {
+		log.Printf("保存风险评分失败: %v", err)
+		return user.RiskLevel
+	}
+	if !escalated {
+		return user.RiskLevel
+	}
+
+	details := make([]string, 0, len(assessment.Signals))
+	for _, signal := range assessment.Signals {
+		details = append(details, signal.Detail)
+	}
+	s.moderationService.Flag(ctx, &models.ModerationItem{
+		Source:        models.ModerationSourceRisk,
+		SubjectUserID: user.ID,
+		Field:         trigger,
+		Content:       strings.Join(details, "；"),
+		Rules:         assessment.Rules(),
+	})
+	s.auditService.Record(ctx, RequestMeta{}, "", models.AuditActionRiskEscalated, user.ID, map[string]string{
+		"level":   assessment.Level,
+		"score":   strconv.Itoa(assessment.Score),
+		"trigger": trigger,
+	})
+	return assessment.Level
+}
+
+// assess 加载评估时间窗口内的事件并评分，管理员排除风险之前的事件不计入
+func (s *riskService) assess(ctx context.Context, user *models.User, now time.Time) (*risk.Assessment, error) {
+	since := now.Add(-s.engine.Lookback())
+	if user.RiskClearedAt != nil && user.RiskClearedAt.After(since) {
+		since = *user.RiskClearedAt
+	}
+
+	snapshot := &risk.Snapshot{Now: now}
+	if user.RegistrationIP != "" {
+		registrations, err := s.riskRepo.ListRegistrationTimes(ctx, user.RegistrationIP, since)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Registrations = registrations
+	}
+
+	firstMessages, err := s.riskRepo.ListFirstMessages(ctx, user.ID, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range firstMessages {
+		snapshot.FirstMessages = append(snapshot.FirstMessages, risk.Message{
+			MatchID: message.MatchID,
+			Content: message.Content,
+			At:      message.CreatedAt,
+		})
+	}
+
+	if snapshot.Swipes, err = s.riskRepo.ListSwipeTimes(ctx, user.ID, since); err != nil {
+		return nil, err
+	}
+	// 重复照片不限时间，管理员排除过风险后视为已确认
+	if user.RiskClearedAt == nil {
+		if snapshot.DuplicatePhotos, err = s.riskRepo.ListDuplicatePhotos(ctx, user.ID, user.Photos, s.config.Photo.MaxDistance); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.engine.Assess(snapshot), nil
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/payment"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories"
+)
+
+var (
+	ErrInvalidPlan          = errors.New("无效的订阅套餐")
+	ErrSubscriptionNotFound = errors.New("没有有效的订阅")
+	ErrAlreadySubscribed    = errors.New("已有有效的订阅")
+)
+
+// SubscriptionService VIP 订阅服务接口
+type SubscriptionService interface {
+	Checkout(ctx context.Context, userID, plan string) (*payment.CheckoutSession, error)
+	GetCurrent(ctx context.Context, userID string) (*models.Subscription, error)
+	Cancel(ctx context.Context, userID string) (*models.Subscription, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	ExpireSubscriptions(ctx context.Context) error
+}
+
+// subscriptionService 订阅服务实现
+type subscriptionService struct {
+	subscriptionRepo    repositories.SubscriptionRepository
+	paymentProvider     payment.PaymentProvider
+	notificationService NotificationService
+	boostService        BoostService
+}
+
+// NewSubscriptionService 创建订阅服务实例
+func NewSubscriptionService(subscriptionRepo repositories.SubscriptionRepository, paymentProvider payment.PaymentProvider, notificationService NotificationService, boostService BoostService) SubscriptionService {
+	return &subscriptionService{
+		subscriptionRepo:    subscriptionRepo,
+		paymentProvider:     paymentProvider,
+		notificationService: notificationService,
+		boostService:        boostService,
+	}
+}
+
+// Checkout 创建支付会话
+func (s *subscriptionService) Checkout(ctx context.Context, userID, plan string) (*payment.CheckoutSession, error) {
+	if !isValidPlan(plan) {
+		return nil, ErrInvalidPlan
+	}
+
+	current, err := s.subscriptionRepo.GetCurrentByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// 终身会员无需再购买；自动续费中的订阅需先取消才能更换套餐
+	if current != nil && (current.EndDate == nil || !current.CancelAtPeriodEnd) {
+		return nil, ErrAlreadySubscribed
+	}
+
+	return s.paymentProvider.CreateCheckout(userID, plan)
+}
+
+// GetCurrent 获取用户当前有效的订阅
+func (s *subscriptionService) GetCurrent(ctx context.Context, userID string) (*models.Subscription, error) {
+	subscription, err := s.subscriptionRepo.GetCurrentByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+// Cancel 取消自动续费，VIP 权益保留到当前周期结束
+func (s *subscriptionService) Cancel(ctx context.Context, userID string) (*models.Subscription, error) {
+	subscription, err := s.subscriptionRepo.GetCurrentByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil || subscription.EndDate == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if subscription.CancelAtPeriodEnd {
+		return subscription, nil
+	}
+
+	if err := s.paymentProvider.CancelAtPeriodEnd(subscription.ProviderRef); err != nil {
+		return nil, err
+	}
+
+	subscription.CancelAtPeriodEnd = true
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// HandleWebhook 处理支付平台回调
+// 支付平台可能重复投递同一事件，各分支都需要保证幂等
+func (s *subscriptionService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.paymentProvider.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case payment.EventCheckoutCompleted:
+		// 资料加速与订阅共用支付回调
+		if event.Plan == models.BoostProduct {
+			return s.boostService.Credit(ctx, event)
+		}
+		return s.activate(ctx, event)
+	case payment.EventSubscriptionRenewed:
+		return s.renew(ctx, event)
+	case payment.EventSubscriptionEnded:
+		return s.end(ctx, event)
+	}
+	return nil
+}
+
+// ExpireSubscriptions 将到期的订阅标记为过期并撤销对应用户的 VIP，由定时任务调用
+func (s *subscriptionService) ExpireSubscriptions(ctx context.Context) error {
+	expired, err := s.subscriptionRepo.ExpireDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	revoked, err := s.subscriptionRepo.SyncAllVIP(ctx)
+	if err != nil {
+		return err
+	}
+	if expired > 0 || revoked > 0 {
+		log.Printf("订阅过期检查: %d 个订阅过期, %d 个用户取消VIP", expired, revoked)
+	}
+	return nil
+}
+
+// activate 首次支付成功，创建订阅并开通 VIP
+func (s *subscriptionService) activate(ctx context.Context, event *payment.WebhookEvent) error {
+	existing, err := s.subscriptionRepo.GetByProviderRef(ctx, event.ProviderRef)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+	if event.UserID == "" || !isValidPlan(event.Plan) {
+		return ErrInvalidPlan
+	}
+
+	now := time.Now()
+	subscription := &models.Subscription{
+		UserID:        event.UserID,
+		Plan:          event.Plan,
+		Status:        models.SubscriptionStatusActive,
+		StartDate:     now,
+		EndDate:       planEndDate(event.Plan, now),
+		PaymentMethod: event.PaymentMethod,
+		Amount:        event.Amount,
+		Currency:      event.Currency,
+		ProviderRef:   event.ProviderRef,
+	}
+	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+		return err
+	}
+	if err := s.subscriptionRepo.SyncUserVIP(ctx, event.UserID); err != nil {
+		return err
+	}
+
+	if _, err := s.notificationService.Notify(ctx, event.UserID, models.NotificationTypeSystem, "VIP 已开通，感谢你的支持！", ""); err != nil {
+		log.Printf("发送VIP开通通知失败: %v", err)
+	}
+	return nil
+}
+
+// renew 自动续费成功，延长订阅到期时间
+func (s *subscriptionService) renew(ctx context.Context, event *payment.WebhookEvent) error {
+	subscription, err := s.subscriptionRepo.GetByProviderRef(ctx, event.ProviderRef)
+	if err != nil {
+		return err
+	}
+	if subscription == nil || event.PeriodEnd == nil {
+		return nil
+	}
+	if subscription.EndDate != nil && !event.PeriodEnd.After(*subscription.EndDate) {
+		return nil
+	}
+
+	subscription.EndDate = event.PeriodEnd
+	subscription.Status = models.SubscriptionStatusActive
+	subscription.Amount = event.Amount
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return err
+	}
+	return s.subscriptionRepo.SyncUserVIP(ctx, subscription.UserID)
+}
+
+// end 订阅在支付平台终止，立即失效
+func (s *subscriptionService) end(ctx context.Context, event *payment.WebhookEvent) error {
+	subscription, err := s.subscriptionRepo.GetByProviderRef(ctx, event.ProviderRef)
+	if err != nil {
+		return err
+	}
+	if subscription == nil || subscription.Status != models.SubscriptionStatusActive {
+		return nil
+	}
+
+	now := time.Now()
+	subscription.Status = models.SubscriptionStatusCanceled
+	if subscription.EndDate == nil || subscription.EndDate.After(now) {
+		subscription.EndDate = &now
+	}
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return err
+	}
+	return s.subscriptionRepo.SyncUserVIP(ctx, subscription.UserID)
+}
+
+// planEndDate 计算套餐的到期时间，终身套餐返回 nil
+// 自动续费的实际到期时间以续费回调为准
+func planEndDate(plan string, start time.Time) *time.Time {
+	var end time.Time
+	switch plan {
+	case models.SubscriptionPlanMonthly:
+		end = start.AddDate(0, 1, 0)
+	case models.SubscriptionPlanYearly:
+		end = start.AddDate(1, 0, 0)
+	default:
+		return nil
+	}
+	return &end
+}
+
+// isValidPlan 检查套餐是否合法
+func isValidPlan(plan string) bool {
+	switch plan {
+	case models.SubscriptionPlanMonthly,
+		models.SubscriptionPlanYearly,
+		models.SubscriptionPlanLifetime:
+		return true
+	}
+	return false
+}
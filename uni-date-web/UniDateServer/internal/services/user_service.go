@@ -1,11 +1,16 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"log"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/contentfilter"
 	"github.com/ShijieLu222/uni-date-server/internal/models"
 	"github.com/ShijieLu222/uni-date-server/internal/repositories"
 	"github.com/golang-jwt/jwt/v4"
@@ -15,34 +20,69 @@ var (
 	ErrInvalidCredentials = errors.New("无效的用户名或密码")
 	ErrAccountExists      = errors.New("账号已存在")
 	ErrUserNotFound       = errors.New("用户不存在")
+	ErrWrongPassword      = errors.New("原密码错误")
+	ErrProfileModified    = errors.New("资料已在其他设备上修改，请刷新后重试")
 )
 
-// UserService 用户服务接口
+// UpdateProfileRequest 修改资料请求，只包含用户可以自行修改的字段，未传的字段保持不变
+type UpdateProfileRequest struct {
+	Name       *string   `json:"name"`
+	Avatar     *string   `json:"avatar"`
+	Birthdate  *string   `json:"birthdate"` // YYYY-MM-DD
+	Gender     *string   `json:"gender"`
+	University *string   `json:"university"`
+	Major      *string   `json:"major"`
+	Photos     *[]string `json:"photos"`
+	Interests  *[]string `json:"interests"`
+	Timezone   *string   `json:"timezone"`
+}
+
+// ProfileFieldError 资料字段校验失败
+type ProfileFieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *ProfileFieldError) Error() string {
+	return e.Message
+}
+
+// UserService 用户服务接口，ctx 为请求的上下文，请求取消或超时后数据库查询随之中止
 type UserService interface {
-	Register(user *models.User) (string, error)
-	Login(account, password string) (string, *models.User, error)
-	GetUserByID(id string) (*models.User, error)
-	UpdateUserProfile(user *models.User) error
+	Register(ctx context.Context, meta RequestMeta, user *models.User) (string, error)
+	Login(ctx context.Context, meta RequestMeta, account, password string) (string, *models.User, error)
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	UpdateProfile(ctx context.Context, meta RequestMeta, userID string, version int, req UpdateProfileRequest) (*models.User, error)
+	ChangePassword(ctx context.Context, meta RequestMeta, userID, oldPassword, newPassword string) error
+	DeleteAccount(ctx context.Context, meta RequestMeta, userID, password string) (time.Time, error)
 }
 
 // userService 用户服务实现
 type userService struct {
-	userRepo repositories.UserRepository
-	config   *config.Config
+	userRepo          repositories.UserRepository
+	photoRepo         repositories.PhotoRepository
+	moderationService ModerationService
+	riskService       RiskService
+	auditService      AuditService
+	config            *config.Config
 }
 
 // NewUserService 创建用户服务实例
-func NewUserService(userRepo repositories.UserRepository, config *config.Config) UserService {
+func NewUserService(userRepo repositories.UserRepository, photoRepo repositories.PhotoRepository, moderationService ModerationService, riskService RiskService, auditService AuditService, config *config.Config) UserService {
 	return &userService{
-		userRepo: userRepo,
-		config:   config,
+		userRepo:          userRepo,
+		photoRepo:         photoRepo,
+		moderationService: moderationService,
+		riskService:       riskService,
+		auditService:      auditService,
+		config:            config,
 	}
 }
 
-// Register 用户注册
-func (s *userService) Register(user *models.User) (string, error) {
+// Register 用户注册，记录注册 IP 并进行风险评估
+func (s *userService) Register(ctx context.Context, meta RequestMeta, user *models.User) (string, error) {
 	// 检查用户是否存在
-	exists, err := s.userRepo.CheckAccountExists(user.Account)
+	exists, err := s.userRepo.CheckAccountExists(ctx, user.Account)
 	if err != nil {
 		return "", err
 	}
@@ -50,10 +90,19 @@ func (s *userService) Register(user *models.User) (string, error) {
 		return "", ErrAccountExists
 	}
 
+	// 检查昵称和专业
+	flagged, err := s.screenProfile(ctx, user)
+	if err != nil {
+		return "", err
+	}
+
 	// 创建用户
-	if err := s.userRepo.Create(user); err != nil {
+	user.RegistrationIP = meta.IP
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return "", err
 	}
+	s.flagProfile(ctx, user.ID, flagged)
+	s.riskService.Evaluate(ctx, user.ID, RiskTriggerRegistration)
 
 	// 生成JWT令牌
 	token, err := s.generateToken(user.ID)
@@ -64,29 +113,59 @@ func (s *userService) Register(user *models.User) (string, error) {
 	return token, nil
 }
 
-// Login 用户登录
-func (s *userService) Login(account, password string) (string, *models.User, error) {
-	// 查找用户
-	user, err := s.userRepo.GetByAccount(account)
+// Login 用户登录，成功和失败都会写入审计记录
+// 账号被限制时返回 *AccountRestrictedError，同时返回的令牌只能用于提交申诉
+func (s *userService) Login(ctx context.Context, meta RequestMeta, account, password string) (string, *models.User, error) {
+	// 查找用户，注销宽限期内的账号也可以登录，登录成功即撤销注销
+	user, err := s.userRepo.GetByAccount(ctx, account)
 	if err != nil {
 		return "", nil, err
 	}
 	if user == nil {
+		user, err = s.getPendingDeletion(ctx, account)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if user == nil {
+		s.auditService.Record(ctx, meta, "", models.AuditActionLoginFailure, "", map[string]string{
+			"account": account,
+			"reason":  "unknown_account",
+		})
 		return "", nil, ErrInvalidCredentials
 	}
 
-	// 添加调试日志
-	log.Printf("尝试登录 - 账号: %s", account)
-	log.Printf("数据库中的密码: %s", user.Password)
-	log.Printf("输入的密码: %s", password)
-
 	// 简单的明文密码比较
 	if user.Password != password {
-		log.Printf("密码不匹配，明文比较失败")
+		s.auditService.Record(ctx, meta, "", models.AuditActionLoginFailure, user.ID, map[string]string{
+			"account": account,
+			"reason":  "wrong_password",
+		})
 		return "", nil, ErrInvalidCredentials
 	}
 
-	log.Printf("密码验证成功!")
+	// 被暂停或封禁的账号只发放申诉令牌，与错误一起返回
+	if restricted := accountStatusError(user, time.Now()); restricted != nil {
+		s.auditService.Record(ctx, meta, "", models.AuditActionLoginFailure, user.ID, map[string]string{
+			"account": account,
+			"reason":  "account_" + user.Status,
+		})
+		appealToken, err := s.signToken(user.ID, TokenScopeAppeal, appealTokenTTL)
+		if err != nil {
+			return "", nil, err
+		}
+		return appealToken, nil, restricted
+	}
+
+	if user.DeletedAt.Valid {
+		if err := s.userRepo.Restore(ctx, user.ID); err != nil {
+			return "", nil, err
+		}
+		user.PurgeAfter = nil
+		s.auditService.Record(ctx, meta, user.ID, models.AuditActionDeletionCancel, user.ID, nil)
+	}
+
+	s.auditService.Record(ctx, meta, user.ID, models.AuditActionLoginSuccess, user.ID, nil)
 
 	// 生成令牌
 	token, err := s.generateToken(user.ID)
@@ -101,8 +180,8 @@ func (s *userService) Login(account, password string) (string, *models.User, err
 }
 
 // GetUserByID 获取用户信息
-func (s *userService) GetUserByID(id string) (*models.User, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -116,29 +195,322 @@ func (s *userService) GetUserByID(id string) (*models.User, error) {
 	return user, nil
 }
 
-// UpdateUserProfile 更新用户信息
-func (s *userService) UpdateUserProfile(user *models.User) error {
-	existingUser, err := s.userRepo.GetByID(user.ID)
+// UpdateProfile 校验并修改资料，只更新请求中传入的字段，返回修改后的资料
+// version 为客户端读取时的资料版本号，为 0 时不校验；资料已被修改时返回 ErrProfileModified
+func (s *userService) UpdateProfile(ctx context.Context, meta RequestMeta, userID string, version int, req UpdateProfileRequest) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if version != 0 && user.Version != version {
+		return nil, ErrProfileModified
+	}
+	now := time.Now()
+	if err := s.validateProfile(&req, now); err != nil {
+		return nil, err
+	}
+	if err := s.checkPhotoOwnership(ctx, userID, &req); err != nil {
+		return nil, err
+	}
+	if req.Timezone != nil && *req.Timezone != user.Timezone {
+		if err := s.checkTimezoneChange(user, now); err != nil {
+			return nil, err
+		}
+	}
+
+	previousPhotos := user.Photos
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Major != nil {
+		user.Major = *req.Major
+	}
+	flagged, err := s.screenProfile(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	if req.Name != nil {
+		values["name"] = user.Name
+	}
+	if req.Major != nil {
+		values["major"] = user.Major
+	}
+	if req.Avatar != nil {
+		user.Avatar = *req.Avatar
+		values["avatar"] = user.Avatar
+	}
+	if req.Birthdate != nil {
+		user.Birthdate = *req.Birthdate
+		values["birthdate"] = user.Birthdate
+	}
+	if req.Gender != nil {
+		user.Gender = *req.Gender
+		values["gender"] = user.Gender
+	}
+	if req.University != nil {
+		user.University = *req.University
+		values["university"] = user.University
+	}
+	if req.Photos != nil {
+		user.Photos = *req.Photos
+		values["photos"] = user.Photos
+	}
+	if req.Interests != nil {
+		user.Interests = *req.Interests
+		values["interests"] = user.Interests
+	}
+	if req.Timezone != nil && *req.Timezone != user.Timezone {
+		user.Timezone = *req.Timezone
+		user.TimezoneChangedAt = &now
+		values["timezone"] = user.Timezone
+		values["timezone_changed_at"] = now
+	}
+	if len(values) == 0 {
+		return user, nil
+	}
+
+	// 读取之后被其他请求修改时同样视为版本冲突
+	if err := s.userRepo.Update(ctx, user, values); err != nil {
+		if errors.Is(err, repositories.ErrStaleVersion) {
+			return nil, ErrProfileModified
+		}
+		return nil, err
+	}
+	s.flagProfile(ctx, userID, flagged)
+	if !slices.Equal(user.Photos, previousPhotos) {
+		s.riskService.Evaluate(ctx, userID, RiskTriggerProfile)
+	}
+	s.auditService.Record(ctx, meta, userID, models.AuditActionProfileUpdate, userID, nil)
+	return s.GetUserByID(ctx, userID)
+}
+
+// validateProfile 校验并规范化请求中传入的字段，失败时返回 *ProfileFieldError
+func (s *userService) validateProfile(req *UpdateProfileRequest, now time.Time) error {
+	rules := s.config.Profile
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		length := utf8.RuneCountInString(name)
+		if length < rules.NameMinLength || length > rules.NameMaxLength {
+			return &ProfileFieldError{Field: "name", Message: fmt.Sprintf("昵称长度需要在%d到%d个字符之间", rules.NameMinLength, rules.NameMaxLength)}
+		}
+		req.Name = &name
+	}
+
+	if req.Gender != nil {
+		switch *req.Gender {
+		case models.GenderMale, models.GenderFemale, models.GenderOther:
+		default:
+			return &ProfileFieldError{Field: "gender", Message: "无效的性别"}
+		}
+	}
+
+	if req.Birthdate != nil {
+		born, err := time.Parse("2006-01-02", *req.Birthdate)
+		if err != nil {
+			return &ProfileFieldError{Field: "birthdate", Message: "生日格式应为 YYYY-MM-DD"}
+		}
+		if born.After(now.AddDate(-rules.MinAge, 0, 0)) {
+			return &ProfileFieldError{Field: "birthdate", Message: fmt.Sprintf("需要年满%d周岁", rules.MinAge)}
+		}
+		if born.Before(now.AddDate(-rules.MaxAge, 0, 0)) {
+			return &ProfileFieldError{Field: "birthdate", Message: "生日超出有效范围"}
+		}
+	}
+
+	if req.University != nil {
+		university := strings.TrimSpace(*req.University)
+		if university == "" {
+			return &ProfileFieldError{Field: "university", Message: "学校不能为空"}
+		}
+		req.University = &university
+	}
+
+	if req.Major != nil && *req.Major != "" && len(rules.Majors) > 0 && !slices.Contains(rules.Majors, *req.Major) {
+		return &ProfileFieldError{Field: "major", Message: "不支持的专业"}
+	}
+
+	if req.Interests != nil {
+		if len(*req.Interests) > rules.MaxInterests {
+			return &ProfileFieldError{Field: "interests", Message: fmt.Sprintf("兴趣标签最多%d个", rules.MaxInterests)}
+		}
+		for _, interest := range *req.Interests {
+			if strings.TrimSpace(interest) == "" {
+				return &ProfileFieldError{Field: "interests", Message: "兴趣标签不能为空"}
+			}
+		}
+	}
+
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" {
+			return &ProfileFieldError{Field: "timezone", Message: "无效的时区"}
+		}
+	}
+	return nil
+}
+
+// checkPhotoOwnership 头像和照片只能使用本人通过 POST /user/photos 上传的照片
+// 其他地址没有计算感知哈希，会绕过违规图片和重复照片的检查
+func (s *userService) checkPhotoOwnership(ctx context.Context, userID string, req *UpdateProfileRequest) error {
+	checkAvatar := req.Avatar != nil && *req.Avatar != ""
+	if !checkAvatar && req.Photos == nil {
+		return nil
+	}
+
+	photos, err := s.photoRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	owned := make(map[string]bool, len(photos))
+	for _, photo := range photos {
+		owned[photo.URL] = true
+	}
+
+	if checkAvatar && !owned[*req.Avatar] {
+		return &ProfileFieldError{Field: "avatar", Message: "头像需要先通过照片上传接口上传"}
+	}
+	if req.Photos != nil {
+		for _, url := range *req.Photos {
+			if !owned[url] {
+				return &ProfileFieldError{Field: "photos", Message: "照片需要先通过照片上传接口上传"}
+			}
+		}
+	}
+	return nil
+}
+
+// checkTimezoneChange 限制修改时区的频率
+// 每日配额按用户时区的零点重置，不限制时可以通过来回切换时区在一天内多次重置配额
+func (s *userService) checkTimezoneChange(user *models.User, now time.Time) error {
+	if user.TimezoneChangedAt == nil {
+		return nil
+	}
+	next := user.TimezoneChangedAt.Add(s.config.Entitlement.TimezoneChangeInterval)
+	if now.Before(next) {
+		if loc, err := time.LoadLocation(user.Timezone); err == nil {
+			next = next.In(loc)
+		}
+		return &ProfileFieldError{Field: "timezone", Message: fmt.Sprintf("时区修改过于频繁，请在%s之后再试", next.Format("2006-01-02 15:04"))}
+	}
+	return nil
+}
+
+// ChangePassword 校验原密码后修改密码
+func (s *userService) ChangePassword(ctx context.Context, meta RequestMeta, userID, oldPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return err
 	}
-	if existingUser == nil {
+	if user == nil {
 		return ErrUserNotFound
 	}
+	if user.Password != oldPassword {
+		return ErrWrongPassword
+	}
 
-	// 更新不允许修改的字段
-	user.Password = existingUser.Password
-	user.Account = existingUser.Account
+	if err := s.userRepo.UpdateColumns(ctx, userID, map[string]interface{}{"password": newPassword}); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, meta, userID, models.AuditActionPasswordChange, userID, nil)
+	return nil
+}
+
+// DeleteAccount 校验密码后注销账号，返回彻底清除数据的时间
+// 账号立即对其他用户不可见，宽限期内重新登录可以撤销
+func (s *userService) DeleteAccount(ctx context.Context, meta RequestMeta, userID, password string) (time.Time, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user == nil {
+		return time.Time{}, ErrUserNotFound
+	}
+	if user.Password != password {
+		return time.Time{}, ErrWrongPassword
+	}
+
+	purgeAfter := time.Now().Add(s.config.Account.DeletionGracePeriod)
+	if err := s.userRepo.ScheduleDeletion(ctx, userID, purgeAfter); err != nil {
+		return time.Time{}, err
+	}
+	s.auditService.Record(ctx, meta, userID, models.AuditActionDeletionRequest, userID, map[string]string{
+		"purgeAfter": purgeAfter.Format(time.RFC3339),
+	})
+	return purgeAfter, nil
+}
+
+// getPendingDeletion 查询处于注销宽限期内的账号，管理员删除的账号不能通过登录恢复
+func (s *userService) getPendingDeletion(ctx context.Context, account string) (*models.User, error) {
+	user, err := s.userRepo.GetByAccountWithDeleted(ctx, account)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	if !user.DeletedAt.Valid || user.PurgeAfter == nil || !time.Now().Before(*user.PurgeAfter) {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// screenProfile 检查昵称和专业，命中 mask 规则的内容直接替换
+// 命中拒绝规则时返回 *ContentRejectedError，需要人工审核的字段在保存后交给 flagProfile
+func (s *userService) screenProfile(ctx context.Context, user *models.User) ([]*models.ModerationItem, error) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{contentfilter.FieldName, &user.Name},
+		{contentfilter.FieldMajor, &user.Major},
+	}
 
-	return s.userRepo.Update(user)
+	var flagged []*models.ModerationItem
+	for _, field := range fields {
+		if *field.value == "" {
+			continue
+		}
+		result, err := s.moderationService.ScreenText(ctx, *field.value, contentfilter.Scope{Field: field.name})
+		if err != nil {
+			return nil, err
+		}
+		*field.value = result.Text
+		if result.Flagged {
+			flagged = append(flagged, &models.ModerationItem{
+				Source:  models.ModerationSourceProfile,
+				Field:   field.name,
+				Content: result.Text,
+				Rules:   result.Rules(),
+			})
+		}
+	}
+	return flagged, nil
+}
+
+// flagProfile 将需要人工审核的资料字段加入审核队列
+func (s *userService) flagProfile(ctx context.Context, userID string, items []*models.ModerationItem) {
+	for _, item := range items {
+		item.SubjectUserID = userID
+		s.moderationService.Flag(ctx, item)
+	}
 }
 
 // generateToken 生成JWT令牌
 func (s *userService) generateToken(userID string) (string, error) {
+	return s.signToken(userID, "", s.config.JWT.ExpiresIn)
+}
+
+// signToken 签发JWT令牌，scope 非空时令牌只能用于对应范围的接口
+func (s *userService) signToken(userID, scope string, ttl time.Duration) (string, error) {
 	// 设置JWT声明
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(s.config.JWT.ExpiresIn).Unix(),
+		"exp":     time.Now().Add(ttl).Unix(),
+	}
+	if scope != "" {
+		claims["scope"] = scope
 	}
 
 	// 创建令牌
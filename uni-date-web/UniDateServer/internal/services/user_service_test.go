@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ShijieLu222/uni-date-server/config"
+	"github.com/ShijieLu222/uni-date-server/internal/models"
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/memory"
+)
+
+func TestCheckTimezoneChange(t *testing.T) {
+	s := &userService{config: &config.Config{Entitlement: config.EntitlementConfig{TimezoneChangeInterval: 7 * 24 * time.Hour}}}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	changedAt := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+
+	tests := []struct {
+		name      string
+		changedAt *time.Time
+		allowed   bool
+	}{
+		{"从未修改过", nil, true},
+		{"刚刚修改过", changedAt(time.Hour), false},
+		{"间隔内", changedAt(7*24*time.Hour - time.Second), false},
+		{"正好达到间隔", changedAt(7 * 24 * time.Hour), true},
+		{"超过间隔", changedAt(30 * 24 * time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{Timezone: "Asia/Shanghai", TimezoneChangedAt: tt.changedAt}
+			err := s.checkTimezoneChange(user, now)
+			if (err == nil) != tt.allowed {
+				t.Fatalf("期望允许 %v，实际 %v", tt.allowed, err)
+			}
+			if fieldErr, ok := err.(*ProfileFieldError); err != nil && (!ok || fieldErr.Field != "timezone") {
+				t.Fatalf("应返回 timezone 字段错误，实际 %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckPhotoOwnership(t *testing.T) {
+	repos := memory.NewSet(memory.NewStore())
+	for _, photo := range []models.Photo{
+		{UserID: "owner", URL: "/uploads/a.jpg"},
+		{UserID: "owner", URL: "/uploads/b.jpg"},
+		{UserID: "other", URL: "/uploads/c.jpg"},
+	} {
+		if err := repos.Photos.Create(context.Background(), &photo); err != nil {
+			t.Fatalf("保存照片失败: %v", err)
+		}
+	}
+	s := &userService{photoRepo: repos.Photos}
+
+	str := func(s string) *string { return &s }
+	list := func(urls ...string) *[]string { return &urls }
+
+	tests := []struct {
+		name  string
+		req   UpdateProfileRequest
+		field string // 为空表示允许
+	}{
+		{"不修改照片", UpdateProfileRequest{Name: str("name")}, ""},
+		{"本人上传的头像", UpdateProfileRequest{Avatar: str("/uploads/a.jpg")}, ""},
+		{"清空头像", UpdateProfileRequest{Avatar: str("")}, ""},
+		{"别人的照片作为头像", UpdateProfileRequest{Avatar: str("/uploads/c.jpg")}, "avatar"},
+		{"外部地址作为头像", UpdateProfileRequest{Avatar: str("https://example.com/a.jpg")}, "avatar"},
+		{"本人上传的照片", UpdateProfileRequest{Photos: list("/uploads/a.jpg", "/uploads/b.jpg")}, ""},
+		{"清空照片", UpdateProfileRequest{Photos: list()}, ""},
+		{"包含别人的照片", UpdateProfileRequest{Photos: list("/uploads/a.jpg", "/uploads/c.jpg")}, "photos"},
+		{"包含外部地址", UpdateProfileRequest{Photos: list("https://example.com/a.jpg")}, "photos"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkPhotoOwnership(context.Background(), "owner", &tt.req)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("期望允许，实际 %v", err)
+				}
+				return
+			}
+			fieldErr, ok := err.(*ProfileFieldError)
+			if !ok || fieldErr.Field != tt.field {
+				t.Fatalf("期望 %s 字段错误，实际 %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	s := &userService{config: &config.Config{Profile: config.ProfileConfig{
+		NameMinLength: 2,
+		NameMaxLength: 6,
+		MinAge:        18,
+		MaxAge:        100,
+		MaxInterests:  2,
+		Majors:        []string{"计算机", "数学"},
+	}}}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	str := func(s string) *string { return &s }
+	list := func(items ...string) *[]string { return &items }
+
+	tests := []struct {
+		name  string
+		req   UpdateProfileRequest
+		field string // 为空表示通过
+	}{
+		{"空请求", UpdateProfileRequest{}, ""},
+		{"昵称", UpdateProfileRequest{Name: str("小明")}, ""},
+		{"昵称按字符计数", UpdateProfileRequest{Name: str("六个中文字符")}, ""},
+		{"昵称太短", UpdateProfileRequest{Name: str("a")}, "name"},
+		{"昵称太长", UpdateProfileRequest{Name: str("abcdefg")}, "name"},
+		{"昵称去掉首尾空格后太短", UpdateProfileRequest{Name: str("  a  ")}, "name"},
+		{"性别", UpdateProfileRequest{Gender: str(models.GenderFemale)}, ""},
+		{"无效的性别", UpdateProfileRequest{Gender: str("unknown")}, "gender"},
+		{"生日", UpdateProfileRequest{Birthdate: str("2000-01-01")}, ""},
+		{"生日格式错误", UpdateProfileRequest{Birthdate: str("2000/01/01")}, "birthdate"},
+		{"正好成年", UpdateProfileRequest{Birthdate: str("2006-03-01")}, ""},
+		{"未成年", UpdateProfileRequest{Birthdate: str("2006-03-02")}, "birthdate"},
+		{"生日过早", UpdateProfileRequest{Birthdate: str("1900-01-01")}, "birthdate"},
+		{"学校", UpdateProfileRequest{University: str("North University")}, ""},
+		{"学校为空", UpdateProfileRequest{University: str("   ")}, "university"},
+		{"可选的专业", UpdateProfileRequest{Major: str("数学")}, ""},
+		{"清空专业", UpdateProfileRequest{Major: str("")}, ""},
+		{"不支持的专业", UpdateProfileRequest{Major: str("历史")}, "major"},
+		{"兴趣标签", UpdateProfileRequest{Interests: list("篮球", "音乐")}, ""},
+		{"兴趣标签过多", UpdateProfileRequest{Interests: list("篮球", "音乐", "电影")}, "interests"},
+		{"空的兴趣标签", UpdateProfileRequest{Interests: list("篮球", " ")}, "interests"},
+		{"时区", UpdateProfileRequest{Timezone: str("Europe/London")}, ""},
+		{"无效的时区", UpdateProfileRequest{Timezone: str("Mars/Base")}, "timezone"},
+		{"空时区", UpdateProfileRequest{Timezone: str("")}, "timezone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.validateProfile(&tt.req, now)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("期望通过，实际 %v", err)
+				}
+				return
+			}
+			fieldErr, ok := err.(*ProfileFieldError)
+			if !ok || fieldErr.Field != tt.field {
+				t.Fatalf("期望 %s 字段错误，实际 %v", tt.field, err)
+			}
+		})
+	}
+
+	// 通过校验的字段会被规范化
+	req := UpdateProfileRequest{Name: str("  小明  "), University: str(" North University ")}
+	if err := s.validateProfile(&req, now); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if *req.Name != "小明" || *req.University != "North University" {
+		t.Fatalf("应去掉首尾空格，实际 %q %q", *req.Name, *req.University)
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Storage 文件存储接口
+type Storage interface {
+	// Save 保存文件，返回可以公开访问的 URL
+	Save(name string, data []byte) (string, error)
+	// Read 读取 Save 返回的 URL 对应的文件
+	Read(url string) ([]byte, error)
+	// Delete 删除 Save 返回的 URL 对应的文件，文件不存在时不报错
+	Delete(url string) error
+}
+
+// localStorage 本地磁盘存储，文件通过静态路由对外提供
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage 创建本地磁盘存储
+func NewLocalStorage(dir, baseURL string) (Storage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &localStorage{dir: dir, baseURL: baseURL}, nil
+}
+
+// Save 写入文件，name 中的目录部分会被忽略
+func (s *localStorage) Save(name string, data []byte) (string, error) {
+	name = filepath.Base(name)
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o644); err != nil {
+		return "", err
+	}
+	return path.Join(s.baseURL, name), nil
+}
+
+// Read 按 URL 读取文件，不属于该存储的 URL 返回错误
+func (s *localStorage) Read(url string) ([]byte, error) {
+	filePath, err := s.resolve(url)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filePath)
+}
+
+// Delete 按 URL 删除文件
+func (s *localStorage) Delete(url string) error {
+	filePath, err := s.resolve(url)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// resolve 将 URL 转换为本地文件路径
+func (s *localStorage) resolve(url string) (string, error) {
+	name, ok := strings.CutPrefix(url, strings.TrimSuffix(s.baseURL, "/")+"/")
+	if !ok || name != filepath.Base(name) {
+		return "", fmt.Errorf("文件不属于该存储: %s", url)
+	}
+	return filepath.Join(s.dir, name), nil
+}
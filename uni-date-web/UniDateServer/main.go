@@ -1,18 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/ShijieLu222/uni-date-server/api/controllers"
-	"github.com/ShijieLu222/uni-date-server/api/routes"
 	"github.com/ShijieLu222/uni-date-server/config"
-	"github.com/ShijieLu222/uni-date-server/internal/repositories"
-	"github.com/ShijieLu222/uni-date-server/internal/repositories/db"
-	"github.com/ShijieLu222/uni-date-server/internal/services"
-	"github.com/gin-gonic/gin"
 )
 
+// 收到退出信号后等待进行中请求完成的时间
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	// 加载配置
 	cfg, err := config.LoadConfig()
@@ -20,36 +24,62 @@ func main() {
 		log.Fatalf("加载配置失败: %v", err)
 	}
 
-	// 初始化数据库
-	_, err = db.InitDB(cfg)
+	// 初始化应用容器
+	app, err := NewApp(cfg)
 	if err != nil {
-		log.Fatalf("数据库连接失败: %v", err)
+		log.Fatalf("%v", err)
 	}
+	defer app.Close()
 
-	// 注释掉自动迁移，因为数据库表已经手动创建
-	// if err := db.AutoMigrate(database); err != nil {
-	// 	log.Fatalf("数据库迁移失败: %v", err)
-	// }
-
-	// 初始化存储库
-	userRepo := repositories.NewUserRepository()
-
-	// 初始化服务
-	userService := services.NewUserService(userRepo, cfg)
-
-	// 初始化控制器
-	userController := controllers.NewUserController(userService)
+	// migrate 子命令只执行迁移，不启动服务
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if app.Migrator == nil {
+			app.Close()
+			log.Fatalf("当前数据库驱动 %s 不需要执行迁移", cfg.Database.Driver)
+		}
+		if err := runMigrate(app.Migrator, os.Args[2:]); err != nil {
+			app.Close()
+			log.Fatalf("数据库迁移失败: %v", err)
+		}
+		return
+	}
 
-	// 设置 Gin 路由
-	router := gin.Default()
+	// 数据库结构与程序不一致时拒绝启动，内存存储没有结构版本
+	if app.Migrator != nil {
+		if err := app.Migrator.CheckVersion(); err != nil {
+			app.Close()
+			log.Fatalf("数据库结构版本检查失败: %v", err)
+		}
+	}
 
-	// 配置路由
-	routes.SetupRoutes(router, userController, cfg)
+	if err := app.Build(); err != nil {
+		app.Close()
+		log.Fatalf("%v", err)
+	}
 
 	// 启动服务器
 	serverAddr := fmt.Sprintf(":%s", cfg.Server.Port)
-	fmt.Printf("服务器运行在 http://localhost%s\n", serverAddr)
-	if err := router.Run(serverAddr); err != nil {
-		log.Fatalf("启动服务器失败: %v", err)
+	server := &http.Server{
+		Addr:    serverAddr,
+		Handler: app.Router,
+	}
+	// Shutdown 不会中断 SSE 等长连接，开始关闭时主动通知它们退出
+	server.RegisterOnShutdown(app.Shutdown)
+	go func() {
+		fmt.Printf("服务器运行在 http://localhost%s\n", serverAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("启动服务器失败: %v", err)
+		}
+	}()
+
+	// 收到退出信号后停止接收新请求，等待进行中的请求完成后释放资源
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("关闭服务器失败: %v", err)
 	}
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ShijieLu222/uni-date-server/internal/repositories/db"
+)
+
+// runMigrate 执行 migrate 子命令
+//
+//	migrate up        执行所有未执行的迁移
+//	migrate down [n]  回滚最近执行的 n 个迁移，默认 1 个
+//	migrate status    查看每个迁移的执行状态
+func runMigrate(migrator db.Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New("用法: migrate up|down [n]|status")
+	}
+
+	switch args[0] {
+	case "up":
+		migrations, err := migrator.Up()
+		for _, migration := range migrations {
+			fmt.Printf("已执行 %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(migrations) == 0 {
+			fmt.Println("数据库结构已是最新")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("无效的回滚数量: %s", args[1])
+			}
+			steps = n
+		}
+		migrations, err := migrator.Down(steps)
+		for _, migration := range migrations {
+			fmt.Printf("已回滚 %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(migrations) == 0 {
+			fmt.Println("没有可回滚的迁移")
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "未执行"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, applied)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("未知的迁移命令: %s", args[0])
+	}
+}
@@ -11,13 +11,18 @@ fi
 # 检查是否存在数据库 - 使用本地用户ken
 psql -U ken -c "SELECT 1 FROM pg_database WHERE datname = 'uni-date'" | grep -q 1
 if [ $? -ne 0 ]; then
-  echo "初始化数据库..."
-  # 跳过初始化，因为我们已经手动设置了数据库
-  # psql -U ken -f ./scripts/init_db.sql
-  echo "请确保数据库'uni-date'已经创建，并且表结构已经正确设置"
+  echo "请先创建数据库'uni-date'"
+  exit 1
 fi
 
-# 编译并运行服务器
-echo "编译并启动服务器..."
+# 编译服务器
+echo "编译服务器..."
 go build -o ./bin/server
+
+# 执行数据库迁移，表结构由 internal/repositories/db/migrations 维护
+echo "执行数据库迁移..."
+./bin/server migrate up || exit 1
+
+# 启动服务器
+echo "启动服务器..."
 ./bin/server 
\ No newline at end of file
@@ -17,6 +17,11 @@ export interface User {
     };
     isVerified: boolean;
     isVIP: boolean;
+    timezone: string;
+    role: 'user' | 'admin';
+    status: 'active' | 'suspended' | 'banned';
+    statusReason?: string;
+    suspendedUntil?: Date;
     createdAt: Date;
     updatedAt: Date;
 }
@@ -74,17 +79,53 @@ export interface Notification {
     createdAt: Date;
   }
 
-//   // VIP订阅
-// interface Subscription {
-//     id: string;
-//     userId: string;
-//     plan: 'monthly' | 'yearly' | 'lifetime';
-//     startDate: Date;
-//     endDate?: Date;
-//     isActive: boolean;
-//     paymentMethod: string;
-//     amount: number;
-//   }
+  // VIP订阅
+export interface Subscription {
+    id: string;
+    userId: string;
+    plan: 'monthly' | 'yearly' | 'lifetime';
+    status: 'active' | 'expired' | 'canceled';
+    startDate: Date;
+    endDate?: Date;
+    cancelAtPeriodEnd: boolean;
+    paymentMethod: string;
+    amount: number;
+    currency: string;
+    createdAt: Date;
+    updatedAt: Date;
+  }
+
+// 会员权益，limit/remaining 为 -1 表示不限
+export interface FeatureQuota {
+    limit: number;
+    used: number;
+    remaining: number;
+}
+
+export interface Entitlements {
+    tier: 'FREE' | 'VIP';
+    quotas: Record<'like' | 'rewind' | 'boost', FeatureQuota>;
+    capabilities: Record<'see_who_liked_me', boolean>;
+    resetAt: Date;
+}
+
+// 资料加速，impressions/likes 为加速期间获得的曝光和喜欢
+export interface Boost {
+    id: string;
+    userId: string;
+    source: 'vip' | 'purchase';
+    startsAt?: Date;
+    endsAt?: Date;
+    impressions: number;
+    likes: number;
+    createdAt: Date;
+}
+
+export interface BoostStatus {
+    active?: Boost;
+    credits: number;
+    vipRemaining: number;
+}
 
 export interface LoginResponse {
     user: User;